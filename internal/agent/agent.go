@@ -1,7 +1,11 @@
 // internal/agent/agent.go
 package agent
 
-import "time"
+import (
+	"time"
+
+	"github.com/sky-xo/june/internal/usage"
+)
 
 const (
 	activeThreshold = 20 * time.Second
@@ -15,6 +19,14 @@ const (
 	SourceGemini = "gemini"
 )
 
+// Lifecycle status of a spawned agent. Claude agents have no status.
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusKilled    = "killed"
+)
+
 // Agent represents any AI coding agent (Claude, Codex, etc.)
 type Agent struct {
 	// Identity
@@ -31,7 +43,19 @@ type Agent struct {
 
 	// Activity
 	LastActivity time.Time
-	PID          int // Process ID if running, 0 otherwise
+	PID          int    // Process ID if running, 0 otherwise
+	Status       string // Lifecycle status for spawned agents, empty if unknown
+
+	// Spawn settings (spawned agents only)
+	Task    string // Prompt the agent was spawned with
+	Options string // Spawn flags, e.g. "model=o3 sandbox=read-only"
+	RetryOf string // Name of the agent this one retries, empty if not a retry
+
+	// Token usage, filled in by callers that read transcripts
+	Usage usage.Summary
+
+	// Dimmed is set when a configured rule de-emphasizes the agent
+	Dimmed bool
 }
 
 // DisplayName returns the best name for UI display.
@@ -43,11 +67,20 @@ func (a Agent) DisplayName() string {
 	return a.ID
 }
 
-// IsActive returns true if the agent was recently modified.
+// IsActive returns true if the agent is running. Agents without a recorded
+// status fall back to whether they were recently modified.
 func (a Agent) IsActive() bool {
+	if a.Status != "" {
+		return a.Status == StatusRunning
+	}
 	return time.Since(a.LastActivity) < activeThreshold
 }
 
+// IsFailed returns true if the agent failed or was killed.
+func (a Agent) IsFailed() bool {
+	return a.Status == StatusFailed || a.Status == StatusKilled
+}
+
 // IsRecent returns true if the agent was modified within 2 hours.
 func (a Agent) IsRecent() bool {
 	return time.Since(a.LastActivity) < recentThreshold
@@ -55,8 +88,18 @@ func (a Agent) IsRecent() bool {
 
 // Channel represents a group of agents from a branch/worktree.
 type Channel struct {
-	Name   string  // Display name like "june:main"
-	Agents []Agent // Mixed Claude and Codex agents
+	Name    string  // Display name like "june:main"
+	Project string  // Path of the repository the channel belongs to
+	Agents  []Agent // Mixed Claude and Codex agents
+}
+
+// Usage returns the combined token usage of the channel's agents.
+func (c Channel) Usage() usage.Summary {
+	var total usage.Summary
+	for _, a := range c.Agents {
+		total = total.Add(a.Usage)
+	}
+	return total
 }
 
 // HasRecentActivity returns true if any agent is active or recent.
@@ -45,6 +45,38 @@ func TestAgent_IsActive(t *testing.T) {
 	}
 }
 
+func TestAgent_IsActive_UsesStatusWhenSet(t *testing.T) {
+	// Running agents are active even if they haven't written recently
+	running := Agent{Status: StatusRunning, LastActivity: time.Now().Add(-10 * time.Minute)}
+	if !running.IsActive() {
+		t.Error("running agent should be active regardless of mtime")
+	}
+
+	// Finished agents are inactive even if they wrote a moment ago
+	finished := Agent{Status: StatusSucceeded, LastActivity: time.Now()}
+	if finished.IsActive() {
+		t.Error("succeeded agent should not be active")
+	}
+}
+
+func TestAgent_IsFailed(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"", false},
+		{StatusRunning, false},
+		{StatusSucceeded, false},
+		{StatusFailed, true},
+		{StatusKilled, true},
+	}
+	for _, tt := range tests {
+		if got := (Agent{Status: tt.status}).IsFailed(); got != tt.want {
+			t.Errorf("IsFailed() with status %q = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
 func TestAgent_IsRecent(t *testing.T) {
 	recent := Agent{LastActivity: time.Now().Add(-1 * time.Hour)}
 	if !recent.IsRecent() {
@@ -8,15 +8,13 @@ import (
 	"strings"
 
 	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/config"
 	"github.com/sky-xo/june/internal/db"
 )
 
 // FindRelatedProjectDirs finds all Claude project directories that share
 // the same base project path (main repo + worktrees).
 func FindRelatedProjectDirs(claudeProjectsDir, basePath string) []string {
-	// Convert base path to Claude's dash format
-	basePrefix := strings.ReplaceAll(basePath, "/", "-")
-
 	entries, err := os.ReadDir(claudeProjectsDir)
 	if err != nil {
 		return nil
@@ -27,15 +25,22 @@ func FindRelatedProjectDirs(claudeProjectsDir, basePath string) []string {
 		if !e.IsDir() {
 			continue
 		}
-		name := e.Name()
-		// Match exact base or base with worktree suffix
-		if name == basePrefix || strings.HasPrefix(name, basePrefix+"-") {
-			related = append(related, filepath.Join(claudeProjectsDir, name))
+		if IsRelatedProjectDir(e.Name(), basePath) {
+			related = append(related, filepath.Join(claudeProjectsDir, e.Name()))
 		}
 	}
 	return related
 }
 
+// IsRelatedProjectDir reports whether a Claude project directory name belongs
+// to basePath: the main repo or one of its worktrees.
+func IsRelatedProjectDir(name, basePath string) bool {
+	// Convert base path to Claude's dash format
+	basePrefix := PathToProjectDir(basePath)
+	// Match exact base or base with worktree suffix
+	return name == basePrefix || strings.HasPrefix(name, basePrefix+"-")
+}
+
 // ExtractChannelName creates a display name like "june:main" or "june:feature".
 // baseDir is the main repo's Claude dir name (e.g., "-Users-test-code-june")
 // projectDir is the current dir name (e.g., "-Users-test-code-june--worktrees-channels")
@@ -72,14 +77,15 @@ func ExtractChannelName(baseDir, projectDir, repoName string) string {
 }
 
 // ScanChannels scans Claude project directories and merges Codex agents.
-// It returns unified channels containing both Claude and Codex agents.
-func ScanChannels(claudeProjectsDir, basePath, repoName string, codexDB *db.DB) ([]agent.Channel, error) {
+// It returns unified channels containing both Claude and Codex agents, less
+// those rules hide.
+func ScanChannels(claudeProjectsDir, basePath, repoName string, codexDB *db.DB, rules config.Rules) ([]agent.Channel, error) {
 	relatedDirs := FindRelatedProjectDirs(claudeProjectsDir, basePath)
 
 	// Map branch -> agents
 	channelMap := make(map[string][]agent.Agent)
 
-	baseDir := strings.ReplaceAll(basePath, "/", "-")
+	baseDir := PathToProjectDir(basePath)
 
 	// 1. Scan Claude agents
 	for _, dir := range relatedDirs {
@@ -114,13 +120,127 @@ func ScanChannels(claudeProjectsDir, basePath, repoName string, codexDB *db.DB)
 	}
 
 	// 3. Build and sort channels
+	transcriptFacts.prune(channelMap)
+	return buildChannels(channelMap, basePath, rules), nil
+}
+
+// ScanAllChannels is ScanChannels for every project: each directory under
+// claudeProjectsDir and each repository spawned agents ran in. Channels are
+// grouped by project, projects with recent activity first, then by name.
+func ScanAllChannels(claudeProjectsDir string, codexDB *db.DB, rules config.Rules) ([]agent.Channel, error) {
+	type project struct {
+		path       string
+		channelMap map[string][]agent.Agent
+	}
+	projects := make(map[string]*project) // By the main repo's project dir name
+	projectFor := func(dirName, path string) *project {
+		p := projects[dirName]
+		if p == nil {
+			p = &project{path: path, channelMap: make(map[string][]agent.Agent)}
+			projects[dirName] = p
+		}
+		return p
+	}
+
+	// Spawned agents record their repository's path; Claude's directory
+	// names have to be decoded
+	var codexAgents []db.Agent
+	if codexDB != nil {
+		codexAgents, _ = codexDB.ListAgents()
+	}
+	for _, ca := range codexAgents {
+		if ca.RepoPath != "" {
+			projectFor(PathToProjectDir(ca.RepoPath), ca.RepoPath)
+		}
+	}
+
+	entries, err := os.ReadDir(claudeProjectsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		baseDir := ProjectDirBase(e.Name())
+		p := projects[baseDir]
+		if p == nil {
+			p = projectFor(baseDir, DecodeProjectDir(baseDir))
+		}
+		repoName := filepath.Base(p.path)
+		channelName := ExtractChannelName(baseDir, e.Name(), repoName)
+		branch := strings.TrimPrefix(channelName, repoName+":")
+
+		claudeAgents, err := ScanAgents(filepath.Join(claudeProjectsDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		for _, ca := range claudeAgents {
+			p.channelMap[channelName] = append(p.channelMap[channelName], ca.ToUnified(p.path, branch))
+		}
+	}
+
+	for _, ca := range codexAgents {
+		if ca.RepoPath == "" {
+			continue
+		}
+		p := projects[PathToProjectDir(ca.RepoPath)]
+		branch := ca.Branch
+		if branch == "" {
+			branch = "main"
+		}
+		channelName := filepath.Base(p.path) + ":" + branch
+		p.channelMap[channelName] = append(p.channelMap[channelName], ca.ToUnified())
+	}
+
+	// Sort projects like channels, then list each one's channels
+	type group struct {
+		path     string
+		channels []agent.Channel
+		recent   bool
+	}
+	var groups []group
+	var channelMaps []map[string][]agent.Agent
+	for _, p := range projects {
+		channelMaps = append(channelMaps, p.channelMap)
+	}
+	transcriptFacts.prune(channelMaps...)
+	for _, p := range projects {
+		g := group{path: p.path, channels: buildChannels(p.channelMap, p.path, rules)}
+		if len(g.channels) == 0 {
+			continue
+		}
+		g.recent = g.channels[0].HasRecentActivity() // Sorted first if any is
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].recent != groups[j].recent {
+			return groups[i].recent
+		}
+		return groups[i].path < groups[j].path
+	})
+
+	var channels []agent.Channel
+	for _, g := range groups {
+		channels = append(channels, g.channels...)
+	}
+	return channels, nil
+}
+
+// buildChannels turns a map of channel name -> agents into channels of
+// project, sorted with recent activity first, then by name. Agents rules
+// hide are left out, and so are channels left empty.
+func buildChannels(channelMap map[string][]agent.Agent, project string, rules config.Rules) []agent.Channel {
 	var channels []agent.Channel
 	for name, agents := range channelMap {
+		if agents = applyRules(agents, rules); len(agents) == 0 {
+			continue
+		}
 		// Sort agents within channel by LastActivity (most recent first)
 		sort.Slice(agents, func(i, j int) bool {
 			return agents[i].LastActivity.After(agents[j].LastActivity)
 		})
-		channels = append(channels, agent.Channel{Name: name, Agents: agents})
+		channels = append(channels, agent.Channel{Name: name, Project: project, Agents: agents})
 	}
 
 	// Sort: recent activity first, then alphabetically
@@ -133,5 +253,5 @@ func ScanChannels(claudeProjectsDir, basePath, repoName string, codexDB *db.DB)
 		return channels[i].Name < channels[j].Name
 	})
 
-	return channels, nil
+	return channels
 }
@@ -99,7 +99,7 @@ func TestScanChannels(t *testing.T) {
 	futureTime := time.Now().Add(time.Hour)
 	os.Chtimes(filepath.Join(worktreeDir, "agent-def456.jsonl"), futureTime, futureTime)
 
-	channels, err := ScanChannels(claudeProjects, "/Users/test/code/myproject", "myproject", nil)
+	channels, err := ScanChannels(claudeProjects, "/Users/test/code/myproject", "myproject", nil, nil)
 	if err != nil {
 		t.Fatalf("ScanChannels failed: %v", err)
 	}
@@ -193,7 +193,7 @@ func TestScanChannels_SortsByActivityThenAlphabetical(t *testing.T) {
 		os.Chtimes(agentFile, d.modTime, d.modTime)
 	}
 
-	channels, err := ScanChannels(claudeProjects, "/Users/test/code/proj", "proj", nil)
+	channels, err := ScanChannels(claudeProjects, "/Users/test/code/proj", "proj", nil, nil)
 	if err != nil {
 		t.Fatalf("ScanChannels failed: %v", err)
 	}
@@ -235,7 +235,7 @@ func TestScanChannels_Integration(t *testing.T) {
 		}
 	}
 
-	channels, err := ScanChannels(claudeProjects, "/Users/test/code/june", "june", nil)
+	channels, err := ScanChannels(claudeProjects, "/Users/test/code/june", "june", nil, nil)
 	if err != nil {
 		t.Fatalf("ScanChannels failed: %v", err)
 	}
@@ -303,7 +303,7 @@ func TestScanChannels_MergesCodexAgents(t *testing.T) {
 		t.Fatalf("failed to create agent: %v", err)
 	}
 
-	channels, err := ScanChannels(claudeProjects, "/Users/test/code/myproject", "myproject", testDB)
+	channels, err := ScanChannels(claudeProjects, "/Users/test/code/myproject", "myproject", testDB, nil)
 	if err != nil {
 		t.Fatalf("ScanChannels failed: %v", err)
 	}
@@ -363,3 +363,107 @@ func TestScanChannels_MergesCodexAgents(t *testing.T) {
 		t.Errorf("expected feature agent to be codex, got %s", featureChannel.Agents[0].Source)
 	}
 }
+
+func TestScanAllChannels(t *testing.T) {
+	tmpDir := t.TempDir()
+	claudeProjects := filepath.Join(tmpDir, ".claude", "projects")
+
+	// Two projects with Claude agents, one with a worktree, and a third with
+	// only a spawned agent, whose repo path is known from the DB
+	for _, dir := range []string{
+		"-Users-test-code-api",
+		"-Users-test-code-api--worktrees-fix-auth",
+		"-Users-test-code-tools",
+	} {
+		os.MkdirAll(filepath.Join(claudeProjects, dir), 0755)
+		os.WriteFile(filepath.Join(claudeProjects, dir, "agent-"+dir[len(dir)-4:]+".jsonl"), []byte(`{"type":"user","message":{"role":"user","content":"Task"}}`+"\n"), 0644)
+	}
+	// Projects without recent activity sort last
+	oldTime := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(filepath.Join(claudeProjects, "-Users-test-code-api", "agent--api.jsonl"), oldTime, oldTime)
+	os.Chtimes(filepath.Join(claudeProjects, "-Users-test-code-api--worktrees-fix-auth", "agent-auth.jsonl"), oldTime, oldTime)
+
+	testDB, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer testDB.Close()
+	if err := testDB.CreateAgent(db.Agent{
+		Name:        "codex-web",
+		ULID:        "codex789",
+		SessionFile: "/tmp/session.jsonl",
+		RepoPath:    "/Users/test/code/my-web",
+		Branch:      "feature",
+	}); err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	channels, err := ScanAllChannels(claudeProjects, testDB, nil)
+	if err != nil {
+		t.Fatalf("ScanAllChannels failed: %v", err)
+	}
+
+	type row struct{ project, name string }
+	var got []row
+	for _, ch := range channels {
+		got = append(got, row{ch.Project, ch.Name})
+	}
+	want := []row{
+		{"/Users/test/code/my-web", "my-web:feature"},
+		{"/Users/test/code/tools", "tools:main"},
+		{"/Users/test/code/api", "api:fix-auth"},
+		{"/Users/test/code/api", "api:main"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("channels = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("channel %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if a := channels[2].Agents[0]; a.RepoPath != "/Users/test/code/api" || a.Branch != "fix-auth" {
+		t.Errorf("worktree agent repo/branch = %q/%q, want /Users/test/code/api/fix-auth", a.RepoPath, a.Branch)
+	}
+}
+
+func TestScanAllChannels_DottedRepoPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	claudeProjects := filepath.Join(tmpDir, ".claude", "projects")
+
+	// Claude encodes the dot in foo.js as a dash, like the slashes
+	repo := "/Users/test/src/foo.js"
+	dir := filepath.Join(claudeProjects, "-Users-test-src-foo-js")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "agent-a1b2c3.jsonl"), []byte(`{"type":"user","message":{"role":"user","content":"Task"}}`+"\n"), 0644)
+
+	testDB, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer testDB.Close()
+	if err := testDB.CreateAgent(db.Agent{
+		Name:        "codex-lint",
+		ULID:        "codex123",
+		SessionFile: "/tmp/session.jsonl",
+		RepoPath:    repo,
+		Branch:      "main",
+	}); err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	channels, err := ScanAllChannels(claudeProjects, testDB, nil)
+	if err != nil {
+		t.Fatalf("ScanAllChannels failed: %v", err)
+	}
+	if len(channels) != 1 {
+		for _, ch := range channels {
+			t.Logf("channel %s (%s)", ch.Name, ch.Project)
+		}
+		t.Fatalf("got %d channels, want the spawned and Claude agents in one", len(channels))
+	}
+	if ch := channels[0]; ch.Project != repo || len(ch.Agents) != 2 {
+		t.Errorf("channel = %s with %d agents, want %s with 2", ch.Project, len(ch.Agents), repo)
+	}
+}
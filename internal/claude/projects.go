@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // ClaudeProjectsDir returns ~/.claude/projects
@@ -13,13 +14,101 @@ func ClaudeProjectsDir() string {
 	return filepath.Join(home, ".claude", "projects")
 }
 
-// PathToProjectDir converts an absolute path to Claude's directory format.
-// Example: /Users/glowy/code/otto -> -Users-glowy-code-otto
+// PathToProjectDir converts an absolute path to Claude's directory format,
+// which replaces every character other than an ASCII letter or digit with "-".
+// Example: /Users/glowy/code/foo.js -> -Users-glowy-code-foo-js
 func PathToProjectDir(absPath string) string {
-	return strings.ReplaceAll(absPath, "/", "-")
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, absPath)
 }
 
 // ProjectDir returns the full path to a project's Claude directory.
 func ProjectDir(absPath string) string {
 	return filepath.Join(ClaudeProjectsDir(), PathToProjectDir(absPath))
 }
+
+// DecodeProjectDir recovers the path a project directory name encodes.
+// Claude replaces "/", "." and other punctuation with "-", which is
+// ambiguous, so the directories that exist are used to tell the cases
+// apart: "-Users-me-code-my-app" is /Users/me/code/my-app if that exists,
+// and "-Users-me-src-foo-js" is /Users/me/src/foo.js. For a path that no
+// longer exists, every dash is taken as a separator. Paths found on disk
+// are cached, as channel scans decode every project directory.
+func DecodeProjectDir(name string) string {
+	decodedDirs.Lock()
+	path, ok := decodedDirs.paths[name]
+	decodedDirs.Unlock()
+	if ok {
+		return path
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(name, "-"), "-")
+	if path, ok := probeProjectPath("/", tokens); ok {
+		decodedDirs.Lock()
+		decodedDirs.paths[name] = path
+		decodedDirs.Unlock()
+		return path
+	}
+	var parts []string
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] == "" && i+1 < len(tokens) {
+			i++
+			parts = append(parts, "."+tokens[i]) // "--x" is "/.x"
+		} else if tokens[i] != "" {
+			parts = append(parts, tokens[i])
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// decodedDirs holds the paths DecodeProjectDir found on disk, by project
+// directory name.
+var decodedDirs = struct {
+	sync.Mutex
+	paths map[string]string
+}{paths: make(map[string]string)}
+
+// probeProjectPath finds an existing directory under dir whose path encodes
+// as tokens, preferring longer names for each component. A component
+// matches the tokens its name encodes to, so "foo.js" and "my-app" both
+// match two tokens.
+func probeProjectPath(dir string, tokens []string) (string, bool) {
+	if len(tokens) == 0 {
+		return dir, true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	for n := len(tokens); n >= 1; n-- {
+		encoded := strings.Join(tokens[:n], "-")
+		for _, e := range entries {
+			if PathToProjectDir(e.Name()) != encoded {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				continue // Also following symlinks
+			}
+			if found, ok := probeProjectPath(path, tokens[n:]); ok {
+				return found, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ProjectDirBase returns the name of the project directory a worktree's
+// directory belongs to, e.g. "-Users-me-code-june" for
+// "-Users-me-code-june--worktrees-feature". Other names are returned as is.
+func ProjectDirBase(name string) string {
+	idx := strings.Index(name, "-worktrees-")
+	if idx <= 0 {
+		return name
+	}
+	return strings.TrimSuffix(name[:idx], "-")
+}
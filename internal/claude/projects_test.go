@@ -2,6 +2,8 @@
 package claude
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -13,6 +15,9 @@ func TestPathToProjectDir(t *testing.T) {
 	}{
 		{"/Users/glowy/code/june", "-Users-glowy-code-june"},
 		{"/home/user/project", "-home-user-project"},
+		{"/Users/me/src/foo.js", "-Users-me-src-foo-js"},
+		{"/Users/me/code/my_app", "-Users-me-code-my-app"},
+		{"/Users/me/.config", "-Users-me--config"},
 	}
 
 	for _, tt := range tests {
@@ -33,3 +38,56 @@ func TestClaudeProjectsDir(t *testing.T) {
 		t.Errorf("ClaudeProjectsDir() = %q, should end with .claude/projects", dir)
 	}
 }
+
+func TestDecodeProjectDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"code/my-app", "code/my", ".config/tool", "src/foo.js", "src/foo_bar"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		// The longest existing name wins over a shorter one that also exists
+		{filepath.Join(tmpDir, "code/my-app"), filepath.Join(tmpDir, "code/my-app")},
+		{filepath.Join(tmpDir, ".config/tool"), filepath.Join(tmpDir, ".config/tool")},
+		{filepath.Join(tmpDir, "code/my"), filepath.Join(tmpDir, "code/my")},
+		// Dots and other punctuation inside a name are recovered too
+		{filepath.Join(tmpDir, "src/foo.js"), filepath.Join(tmpDir, "src/foo.js")},
+		{filepath.Join(tmpDir, "src/foo_bar"), filepath.Join(tmpDir, "src/foo_bar")},
+	}
+	// Claude also replaces dots, so hidden directories start with "--"
+	for _, tt := range tests {
+		name := PathToProjectDir(tt.path)
+		if got := DecodeProjectDir(name); got != tt.want {
+			t.Errorf("DecodeProjectDir(%q) = %q, want %q", name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeProjectDir_MissingPath(t *testing.T) {
+	// Without the directories to go by, every dash is a separator
+	got := DecodeProjectDir("-nonexistent-june-test-my-app--hidden")
+	if want := "/nonexistent/june/test/my/app/.hidden"; got != want {
+		t.Errorf("DecodeProjectDir() = %q, want %q", got, want)
+	}
+}
+
+func TestProjectDirBase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"-Users-test-code-june", "-Users-test-code-june"},
+		{"-Users-test-code-june--worktrees-select-mode", "-Users-test-code-june"},
+		{"-Users-test-code-june--worktrees--worktrees-channels", "-Users-test-code-june"},
+	}
+	for _, tt := range tests {
+		if got := ProjectDirBase(tt.name); got != tt.want {
+			t.Errorf("ProjectDirBase(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
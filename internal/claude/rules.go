@@ -0,0 +1,175 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/codex"
+	"github.com/sky-xo/june/internal/config"
+	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/transcript"
+)
+
+// applyRules drops the agents rules hide and marks the ones they dim.
+func applyRules(agents []agent.Agent, rules config.Rules) []agent.Agent {
+	if len(rules) == 0 {
+		return agents
+	}
+	kept := agents[:0]
+	for _, a := range agents {
+		switch rules.Action(a, ruleTranscript{a}) {
+		case config.ActionHide:
+			continue
+		case config.ActionDim:
+			a.Dimmed = true
+		}
+		kept = append(kept, a)
+	}
+	return kept
+}
+
+// ruleTranscript reads what rules match on from an agent's transcript,
+// through transcriptFacts.
+type ruleTranscript struct {
+	a agent.Agent
+}
+
+func (t ruleTranscript) FirstMessage() string {
+	if t.a.Task != "" {
+		return t.a.Task // Spawned agents record their prompt
+	}
+	return transcriptFacts.firstMessage(t.a.TranscriptPath, lineParser(t.a.Source))
+}
+
+func (t ruleTranscript) Lines() int {
+	return transcriptFacts.lines(t.a.TranscriptPath)
+}
+
+// transcriptFacts caches what rules read from transcripts across scans.
+var transcriptFacts = &factCache{entries: make(map[string]facts)}
+
+// factCache remembers each transcript's first user message, which never
+// changes once written, and its line count as of a size and modification
+// time. It is safe for concurrent use.
+type factCache struct {
+	mu      sync.Mutex
+	entries map[string]facts // By transcript path
+}
+
+type facts struct {
+	firstMessage string
+	searchedSize int64 // Size when a transcript without a first message was last read
+	size         int64
+	modTime      time.Time
+	lines        int
+	counted      bool // lines is valid for size and modTime
+}
+
+func (c *factCache) firstMessage(path string, parse func(line []byte) []transcript.Event) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f := c.entries[path]
+	if f.firstMessage == "" && f.searchedSize != info.Size() {
+		f.firstMessage = readFirstUserMessage(path, parse)
+		f.searchedSize = info.Size()
+		c.entries[path] = f
+	}
+	return f.firstMessage
+}
+
+func (c *factCache) lines(path string) int {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f := c.entries[path]
+	if !f.counted || f.size != info.Size() || !f.modTime.Equal(info.ModTime()) {
+		f.lines = countLines(path)
+		f.size, f.modTime, f.counted = info.Size(), info.ModTime(), true
+		c.entries[path] = f
+	}
+	return f.lines
+}
+
+// prune forgets the transcripts of agents no longer in channelMaps, so the
+// cache doesn't grow as agents come and go.
+func (c *factCache) prune(channelMaps ...map[string][]agent.Agent) {
+	keep := make(map[string]bool)
+	for _, m := range channelMaps {
+		for _, agents := range m {
+			for _, a := range agents {
+				keep[a.TranscriptPath] = true
+			}
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for path := range c.entries {
+		if !keep[path] {
+			delete(c.entries, path)
+		}
+	}
+}
+
+// lineParser returns the transcript line parser of the CLI source agents
+// come from.
+func lineParser(source string) func(line []byte) []transcript.Event {
+	switch source {
+	case agent.SourceCodex:
+		return codex.ParseLine
+	case agent.SourceGemini:
+		return gemini.ParseLine
+	}
+	return ParseLine
+}
+
+// readFirstUserMessage returns the text of the first user message in a
+// transcript, parsing its lines with parse, or "" if it has none yet.
+func readFirstUserMessage(path string, parse func(line []byte) []transcript.Event) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024)
+	for scanner.Scan() {
+		for _, e := range parse(scanner.Bytes()) {
+			if p, ok := e.(transcript.UserPrompt); ok && p.Text != "" {
+				return p.Text
+			}
+		}
+	}
+	return ""
+}
+
+// countLines returns the number of lines in a file.
+func countLines(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	lines := 0
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		lines += bytes.Count(buf[:n], []byte{'\n'})
+		if err != nil { // Including io.EOF
+			return lines
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/config"
+)
+
+func TestScanChannelsAppliesRules(t *testing.T) {
+	claudeProjects := filepath.Join(t.TempDir(), ".claude", "projects")
+	mainDir := filepath.Join(claudeProjects, "-Users-test-code-myproject")
+	warmupDir := filepath.Join(claudeProjects, "-Users-test-code-myproject--worktrees-warm")
+	os.MkdirAll(mainDir, 0755)
+	os.MkdirAll(warmupDir, 0755)
+
+	write := func(dir, name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-time.Hour) // Finished, so length rules apply
+		os.Chtimes(path, old, old)
+	}
+	write(mainDir, "agent-warm1.jsonl", `{"type":"user","message":{"role":"user","content":"Warmup"}}`+"\n"+
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Ready."}]}}`+"\n")
+	write(mainDir, "agent-task1.jsonl", `{"type":"user","message":{"role":"user","content":"Fix the login bug"}}`+"\n")
+	write(mainDir, "agent-lint1.jsonl", `{"type":"user","message":{"role":"user","content":[{"type":"text","text":"Run the linter"}]}}`+"\n")
+	write(warmupDir, "agent-warm2.jsonl", `{"type":"user","message":{"role":"user","content":"Warmup"}}`+"\n")
+
+	rules := append(config.DefaultRules(), config.Rule{Action: config.ActionDim, Source: "claude", MaxLines: 1})
+	if err := rules.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	channels, err := ScanChannels(claudeProjects, "/Users/test/code/myproject", "myproject", nil, rules)
+	if err != nil {
+		t.Fatalf("ScanChannels: %v", err)
+	}
+
+	// The worktree channel only had a warmup agent, so it's gone entirely
+	if len(channels) != 1 || channels[0].Name != "myproject:main" {
+		t.Fatalf("channels = %+v, want only myproject:main", channels)
+	}
+	dimmed := make(map[string]bool)
+	for _, a := range channels[0].Agents {
+		dimmed[a.ID] = a.Dimmed
+	}
+	if _, ok := dimmed["warm1"]; ok || len(dimmed) != 2 {
+		t.Errorf("agents = %v, want task1 and lint1 without the warmup agent", dimmed)
+	}
+	if !dimmed["task1"] || !dimmed["lint1"] {
+		t.Errorf("one-line transcripts should be dimmed: %v", dimmed)
+	}
+}
+
+func TestReadFirstUserMessageSkipsToolResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-a1.jsonl")
+	content := `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}` + "\n" +
+		`{"type":"user","message":{"role":"user","content":"Warmup"}}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFirstUserMessage(path, ParseLine); got != "Warmup" {
+		t.Errorf("readFirstUserMessage = %q, want Warmup", got)
+	}
+}
+
+func TestRuleTranscriptFirstMessage_OtherSources(t *testing.T) {
+	dir := t.TempDir()
+	codexPath := filepath.Join(dir, "rollout.jsonl")
+	if err := os.WriteFile(codexPath, []byte(`{"type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"Review the API"}]}}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	geminiPath := filepath.Join(dir, "gemini.jsonl")
+	if err := os.WriteFile(geminiPath, []byte(`{"type":"init","model":"gemini-2.5-pro"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Spawned agents without a recorded task are matched on their transcript
+	codexAgent := agent.Agent{ID: "01J", Source: agent.SourceCodex, TranscriptPath: codexPath}
+	if got := (ruleTranscript{codexAgent}).FirstMessage(); got != "Review the API" {
+		t.Errorf("codex first message = %q, want Review the API", got)
+	}
+
+	// A transcript without a user message yet is read again once it grows
+	geminiAgent := agent.Agent{ID: "s1", Source: agent.SourceGemini, TranscriptPath: geminiPath}
+	if got := (ruleTranscript{geminiAgent}).FirstMessage(); got != "" {
+		t.Errorf("gemini first message = %q, want none yet", got)
+	}
+	if f := transcriptFacts.entries[geminiPath]; f.searchedSize == 0 {
+		t.Errorf("miss not cached: %+v", f)
+	}
+	f, err := os.OpenFile(geminiPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"type":"message","role":"user","content":"Summarize the docs"}` + "\n")
+	f.Close()
+	if got := (ruleTranscript{geminiAgent}).FirstMessage(); got != "Summarize the docs" {
+		t.Errorf("gemini first message = %q, want Summarize the docs", got)
+	}
+
+	// Transcripts of agents that are gone are forgotten
+	transcriptFacts.prune(map[string][]agent.Agent{"repo:main": {codexAgent}})
+	if _, ok := transcriptFacts.entries[geminiPath]; ok {
+		t.Error("facts of a transcript no longer scanned were kept")
+	}
+	if _, ok := transcriptFacts.entries[codexPath]; !ok {
+		t.Error("facts of a scanned transcript were dropped")
+	}
+}
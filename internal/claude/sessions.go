@@ -0,0 +1,73 @@
+package claude
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSessionNotFound is returned when a spawned agent's session file cannot be found
+var ErrSessionNotFound = errors.New("session file not found")
+
+// ErrInvalidSessionID is returned when a session ID contains invalid characters
+var ErrInvalidSessionID = errors.New("invalid session ID: must not contain path separators or traversal sequences")
+
+// validateSessionID checks that a session ID does not contain path traversal characters.
+func validateSessionID(sessionID string) error {
+	if sessionID == "" || strings.ContainsAny(sessionID, `/\`) || strings.Contains(sessionID, "..") {
+		return ErrInvalidSessionID
+	}
+	return nil
+}
+
+// SessionsDir returns the directory june records spawned Claude agents'
+// output to: ~/.june/claude/sessions.
+func SessionsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".june", "claude", "sessions"), nil
+}
+
+// EnsureSessionsDir creates the sessions directory if needed and returns it.
+func EnsureSessionsDir() (string, error) {
+	dir, err := SessionsDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// FindSessionFile finds a spawned Claude agent's session file by session ID.
+// Looks in ~/.june/claude/sessions/{session_id}.jsonl
+func FindSessionFile(sessionID string) (string, error) {
+	sessionFile, err := SessionFilePath(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(sessionFile); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrSessionNotFound
+		}
+		return "", err
+	}
+	return sessionFile, nil
+}
+
+// SessionFilePath returns the path where a session file should be written.
+func SessionFilePath(sessionID string) (string, error) {
+	if err := validateSessionID(sessionID); err != nil {
+		return "", err
+	}
+
+	sessionsDir, err := SessionsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(sessionsDir, sessionID+".jsonl"), nil
+}
@@ -0,0 +1,48 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindSessionFile(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	sessionsDir, err := EnsureSessionsDir()
+	if err != nil {
+		t.Fatalf("EnsureSessionsDir failed: %v", err)
+	}
+	if want := filepath.Join(tmpHome, ".june", "claude", "sessions"); sessionsDir != want {
+		t.Errorf("sessionsDir = %q, want %q", sessionsDir, want)
+	}
+
+	sessionID := "8b6238bf-8332-4fc7-ba9a-2f3323119bb2"
+	if _, err := FindSessionFile(sessionID); err != ErrSessionNotFound {
+		t.Errorf("err = %v, want ErrSessionNotFound", err)
+	}
+
+	sessionFile := filepath.Join(sessionsDir, sessionID+".jsonl")
+	if err := os.WriteFile(sessionFile, []byte(`{"type":"system","subtype":"init"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := FindSessionFile(sessionID)
+	if err != nil {
+		t.Fatalf("FindSessionFile failed: %v", err)
+	}
+	if found != sessionFile {
+		t.Errorf("found = %q, want %q", found, sessionFile)
+	}
+}
+
+func TestSessionFilePathRejectsTraversal(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, id := range []string{"", "../etc/passwd", "a/b", `a\b`, "..", "foo..bar"} {
+		if _, err := SessionFilePath(id); err != ErrInvalidSessionID {
+			t.Errorf("SessionFilePath(%q) err = %v, want ErrInvalidSessionID", id, err)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
 )
 
 // Entry represents a single line in an agent transcript.
@@ -19,9 +22,20 @@ type Entry struct {
 
 // Message represents the message content.
 type Message struct {
+	ID         string      `json:"id"` // Assistant messages only
 	Role       string      `json:"role"`
+	Model      string      `json:"model"`
 	Content    interface{} `json:"content"` // string or []ContentBlock
 	StopReason *string     `json:"stop_reason"`
+	Usage      *Usage      `json:"usage"` // Assistant messages only
+}
+
+// Usage is the token usage the API reported for an assistant message.
+type Usage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
 }
 
 // ContentBlock represents a content block in assistant messages.
@@ -51,6 +65,24 @@ func (e Entry) TextContent() string {
 	return ""
 }
 
+// ThinkingContent returns the text of the entry's thinking blocks, which
+// TextContent skips.
+func (e Entry) ThinkingContent() string {
+	blocks, ok := e.Message.Content.([]interface{})
+	if !ok {
+		return ""
+	}
+	var parts []string
+	for _, block := range blocks {
+		if m, ok := block.(map[string]interface{}); ok && m["type"] == "thinking" {
+			if text, ok := m["thinking"].(string); ok && text != "" {
+				parts = append(parts, text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
 // ToolName returns the tool name if this is a tool_use entry.
 func (e Entry) ToolName() string {
 	if blocks, ok := e.Message.Content.([]interface{}); ok {
@@ -80,18 +112,22 @@ func (e Entry) ToolSummary() string {
 				if name == "" {
 					continue
 				}
-
-				detail := extractToolDetail(name, input)
-				if detail != "" {
-					return name + ": " + detail
-				}
-				return name
+				return ToolSummary(name, input)
 			}
 		}
 	}
 	return ""
 }
 
+// ToolSummary returns a one-line summary of a call to a Claude tool, e.g.
+// "Bash: go test ./...", or just the name for tools without a detail.
+func ToolSummary(name string, input map[string]interface{}) string {
+	if detail := extractToolDetail(name, input); detail != "" {
+		return name + ": " + detail
+	}
+	return name
+}
+
 // ToolInput returns the tool input map for tool_use entries.
 func (e Entry) ToolInput() map[string]interface{} {
 	if blocks, ok := e.Message.Content.([]interface{}); ok {
@@ -191,11 +227,79 @@ func (e Entry) IsToolResult() bool {
 	return false
 }
 
+// ToolUseID returns the ID of the tool_use block, used to match its result.
+func (e Entry) ToolUseID() string {
+	if blocks, ok := e.Message.Content.([]interface{}); ok {
+		for _, block := range blocks {
+			if m, ok := block.(map[string]interface{}); ok {
+				if m["type"] == "tool_use" {
+					id, _ := m["id"].(string)
+					return id
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// ToolResult is the output of a tool call.
+type ToolResult struct {
+	ToolUseID string // ID of the tool_use block this answers, empty if unknown
+	Content   string
+	IsError   bool
+}
+
+// ToolResults returns the tool_result blocks of a user entry.
+func (e Entry) ToolResults() []ToolResult {
+	blocks, ok := e.Message.Content.([]interface{})
+	if !ok {
+		return nil
+	}
+	var results []ToolResult
+	for _, block := range blocks {
+		m, ok := block.(map[string]interface{})
+		if !ok || m["type"] != "tool_result" {
+			continue
+		}
+		id, _ := m["tool_use_id"].(string)
+		isError, _ := m["is_error"].(bool)
+		results = append(results, ToolResult{ToolUseID: id, Content: toolResultText(m["content"]), IsError: isError})
+	}
+	return results
+}
+
+// toolResultText extracts the text of a tool_result block's content, which
+// is either a string or a list of content blocks.
+func toolResultText(content interface{}) string {
+	switch c := content.(type) {
+	case string:
+		return c
+	case []interface{}:
+		var parts []string
+		for _, block := range c {
+			if m, ok := block.(map[string]interface{}); ok && m["type"] == "text" {
+				if text, ok := m["text"].(string); ok {
+					parts = append(parts, text)
+				}
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return ""
+}
+
 // ParseTranscript reads a JSONL file and returns all entries.
 func ParseTranscript(path string) ([]Entry, error) {
+	entries, _, err := ReadTranscript(path, 0)
+	return entries, err
+}
+
+// ReadTranscript reads a JSONL file from the given line offset.
+// Returns entries and the new line count, so callers can read incrementally.
+func ReadTranscript(path string, fromLine int) ([]Entry, int, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return nil, fromLine, err
 	}
 	defer f.Close()
 
@@ -206,7 +310,12 @@ func ParseTranscript(path string) ([]Entry, error) {
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 1024*1024) // 1MB max line
 
+	lineNum := 0
 	for scanner.Scan() {
+		lineNum++
+		if lineNum <= fromLine {
+			continue
+		}
 		var e Entry
 		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
 			continue // Skip malformed lines
@@ -214,5 +323,125 @@ func ParseTranscript(path string) ([]Entry, error) {
 		entries = append(entries, e)
 	}
 
-	return entries, scanner.Err()
+	return entries, lineNum, scanner.Err()
+}
+
+// Events returns the transcript events of a user or assistant entry. The
+// text of user entries carrying tool results isn't a prompt, so only their
+// results are returned.
+func (e Entry) Events() []transcript.Event {
+	if e.Type != "user" && e.Type != "assistant" {
+		return nil
+	}
+	when := e.Timestamp
+
+	var events []transcript.Event
+	addText := func(text string) {
+		if text == "" {
+			return
+		}
+		if e.Type == "user" {
+			events = append(events, transcript.UserPrompt{Time: when, Text: text})
+		} else {
+			events = append(events, transcript.AssistantText{Time: when, Text: text})
+		}
+	}
+
+	switch c := e.Message.Content.(type) {
+	case string:
+		addText(c)
+	case []interface{}:
+		results := e.ToolResults()
+		for _, r := range results {
+			events = append(events, transcript.ToolResult{Time: when, ID: r.ToolUseID, Output: r.Content, IsError: r.IsError})
+		}
+		for _, block := range c {
+			m, ok := block.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch m["type"] {
+			case "text":
+				if len(results) == 0 {
+					text, _ := m["text"].(string)
+					addText(text)
+				}
+			case "thinking":
+				if text, _ := m["thinking"].(string); text != "" {
+					events = append(events, transcript.Reasoning{Time: when, Text: text})
+				}
+			case "tool_use":
+				id, _ := m["id"].(string)
+				name, _ := m["name"].(string)
+				input, _ := m["input"].(map[string]interface{})
+				events = append(events, transcript.ToolCall{Time: when, ID: id, Name: name, Input: input})
+			}
+		}
+	}
+
+	if u := e.Message.Usage; e.Type == "assistant" && u != nil {
+		events = append(events, transcript.Usage{
+			Time:  when,
+			Model: e.Message.Model,
+			Tokens: usage.Tokens{
+				Input:      u.InputTokens,
+				Output:     u.OutputTokens,
+				CacheRead:  u.CacheReadInputTokens,
+				CacheWrite: u.CacheCreationInputTokens,
+			},
+			MessageID: e.Message.ID,
+		})
+	}
+	return events
+}
+
+// ParseLine parses a single transcript line into events. Besides the user
+// and assistant entries of every Claude transcript, it understands the
+// system and result events of headless (stream-json) runs.
+func ParseLine(line []byte) []transcript.Event {
+	var e struct {
+		Entry
+		Subtype string `json:"subtype"`
+		Model   string `json:"model"`    // system/init
+		IsError bool   `json:"is_error"` // result
+	}
+	if err := json.Unmarshal(line, &e); err != nil {
+		return nil
+	}
+	switch e.Type {
+	case "system":
+		if e.Subtype != "init" {
+			return nil
+		}
+		return []transcript.Event{transcript.Lifecycle{Time: e.Timestamp, Phase: transcript.LifecycleStart, Detail: e.Model, Model: e.Model}}
+	case "result":
+		return []transcript.Event{transcript.Lifecycle{Time: e.Timestamp, Phase: transcript.LifecycleEnd, Detail: e.Subtype, IsError: e.IsError}}
+	}
+	return e.Entry.Events()
+}
+
+// ReadEvents reads the events of a transcript from the given line offset.
+// Returns the events and the new line count, so callers can read
+// incrementally.
+func ReadEvents(path string, fromLine int) ([]transcript.Event, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fromLine, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024) // 1MB max line
+
+	var events []transcript.Event
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if lineNum <= fromLine {
+			continue
+		}
+		events = append(events, ParseLine(scanner.Bytes())...)
+	}
+	return events, lineNum, scanner.Err()
 }
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/sky-xo/june/internal/transcript"
 )
 
 func TestParseTranscript(t *testing.T) {
@@ -158,3 +160,108 @@ func TestToolSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestReadTranscriptFromLine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+
+	content := `{"type":"system","subtype":"init","session_id":"abc"}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"first"}]}}
+not json
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"second"}]}}
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	entries, cursor, err := ReadTranscript(path, 0)
+	if err != nil {
+		t.Fatalf("ReadTranscript: %v", err)
+	}
+	if cursor != 4 {
+		t.Errorf("cursor = %d, want 4 (malformed lines still count)", cursor)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+
+	entries, cursor, err = ReadTranscript(path, 2)
+	if err != nil {
+		t.Fatalf("ReadTranscript: %v", err)
+	}
+	if cursor != 4 || len(entries) != 1 || entries[0].TextContent() != "second" {
+		t.Errorf("from line 2: cursor=%d entries=%v, want only the second message", cursor, entries)
+	}
+}
+
+func TestEntryToolResults(t *testing.T) {
+	call := Entry{Type: "assistant", Message: Message{Content: []interface{}{
+		map[string]interface{}{"type": "tool_use", "id": "toolu_1", "name": "Bash", "input": map[string]interface{}{"command": "go test"}},
+	}}}
+	if got := call.ToolUseID(); got != "toolu_1" {
+		t.Errorf("ToolUseID() = %q, want toolu_1", got)
+	}
+
+	result := Entry{Type: "user", Message: Message{Content: []interface{}{
+		map[string]interface{}{"type": "tool_result", "tool_use_id": "toolu_1", "is_error": true, "content": []interface{}{
+			map[string]interface{}{"type": "text", "text": "FAIL"},
+			map[string]interface{}{"type": "text", "text": "exit status 1"},
+		}},
+	}}}
+	results := result.ToolResults()
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	want := ToolResult{ToolUseID: "toolu_1", Content: "FAIL\nexit status 1", IsError: true}
+	if results[0] != want {
+		t.Errorf("ToolResults()[0] = %+v, want %+v", results[0], want)
+	}
+}
+
+func TestParseLineEvents(t *testing.T) {
+	events := ParseLine([]byte(`{"type":"assistant","message":{"id":"msg_1","model":"claude-sonnet-4-5","role":"assistant","content":[{"type":"thinking","thinking":"Check the tests"},{"type":"text","text":"Running them"},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test"}}],"usage":{"input_tokens":10,"output_tokens":5}}}`))
+	if len(events) != 4 {
+		t.Fatalf("ParseLine() = %+v, want reasoning, text, a tool call and usage", events)
+	}
+	if r, ok := events[0].(transcript.Reasoning); !ok || r.Text != "Check the tests" {
+		t.Errorf("events[0] = %+v, want Reasoning", events[0])
+	}
+	if text, ok := events[1].(transcript.AssistantText); !ok || text.Text != "Running them" {
+		t.Errorf("events[1] = %+v, want AssistantText", events[1])
+	}
+	if call, ok := events[2].(transcript.ToolCall); !ok || call.ID != "t1" || call.Name != "Bash" || call.Input["command"] != "go test" {
+		t.Errorf("events[2] = %+v, want the Bash ToolCall", events[2])
+	}
+	if u, ok := events[3].(transcript.Usage); !ok || u.MessageID != "msg_1" || u.Model != "claude-sonnet-4-5" || u.Input != 10 || u.Output != 5 {
+		t.Errorf("events[3] = %+v, want the message's Usage", events[3])
+	}
+
+	// Tool results are returned without the entry's text
+	events = ParseLine([]byte(`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"FAIL","is_error":true},{"type":"text","text":"ignored"}]}}`))
+	if len(events) != 1 {
+		t.Fatalf("ParseLine() = %+v, want only the tool result", events)
+	}
+	if r, ok := events[0].(transcript.ToolResult); !ok || r.ID != "t1" || r.Output != "FAIL" || !r.IsError {
+		t.Errorf("events[0] = %+v, want the failed ToolResult", events[0])
+	}
+}
+
+func TestParseLineLifecycle(t *testing.T) {
+	start := ParseLine([]byte(`{"type":"system","subtype":"init","model":"claude-opus-4-1"}`))
+	if len(start) != 1 {
+		t.Fatalf("system/init = %+v, want one event", start)
+	}
+	if l, ok := start[0].(transcript.Lifecycle); !ok || l.Phase != transcript.LifecycleStart || l.Detail != "claude-opus-4-1" {
+		t.Errorf("system/init = %+v, want a start Lifecycle with the model", start[0])
+	}
+
+	end := ParseLine([]byte(`{"type":"result","subtype":"error_max_turns","is_error":true}`))
+	if len(end) != 1 {
+		t.Fatalf("result = %+v, want one event", end)
+	}
+	if l, ok := end[0].(transcript.Lifecycle); !ok || l.Phase != transcript.LifecycleEnd || l.Detail != "error_max_turns" || !l.IsError {
+		t.Errorf("result = %+v, want a failed end Lifecycle", end[0])
+	}
+
+	if events := ParseLine([]byte(`{"type":"summary","summary":"Fixing tests"}`)); events != nil {
+		t.Errorf("summary = %+v, want no events", events)
+	}
+}
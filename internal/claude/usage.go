@@ -0,0 +1,14 @@
+package claude
+
+import (
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+// ReadUsage returns the token usage of each assistant message in a
+// transcript. Claude Code writes a message's content blocks as separate
+// entries that repeat its usage, so entries are counted once per message ID,
+// using the last one (the most complete while streaming).
+func ReadUsage(path string) ([]usage.Record, error) {
+	return transcript.ReadUsage(path, ParseLine)
+}
@@ -0,0 +1,45 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func TestReadUsageCountsEachMessageOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-a1.jsonl")
+	// msg_1 is split into a thinking and a tool_use entry that repeat its usage
+	content := `{"type":"user","timestamp":"2026-01-07T10:00:00Z","message":{"role":"user","content":"Fix the test"}}
+{"type":"assistant","timestamp":"2026-01-07T10:00:01Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","role":"assistant","content":[{"type":"thinking","thinking":"..."}],"usage":{"input_tokens":10,"cache_creation_input_tokens":500,"cache_read_input_tokens":2000,"output_tokens":3}}}
+{"type":"assistant","timestamp":"2026-01-07T10:00:02Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}],"usage":{"input_tokens":10,"cache_creation_input_tokens":500,"cache_read_input_tokens":2000,"output_tokens":40}}}
+{"type":"user","timestamp":"2026-01-07T10:00:03Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-01-07T10:00:04Z","message":{"id":"msg_2","model":"claude-sonnet-4-5","role":"assistant","content":[{"type":"text","text":"Fixed."}],"usage":{"input_tokens":5,"cache_read_input_tokens":2500,"output_tokens":20}}}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want one per message", len(records))
+	}
+
+	first := records[0]
+	if want := (usage.Tokens{Input: 10, Output: 40, CacheRead: 2000, CacheWrite: 500}); first.Tokens != want {
+		t.Errorf("msg_1 tokens = %+v, want %+v (from its last entry)", first.Tokens, want)
+	}
+	if first.Model != "claude-sonnet-4-5" {
+		t.Errorf("msg_1 model = %q, want claude-sonnet-4-5", first.Model)
+	}
+	if first.Time.Second() != 1 {
+		t.Errorf("msg_1 time = %v, want when the message started", first.Time)
+	}
+	if records[1].Output != 20 {
+		t.Errorf("msg_2 output = %d, want 20", records[1].Output)
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// supervisorEnv marks a re-executed june process as a detached supervisor.
+	supervisorEnv = "JUNE_SUPERVISOR"
+	// supervisorLogEnv tells the supervisor where its stdout/stderr are written,
+	// so it can rename the log once the agent name is known.
+	supervisorLogEnv = "JUNE_SUPERVISOR_LOG"
+	// readyFD is the file descriptor (ExtraFiles[0]) the supervisor uses to hand
+	// the agent name back to the foreground process.
+	readyFD = 3
+)
+
+// isSupervisor reports whether this process is a detached spawn supervisor.
+func isSupervisor() bool {
+	return os.Getenv(supervisorEnv) == "1"
+}
+
+// logsDir returns ~/.june/logs, where detached supervisors write their output.
+func logsDir() (string, error) {
+	home, err := juneHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "logs"), nil
+}
+
+// runDetached re-executes the current june command as a background supervisor
+// in its own session. It blocks only until the supervisor reports the agent
+// name (i.e. once the thread/session ID is known), prints it, and returns.
+// stdin, if set, is the supervisor's standard input.
+func runDetached(stdin io.Reader) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate june executable: %w", err)
+	}
+
+	dir, err := logsDir()
+	if err != nil {
+		return fmt.Errorf("failed to get june home: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create logs directory: %w", err)
+	}
+	logPath := filepath.Join(dir, fmt.Sprintf("spawn-%d.log", time.Now().UnixNano()))
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create supervisor log: %w", err)
+	}
+	defer logFile.Close()
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	defer readyR.Close()
+
+	supervisor := exec.Command(exe, os.Args[1:]...)
+	supervisor.Env = append(os.Environ(),
+		supervisorEnv+"=1",
+		supervisorLogEnv+"="+logPath,
+	)
+	supervisor.Stdin = stdin
+	supervisor.Stdout = logFile
+	supervisor.Stderr = logFile
+	supervisor.ExtraFiles = []*os.File{readyW}
+	// New session: no controlling terminal, so closing the shell won't SIGHUP the agent
+	supervisor.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := supervisor.Start(); err != nil {
+		readyW.Close()
+		return fmt.Errorf("failed to start supervisor: %w", err)
+	}
+	// Close our copy so the read below sees EOF if the supervisor dies early
+	readyW.Close()
+
+	data, _ := io.ReadAll(readyR)
+	name := strings.TrimSpace(string(data))
+	if name == "" {
+		// The supervisor exited without announcing a name; reap it and surface its log
+		supervisor.Wait()
+		msg, _ := os.ReadFile(logPath)
+		if detail := strings.TrimSpace(string(msg)); detail != "" {
+			return fmt.Errorf("detached spawn failed: %s", detail)
+		}
+		return fmt.Errorf("detached spawn failed (see %s)", logPath)
+	}
+
+	// Let the supervisor run on its own
+	supervisor.Process.Release()
+
+	fmt.Println(name)
+	return nil
+}
+
+// releaseFromSupervisor keeps an agent command started by a detached
+// supervisor from inheriting the ready pipe, which the foreground process
+// reads until every copy is closed, and the supervisor environment. Without
+// this, --detach would only return once the agent exits. It is a no-op
+// outside supervisor mode.
+func releaseFromSupervisor(cmd *exec.Cmd) {
+	if !isSupervisor() {
+		return
+	}
+	// ExtraFiles aren't close-on-exec in the process that receives them
+	syscall.CloseOnExec(readyFD)
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = slices.DeleteFunc(slices.Clone(env), func(kv string) bool {
+		return strings.HasPrefix(kv, supervisorEnv+"=") || strings.HasPrefix(kv, supervisorLogEnv+"=")
+	})
+}
+
+// notifyDetached hands the agent name to the foreground process when running
+// as a detached supervisor. It is a no-op otherwise.
+func notifyDetached(name string) {
+	if !isSupervisor() {
+		return
+	}
+
+	// Give the log a stable, discoverable name
+	if logPath := os.Getenv(supervisorLogEnv); logPath != "" {
+		if dir, err := logsDir(); err == nil {
+			_ = os.Rename(logPath, filepath.Join(dir, name+".log"))
+		}
+	}
+
+	ready := os.NewFile(readyFD, "ready")
+	if ready == nil {
+		return
+	}
+	fmt.Fprintln(ready, name)
+	ready.Close()
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+)
+
+// testMainEnv makes the test binary run june itself, so runDetached can
+// re-execute it as a supervisor.
+const testMainEnv = "JUNE_TEST_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testMainEnv) == "1" {
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestIsSupervisor(t *testing.T) {
+	t.Setenv(supervisorEnv, "")
+	if isSupervisor() {
+		t.Error("isSupervisor() = true without env, want false")
+	}
+
+	t.Setenv(supervisorEnv, "1")
+	if !isSupervisor() {
+		t.Error("isSupervisor() = false with env, want true")
+	}
+}
+
+func TestNotifyDetached_NoopWhenNotSupervisor(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv(supervisorEnv, "")
+
+	logPath := filepath.Join(tmpDir, "spawn.log")
+	os.WriteFile(logPath, []byte("output"), 0644)
+	t.Setenv(supervisorLogEnv, logPath)
+
+	notifyDetached("swift-falcon-7d1e")
+
+	// Log should not have been renamed
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("log file was moved outside supervisor mode: %v", err)
+	}
+}
+
+func TestRunDetached_ReturnsWhileAgentRuns(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// A fake codex that announces its thread and runs until told to stop
+	dir := t.TempDir()
+	stop := filepath.Join(dir, "stop")
+	envFile := filepath.Join(dir, "env")
+	script := `#!/bin/sh
+env > "$FAKE_CODEX_ENV"
+echo '{"type":"thread.started","thread_id":"019b6e2c-7d1e-7c3a-9c4f-2a1b3c4d5e6f"}'
+while [ ! -e "$FAKE_CODEX_STOP" ]; do sleep 0.1; done
+`
+	if err := os.WriteFile(filepath.Join(dir, "codex"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_CODEX_STOP", stop)
+	t.Setenv("FAKE_CODEX_ENV", envFile)
+	t.Setenv(testMainEnv, "1")
+	t.Setenv(supervisorEnv, "")
+
+	args := os.Args
+	os.Args = []string{args[0], "spawn", "codex", "fix the tests", "--name", "fake", "--detach"}
+	defer func() { os.Args = args }()
+
+	// Let the agent finish and the supervisor record it before HOME is removed
+	t.Cleanup(func() {
+		os.WriteFile(stop, nil, 0644)
+		database, err := openDB()
+		if err != nil {
+			return
+		}
+		defer database.Close()
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+			agents, err := database.ListAgents()
+			if err == nil && len(agents) == 1 && agents[0].Status != agent.StatusRunning {
+				return
+			}
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- runDetached(nil) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("runDetached: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("--detach didn't return while the agent was running")
+	}
+
+	env, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatalf("agent didn't start: %v", err)
+	}
+	for _, line := range strings.Split(string(env), "\n") {
+		if strings.HasPrefix(line, supervisorEnv+"=") || strings.HasPrefix(line, supervisorLogEnv+"=") {
+			t.Errorf("agent inherited the supervisor environment: %s", line)
+		}
+	}
+}
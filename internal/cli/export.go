@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sky-xo/june/internal/scope"
+	"github.com/sky-xo/june/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	var format, output string
+
+	cmd := &cobra.Command{
+		Use:   "export <name|agent-id>",
+		Short: "Export an agent's transcript as Markdown, HTML or JSON",
+		Long: `Export the full transcript of a spawned agent, or of one of this
+repository's Claude subagents by its agent ID.
+
+Markdown keeps code blocks and shows edits as diffs, for pasting into pull
+requests and write-ups. HTML is a single self-contained page with the TUI's
+syntax highlighting. JSON follows the schema documented in the README.
+
+Examples:
+  june export refactor-9c4f > refactor.md
+  june export refactor-9c4f --format html -o refactor.html
+  june export a1b2c3d --format json | jq '.events[] | select(.type == "tool_call")'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(tui.ExportFormats, format) {
+				return fmt.Errorf("invalid --format %q: use %s", format, strings.Join(tui.ExportFormats, ", "))
+			}
+			cmd.SilenceUsage = true
+			return runExport(cmd.OutOrStdout(), args[0], format, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", tui.FormatMarkdown, "Output format: md, html or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to a file instead of stdout")
+
+	return cmd
+}
+
+func runExport(w io.Writer, nameOrID, format, output string) error {
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	a, _, err := resolveAgent(database, nameOrID, scope.RepoRoot())
+	if err != nil {
+		return err
+	}
+	if a.TranscriptPath == "" {
+		return fmt.Errorf("session file not found for agent %q", nameOrID)
+	}
+
+	// Render fully before touching the output file, so a failed export
+	// doesn't leave a partial one behind
+	var buf bytes.Buffer
+	if err := tui.ExportAgent(&buf, a, format); err != nil {
+		return fmt.Errorf("failed to export %s: %w", a.DisplayName(), err)
+	}
+	if output == "" {
+		_, err = w.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(output, buf.Bytes(), 0644)
+}
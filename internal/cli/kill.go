@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/proc"
+	"github.com/spf13/cobra"
+)
+
+// defaultKillGrace is how long kill waits after SIGTERM before sending SIGKILL.
+const defaultKillGrace = 5 * time.Second
+
+func newKillCmd() *cobra.Command {
+	var grace time.Duration
+
+	cmd := &cobra.Command{
+		Use:     "kill <name>",
+		Aliases: []string{"stop"},
+		Short:   "Stop a running agent",
+		Long: `Stop a running agent by sending SIGTERM to its process group.
+
+If the agent is still running after the grace period, it is sent SIGKILL.
+The agent is recorded as killed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			return runKill(name, grace)
+		},
+	}
+
+	cmd.Flags().DurationVar(&grace, "grace", defaultKillGrace, "Time to wait after SIGTERM before sending SIGKILL")
+
+	return cmd
+}
+
+func runKill(name string, grace time.Duration) error {
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	a, err := database.GetAgent(name)
+	if err == db.ErrAgentNotFound {
+		return fmt.Errorf("agent %q not found", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := killAgent(database, a, grace); err != nil {
+		return err
+	}
+
+	fmt.Printf("killed %s\n", name)
+	return nil
+}
+
+// killAgent stops a running agent's process group (SIGTERM, then SIGKILL
+// after grace) and records it as killed. It refuses to signal a PID that no
+// longer belongs to the agent.
+func killAgent(database *db.DB, a *db.Agent, grace time.Duration) error {
+	if a.IsFinished() {
+		return fmt.Errorf("agent %q is not running (%s)", a.Name, a.Status)
+	}
+	sig, err := proc.Stop(a.PID, a.StartedAt, grace)
+	if err != nil {
+		return fmt.Errorf("failed to stop agent %q: %w", a.Name, err)
+	}
+	return database.MarkKilled(a.Name, sig)
+}
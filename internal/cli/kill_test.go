@@ -0,0 +1,26 @@
+package cli
+
+import "testing"
+
+func TestKillCmdFlags(t *testing.T) {
+	cmd := newKillCmd()
+
+	flag := cmd.Flags().Lookup("grace")
+	if flag == nil {
+		t.Fatal("flag --grace not found")
+	}
+	if flag.DefValue != defaultKillGrace.String() {
+		t.Errorf("--grace default = %s, want %s", flag.DefValue, defaultKillGrace)
+	}
+
+	if len(cmd.Aliases) != 1 || cmd.Aliases[0] != "stop" {
+		t.Errorf("Aliases = %v, want [stop]", cmd.Aliases)
+	}
+}
+
+func TestKillCmdRequiresName(t *testing.T) {
+	cmd := newKillCmd()
+	if err := cmd.Args(cmd, nil); err == nil {
+		t.Error("kill with no args should fail")
+	}
+}
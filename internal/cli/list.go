@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/scope"
+	"github.com/spf13/cobra"
+)
+
+// listOptions holds the filters for june list.
+type listOptions struct {
+	Repo    bool // Only agents spawned in the current repository
+	Branch  string
+	Type    string
+	Running bool
+}
+
+// listedAgent is a row of june list output. The JSON field names are part of
+// the CLI's scripting interface, so keep them stable.
+type listedAgent struct {
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	RepoPath     string          `json:"repo_path"`
+	Branch       string          `json:"branch"`
+	SpawnedAt    time.Time       `json:"spawned_at"`
+	PID          int             `json:"pid"`
+	Running      bool            `json:"running"`
+	LastActivity time.Time       `json:"last_activity"`
+	SessionFile  string          `json:"session_file"`
+	Status       string          `json:"status"`
+	ExitCode     *int            `json:"exit_code,omitempty"`
+	FinishedAt   time.Time       `json:"finished_at,omitzero"`
+	Error        string          `json:"error,omitempty"`
+	Task         string          `json:"task,omitempty"`
+	Options      db.SpawnOptions `json:"options"`
+	RetryOf      string          `json:"retry_of,omitempty"`
+}
+
+func newListCmd() *cobra.Command {
+	var (
+		opts     listOptions
+		jsonMode bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List spawned agents",
+		Long: `List spawned Codex, Gemini and Claude agents, most recent first.
+
+Use --repo to limit the list to the current repository, as with june search.
+
+Examples:
+  june list                          # All agents
+  june list --repo --running         # Running agents in this repo
+  june list --type codex --json      # Machine-readable output`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(cmd.OutOrStdout(), opts, jsonMode)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Repo, "repo", false, "Only show agents spawned in the current repository")
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Only show agents spawned on this branch")
+	cmd.Flags().StringVar(&opts.Type, "type", "", "Only show agents of this type (codex, gemini, claude)")
+	cmd.Flags().BoolVar(&opts.Running, "running", false, "Only show agents whose process is still running")
+	cmd.Flags().BoolVar(&jsonMode, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func runList(w io.Writer, opts listOptions, jsonMode bool) error {
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	var agents []db.Agent
+	if opts.Repo {
+		repoRoot := scope.RepoRoot()
+		if repoRoot == "" {
+			return fmt.Errorf("not in a git repository")
+		}
+		agents, err = database.ListAgentsByRepo(repoRoot)
+		if err != nil {
+			return fmt.Errorf("failed to list agents: %w", err)
+		}
+	} else {
+		agents, err = database.ListAgents()
+		if err != nil {
+			return fmt.Errorf("failed to list agents: %w", err)
+		}
+	}
+
+	rows := filterAgents(agents, opts, db.Agent.ProcessAlive)
+
+	if jsonMode {
+		if rows == nil {
+			rows = []listedAgent{} // Emit [] rather than null
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "(no agents)")
+		return nil
+	}
+	return writeAgentTable(w, rows)
+}
+
+// filterAgents applies the list filters and resolves liveness for each agent.
+// alive is injected so tests don't depend on real processes; it is
+// db.Agent.ProcessAlive, so a reused PID doesn't count as running.
+func filterAgents(agents []db.Agent, opts listOptions, alive func(db.Agent) bool) []listedAgent {
+	var rows []listedAgent
+	for _, a := range agents {
+		if opts.Branch != "" && a.Branch != opts.Branch {
+			continue
+		}
+		if opts.Type != "" && a.Type != opts.Type {
+			continue
+		}
+		running := alive(a)
+		if opts.Running && !running {
+			continue
+		}
+		row := listedAgent{
+			Name:         a.Name,
+			Type:         a.Type,
+			RepoPath:     a.RepoPath,
+			Branch:       a.Branch,
+			SpawnedAt:    a.SpawnedAt,
+			PID:          a.PID,
+			Running:      running,
+			LastActivity: a.ToUnified().LastActivity,
+			SessionFile:  a.SessionFile,
+			Status:       a.EffectiveStatus(),
+			FinishedAt:   a.FinishedAt,
+			Error:        a.Error,
+			Task:         a.Task,
+			Options:      a.Options,
+			RetryOf:      a.RetryOf,
+		}
+		if a.IsFinished() {
+			exitCode := a.ExitCode
+			row.ExitCode = &exitCode
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// writeAgentTable prints agents as aligned columns.
+func writeAgentTable(w io.Writer, rows []listedAgent) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tBRANCH\tSTATUS\tSPAWNED\tPID\tLAST ACTIVITY")
+	for _, r := range rows {
+		branch := r.Branch
+		if branch == "" {
+			branch = "-"
+		}
+		pid := "-"
+		if r.PID > 0 {
+			state := "exited"
+			if r.Running {
+				state = "running"
+			}
+			pid = fmt.Sprintf("%d (%s)", r.PID, state)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Name, r.Type, branch, formatStatus(r), relativeTime(r.SpawnedAt), pid, relativeTime(r.LastActivity))
+	}
+	return tw.Flush()
+}
+
+// formatStatus renders the status column, e.g. "running" or "failed (exit 1)".
+// Agents spawned before status tracking existed show "-".
+func formatStatus(r listedAgent) string {
+	if r.Status == "" {
+		return "-"
+	}
+	if r.ExitCode != nil && *r.ExitCode != 0 {
+		return fmt.Sprintf("%s (exit %d)", r.Status, *r.ExitCode)
+	}
+	return r.Status
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/db"
+)
+
+func testListAgents() []db.Agent {
+	now := time.Now()
+	return []db.Agent{
+		{Name: "refactor-9c4f", Type: "codex", Branch: "main", PID: 100, SpawnedAt: now.Add(-time.Hour)},
+		{Name: "research-3b7a", Type: "gemini", Branch: "feature", PID: 200, SpawnedAt: now.Add(-2 * time.Hour)},
+		{Name: "swift-falcon-7d1e", Type: "codex", Branch: "feature", PID: 300, SpawnedAt: now.Add(-3 * time.Hour)},
+	}
+}
+
+func aliveSet(pids ...int) func(db.Agent) bool {
+	return func(a db.Agent) bool {
+		for _, p := range pids {
+			if p == a.PID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func names(rows []listedAgent) []string {
+	var out []string
+	for _, r := range rows {
+		out = append(out, r.Name)
+	}
+	return out
+}
+
+func TestFilterAgents(t *testing.T) {
+	tests := []struct {
+		name string
+		opts listOptions
+		want []string
+	}{
+		{
+			name: "no filters",
+			opts: listOptions{},
+			want: []string{"refactor-9c4f", "research-3b7a", "swift-falcon-7d1e"},
+		},
+		{
+			name: "by branch",
+			opts: listOptions{Branch: "feature"},
+			want: []string{"research-3b7a", "swift-falcon-7d1e"},
+		},
+		{
+			name: "by type",
+			opts: listOptions{Type: "codex"},
+			want: []string{"refactor-9c4f", "swift-falcon-7d1e"},
+		},
+		{
+			name: "running only",
+			opts: listOptions{Running: true},
+			want: []string{"research-3b7a"},
+		},
+		{
+			name: "combined filters",
+			opts: listOptions{Branch: "feature", Type: "codex"},
+			want: []string{"swift-falcon-7d1e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(filterAgents(testListAgents(), tt.opts, aliveSet(200)))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterAgents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterAgents_SetsRunning(t *testing.T) {
+	rows := filterAgents(testListAgents(), listOptions{}, aliveSet(100))
+	if !rows[0].Running {
+		t.Error("rows[0].Running = false, want true")
+	}
+	if rows[1].Running {
+		t.Error("rows[1].Running = true, want false")
+	}
+}
+
+func TestWriteAgentTable(t *testing.T) {
+	rows := filterAgents(testListAgents(), listOptions{}, aliveSet(100))
+
+	var buf bytes.Buffer
+	if err := writeAgentTable(&buf, rows); err != nil {
+		t.Fatalf("writeAgentTable failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"NAME", "refactor-9c4f", "100 (running)", "200 (exited)", "1 hour ago"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatStatus(t *testing.T) {
+	zero, one := 0, 1
+	tests := []struct {
+		row  listedAgent
+		want string
+	}{
+		{listedAgent{}, "-"},
+		{listedAgent{Status: "running"}, "running"},
+		{listedAgent{Status: "succeeded", ExitCode: &zero}, "succeeded"},
+		{listedAgent{Status: "failed", ExitCode: &one}, "failed (exit 1)"},
+	}
+	for _, tt := range tests {
+		if got := formatStatus(tt.row); got != tt.want {
+			t.Errorf("formatStatus(%+v) = %q, want %q", tt.row, got, tt.want)
+		}
+	}
+}
+
+func TestFilterAgents_IncludesSpawnSettings(t *testing.T) {
+	agents := []db.Agent{{
+		Name:    "impl-1",
+		Type:    "codex",
+		Task:    "fix the tests",
+		Options: db.SpawnOptions{Model: "o3"},
+	}}
+
+	rows := filterAgents(agents, listOptions{}, aliveSet())
+	if rows[0].Task != "fix the tests" {
+		t.Errorf("Task = %q, want %q", rows[0].Task, "fix the tests")
+	}
+	if rows[0].Options.Model != "o3" {
+		t.Errorf("Options.Model = %q, want %q", rows[0].Options.Model, "o3")
+	}
+}
+
+func TestFilterAgents_ReusedPIDNotRunning(t *testing.T) {
+	// Our own process started after this agent was recorded, so it only
+	// reused the PID
+	agents := []db.Agent{
+		{Name: "old-1", Type: "codex", PID: os.Getpid(), StartedAt: time.Now().Add(-time.Hour)},
+		{Name: "live-1", Type: "codex", PID: os.Getpid(), StartedAt: time.Now()},
+	}
+
+	rows := filterAgents(agents, listOptions{Running: true}, db.Agent.ProcessAlive)
+	if got := strings.Join(names(rows), ","); got != "live-1" {
+		t.Errorf("running agents = %q, want only live-1", got)
+	}
+}
@@ -2,85 +2,223 @@ package cli
 
 import (
 	"fmt"
-	"path/filepath"
+	"io"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/sky-xo/june/internal/codex"
+	"github.com/sky-xo/june/internal/agent"
 	"github.com/sky-xo/june/internal/db"
-	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/scope"
+	"github.com/sky-xo/june/internal/transcript"
 	"github.com/spf13/cobra"
 )
 
+// followPollInterval is how often june logs --follow checks the transcript
+// for new entries.
+const followPollInterval = 500 * time.Millisecond
+
 func newLogsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "logs <name>",
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:   "logs <name|agent-id>",
 		Short: "Show full transcript from an agent",
-		Long:  "Show full transcript without advancing the cursor",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show full transcript without advancing the cursor.
+
+With --follow, keep printing entries as the agent writes them, like tail -f,
+until its process ends.
+
+Besides spawned agents, this accepts the agent ID or a description prefix of
+one of this repository's Claude subagents. Following one stops once its
+transcript has been quiet for a while.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
-			return runLogs(name)
+			return runLogs(cmd.OutOrStdout(), name, follow)
 		},
 	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream new entries until the agent exits")
+
+	return cmd
 }
 
-func runLogs(name string) error {
-	// Open database
-	home, err := juneHome()
-	if err != nil {
-		return fmt.Errorf("failed to get june home: %w", err)
-	}
-	dbPath := filepath.Join(home, "june.db")
-	database, err := db.Open(dbPath)
+func runLogs(w io.Writer, nameOrID string, follow bool) error {
+	database, err := openDB()
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return err
 	}
 	defer database.Close()
 
-	// Get agent
-	agent, err := database.GetAgent(name)
-	if err == db.ErrAgentNotFound {
-		return fmt.Errorf("agent %q not found", name)
+	a, spawned, err := resolveAgent(database, nameOrID, scope.RepoRoot())
+	if err != nil {
+		return err
 	}
+
+	p, err := provider.Get(a.Source)
 	if err != nil {
 		return err
 	}
 
-	// Find session file if not set
-	sessionFile := agent.SessionFile
-	if sessionFile == "" {
-		var findErr error
-		if agent.Type == "gemini" {
-			sessionFile, findErr = gemini.FindSessionFile(agent.ULID)
-		} else {
-			sessionFile, findErr = codex.FindSessionFile(agent.ULID)
+	if follow {
+		if spawned == nil {
+			return followClaudeAgent(w, a, p)
+		}
+		return followLogs(w, database, spawned, p)
+	}
+
+	if a.TranscriptPath == "" {
+		return fmt.Errorf("session file not found for agent %q", nameOrID)
+	}
+
+	events, _, err := p.ReadTranscript(a.TranscriptPath, 0)
+	if err != nil {
+		return fmt.Errorf("failed to read transcript: %w", err)
+	}
+	output := transcript.FormatText(events)
+
+	fmt.Fprint(w, formatSpawnInfo(a))
+
+	if output == "" {
+		fmt.Fprintln(w, "(no output)")
+		return nil
+	}
+
+	fmt.Fprint(w, output)
+	return nil
+}
+
+// followLogs prints a spawned agent's transcript and then its new entries
+// as they are written, until the agent's process ends.
+func followLogs(w io.Writer, database *db.DB, a *db.Agent, p provider.Provider) error {
+	fmt.Fprint(w, formatSpawnInfo(a.ToUnified()))
+
+	// finished reloads the agent, remembering its final state
+	final := *a
+	finished := func() (bool, error) {
+		current, err := database.GetAgent(a.Name)
+		if err != nil {
+			return false, fmt.Errorf("failed to get agent %q: %w", a.Name, err)
+		}
+		final = *current
+		_, done := terminalStatus(final)
+		return done, nil
+	}
+
+	// A just-spawned agent may not have created its session file yet
+	sessionFile := a.SessionFile
+	for sessionFile == "" {
+		done, err := finished()
+		if err != nil {
+			return err
 		}
-		if findErr != nil {
-			return fmt.Errorf("session file not found for agent %q", name)
+		if sessionFile, err = p.FindSessionFile(a.ULID); err == nil {
+			break
 		}
+		if done {
+			return fmt.Errorf("session file not found for agent %q", a.Name)
+		}
+		time.Sleep(followPollInterval)
 	}
 
-	// Read transcript based on agent type
-	var output string
+	if err := followTranscript(w, p, sessionFile, finished, followPollInterval); err != nil {
+		return err
+	}
+	status, _ := terminalStatus(final)
+	fmt.Fprintf(w, "[agent %s]\n", formatWaitStatus(waitResult{Agent: final, Status: status}))
+	return nil
+}
 
-	if agent.Type == "gemini" {
-		entries, _, err := gemini.ReadTranscript(sessionFile, 0)
+// followClaudeAgent prints a Claude subagent's transcript and then its new
+// entries as they are written. There is no process to watch, so it stops
+// once the transcript goes quiet, as the TUI stops showing it active.
+func followClaudeAgent(w io.Writer, a agent.Agent, p provider.Provider) error {
+	finished := func() (bool, error) {
+		info, err := os.Stat(a.TranscriptPath)
 		if err != nil {
-			return fmt.Errorf("failed to read transcript: %w", err)
+			return false, fmt.Errorf("failed to read transcript: %w", err)
 		}
-		output = gemini.FormatEntries(entries)
-	} else {
-		entries, _, err := codex.ReadTranscript(sessionFile, 0)
+		return !(agent.Agent{LastActivity: info.ModTime()}).IsActive(), nil
+	}
+
+	if err := followTranscript(w, p, a.TranscriptPath, finished, followPollInterval); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "[agent idle]")
+	return nil
+}
+
+// followTranscript writes the events of the transcript at path as they are
+// appended, checking every interval, until finished reports the agent done.
+// Whatever the agent wrote before finishing is still written.
+func followTranscript(w io.Writer, p provider.Provider, path string, finished func() (bool, error), interval time.Duration) error {
+	stream := &textStream{w: w}
+	var pos transcript.Position
+	for {
+		// Check before reading, so the last read sees everything the agent
+		// wrote before it finished
+		done, err := finished()
 		if err != nil {
+			return err
+		}
+
+		pos, _, err = transcript.ReadAppended(path, pos, func(line []byte) {
+			stream.write(p.ParseLine(line))
+		})
+		if err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("failed to read transcript: %w", err)
 		}
-		output = codex.FormatEntries(entries)
+
+		if done {
+			stream.end()
+			return nil
+		}
+		time.Sleep(interval)
 	}
+}
 
-	if output == "" {
-		fmt.Println("(no output)")
-		return nil
+// textStream writes events as transcript.FormatText formats them, as they
+// arrive. Streamed chunks of a reply are written as they come; the reply is
+// ended when another event arrives.
+type textStream struct {
+	w       io.Writer
+	inDelta bool // A streamed reply is being written
+}
+
+func (s *textStream) write(events []transcript.Event) {
+	for _, e := range events {
+		if t, ok := e.(transcript.AssistantText); ok && t.Delta {
+			fmt.Fprint(s.w, t.Text)
+			s.inDelta = true
+			continue
+		}
+		s.end()
+		fmt.Fprint(s.w, transcript.FormatText([]transcript.Event{e}))
 	}
+}
 
-	fmt.Print(output)
-	return nil
+// end finishes a streamed reply, if one is being written.
+func (s *textStream) end() {
+	if s.inDelta {
+		fmt.Fprint(s.w, "\n\n")
+		s.inDelta = false
+	}
+}
+
+// formatSpawnInfo describes what an agent was asked to do and how it was
+// spawned. Returns "" for Claude subagents and agents spawned before this
+// was recorded.
+func formatSpawnInfo(a agent.Agent) string {
+	if a.Task == "" {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task: %s\n", a.Task)
+	if a.Options != "" {
+		fmt.Fprintf(&b, "Options: %s\n", a.Options)
+	}
+	b.WriteString("\n")
+	return b.String()
 }
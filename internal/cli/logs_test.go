@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/transcript"
+)
+
+func TestFormatSpawnInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		agent agent.Agent
+		want  string
+	}{
+		{
+			name:  "legacy agent without task",
+			agent: agent.Agent{},
+			want:  "",
+		},
+		{
+			name:  "task only",
+			agent: agent.Agent{Task: "fix the tests"},
+			want:  "Task: fix the tests\n\n",
+		},
+		{
+			name:  "task and options",
+			agent: agent.Agent{Task: "fix the tests", Options: "model=o3 sandbox=read-only"},
+			want:  "Task: fix the tests\nOptions: model=o3 sandbox=read-only\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSpawnInfo(tt.agent); got != tt.want {
+				t.Errorf("formatSpawnInfo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	write := func(data string) {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := provider.Get("gemini")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The agent writes between checks, starting before its file exists
+	steps := []string{
+		"",
+		`{"type":"message","role":"user","content":"Hi"}` + "\n" + `{"type":"message","role":"assistant","content":"Hel","delta":true}` + "\n",
+		`{"type":"message","role":"assistant","content":"lo","delta":true}` + "\n" + `{"type":"tool_use","tool_name":"shell",`,
+		`"tool_id":"t1"}` + "\n",
+	}
+	checks := 0
+	finished := func() (bool, error) {
+		if checks < len(steps) && steps[checks] != "" {
+			write(steps[checks])
+		}
+		checks++
+		return checks >= len(steps), nil
+	}
+
+	var buf bytes.Buffer
+	if err := followTranscript(&buf, p, path, finished, 0); err != nil {
+		t.Fatalf("followTranscript: %v", err)
+	}
+	want := "[user] Hi\n\nHello\n\n[tool: shell]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestTextStream_EndsStreamedReply(t *testing.T) {
+	var buf bytes.Buffer
+	s := &textStream{w: &buf}
+	s.write([]transcript.Event{transcript.AssistantText{Text: "Do", Delta: true}})
+	s.write([]transcript.Event{transcript.AssistantText{Text: "ne", Delta: true}})
+	if got := buf.String(); got != "Done" {
+		t.Errorf("mid-stream output = %q, want the chunks written as they come", got)
+	}
+	s.end()
+	if got := buf.String(); got != "Done\n\n" {
+		t.Errorf("output = %q, want the reply ended", got)
+	}
+}
@@ -102,3 +102,12 @@ func resolveAgentNameWithULID(database *db.DB, prefix, ulid string) (string, err
 
 	return "", errors.New("failed to generate unique agent name after 10 attempts")
 }
+
+// namePrefix returns the prefix of an agent name, i.e. the name without its
+// 4-character suffix ("refactor-9c4f" -> "refactor").
+func namePrefix(name string) string {
+	if lastDash := strings.LastIndex(name, "-"); lastDash > 0 {
+		return name[:lastDash]
+	}
+	return name
+}
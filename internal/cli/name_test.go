@@ -143,3 +143,16 @@ func TestResolveAgentNameWithULID_DBError_Propagates(t *testing.T) {
 		t.Errorf("error = %q, want 'failed to check for existing agent'", err)
 	}
 }
+
+func TestNamePrefix(t *testing.T) {
+	tests := map[string]string{
+		"refactor-9c4f":     "refactor",
+		"swift-falcon-7d1e": "swift-falcon",
+		"plain":             "plain",
+	}
+	for name, want := range tests {
+		if got := namePrefix(name); got != want {
+			t.Errorf("namePrefix(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
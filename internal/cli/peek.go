@@ -2,97 +2,86 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 
-	"github.com/sky-xo/june/internal/codex"
-	"github.com/sky-xo/june/internal/db"
-	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/scope"
+	"github.com/sky-xo/june/internal/transcript"
 	"github.com/spf13/cobra"
 )
 
 func newPeekCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "peek <name>",
+		Use:   "peek <name|agent-id>",
 		Short: "Show new output from an agent",
-		Long:  "Show output since last peek and advance the cursor",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show output since last peek and advance the cursor.
+
+Besides spawned agents, this accepts the agent ID or a description prefix of
+one of this repository's Claude subagents, so a session can check on the
+Task agents it started.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := args[0]
-			return runPeek(name)
+			return runPeek(cmd.OutOrStdout(), args[0])
 		},
 	}
 }
 
-func runPeek(name string) error {
-	// Open database
-	home, err := juneHome()
-	if err != nil {
-		return fmt.Errorf("failed to get june home: %w", err)
-	}
-	dbPath := filepath.Join(home, "june.db")
-	database, err := db.Open(dbPath)
+func runPeek(w io.Writer, nameOrID string) error {
+	database, err := openDB()
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return err
 	}
 	defer database.Close()
 
-	// Get agent
-	agent, err := database.GetAgent(name)
-	if err == db.ErrAgentNotFound {
-		return fmt.Errorf("agent %q not found", name)
+	a, spawned, err := resolveAgent(database, nameOrID, scope.RepoRoot())
+	if err != nil {
+		return err
+	}
+	if a.TranscriptPath == "" {
+		return fmt.Errorf("session file not found for agent %q", nameOrID)
 	}
+
+	p, err := provider.Get(a.Source)
 	if err != nil {
 		return err
 	}
 
-	// Find session file if not set
-	sessionFile := agent.SessionFile
-	if sessionFile == "" {
-		var findErr error
-		if agent.Type == "gemini" {
-			sessionFile, findErr = gemini.FindSessionFile(agent.ULID)
-		} else {
-			sessionFile, findErr = codex.FindSessionFile(agent.ULID)
-		}
-		if findErr != nil {
-			return fmt.Errorf("session file not found for agent %q", name)
-		}
-		if err := database.UpdateSessionFile(name, sessionFile); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to update session file in database: %v\n", err)
+	// Spawned agents keep their cursor with the agent; Claude subagents
+	// have no row of their own
+	var cursor int
+	if spawned != nil {
+		cursor = spawned.Cursor
+		if spawned.SessionFile == "" {
+			if err := database.UpdateSessionFile(spawned.Name, a.TranscriptPath); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to update session file in database: %v\n", err)
+			}
 		}
+	} else if cursor, err = database.GetPeekCursor(a.ID); err != nil {
+		return fmt.Errorf("failed to get cursor: %w", err)
 	}
 
-	// Read transcript based on agent type
-	var output string
-	var newCursor int
-
-	if agent.Type == "gemini" {
-		entries, cursor, err := gemini.ReadTranscript(sessionFile, agent.Cursor)
-		if err != nil {
-			return fmt.Errorf("failed to read transcript: %w", err)
-		}
-		newCursor = cursor
-		output = gemini.FormatEntries(entries)
-	} else {
-		entries, cursor, err := codex.ReadTranscript(sessionFile, agent.Cursor)
-		if err != nil {
-			return fmt.Errorf("failed to read transcript: %w", err)
-		}
-		newCursor = cursor
-		output = codex.FormatEntries(entries)
+	events, newCursor, err := p.ReadTranscript(a.TranscriptPath, cursor)
+	if err != nil {
+		return fmt.Errorf("failed to read transcript: %w", err)
 	}
+	output := transcript.FormatText(events)
 
 	if output == "" {
-		fmt.Println("(no new output)")
+		fmt.Fprintln(w, "(no new output)")
 		return nil
 	}
 
 	// Update cursor
-	if err := database.UpdateCursor(name, newCursor); err != nil {
+	if spawned != nil {
+		err = database.UpdateCursor(spawned.Name, newCursor)
+	} else {
+		err = database.SetPeekCursor(a.ID, newCursor)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to update cursor: %w", err)
 	}
 
-	fmt.Print(output)
+	fmt.Fprint(w, output)
 	return nil
 }
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/provider"
+)
+
+// resolveAgent finds an agent by the name of a spawned agent or, inside a
+// repository, one of its Claude subagents by ID or description prefix.
+// Spawned agents are also returned as stored; Claude subagents have no row
+// and return nil. TranscriptPath is empty if a spawned agent's session file
+// hasn't been written yet.
+func resolveAgent(database *db.DB, nameOrID, repoRoot string) (agent.Agent, *db.Agent, error) {
+	spawned, err := database.GetAgent(nameOrID)
+	if err == nil {
+		a := spawned.ToUnified()
+		if a.TranscriptPath == "" {
+			p, err := provider.ForAgent(spawned)
+			if err != nil {
+				return agent.Agent{}, nil, err
+			}
+			a.TranscriptPath, _ = p.FindSessionFile(spawned.ULID)
+		}
+		return a, spawned, nil
+	}
+	if err != db.ErrAgentNotFound {
+		return agent.Agent{}, nil, err
+	}
+
+	if repoRoot != "" {
+		a, err := resolveClaudeAgent(nameOrID, repoRoot)
+		if err != nil || a.ID != "" {
+			return a, nil, err
+		}
+	}
+	return agent.Agent{}, nil, fmt.Errorf("agent %q not found", nameOrID)
+}
+
+// resolveClaudeAgent finds one of the repository's Claude subagents by exact
+// ID or, failing that, by a case-insensitive prefix of its description.
+// Returns a zero Agent if none matches, and an error if several do.
+func resolveClaudeAgent(idOrPrefix, repoRoot string) (agent.Agent, error) {
+	basePath, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return agent.Agent{}, err
+	}
+	// Without a database or rules ScanChannels returns every Claude agent
+	channels, err := claude.ScanChannels(claude.ClaudeProjectsDir(), basePath, filepath.Base(basePath), nil, nil)
+	if err != nil {
+		return agent.Agent{}, err
+	}
+
+	prefix := strings.ToLower(idOrPrefix)
+	var matches []agent.Agent
+	for _, ch := range channels {
+		for _, a := range ch.Agents {
+			if a.ID == idOrPrefix {
+				return a, nil
+			}
+			if a.Name != "" && strings.HasPrefix(strings.ToLower(a.Name), prefix) {
+				matches = append(matches, a)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return agent.Agent{}, nil
+	case 1:
+		return matches[0], nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d agents, use an agent ID:", idOrPrefix, len(matches))
+	for _, a := range matches {
+		fmt.Fprintf(&b, "\n  %s  %s", a.ID, a.Name)
+	}
+	return agent.Agent{}, errors.New(b.String())
+}
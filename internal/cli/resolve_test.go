@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/db"
+)
+
+// writeClaudeAgent writes a Claude subagent transcript for the repository
+// at repo, with the given description as its first prompt, and returns its
+// path. HOME must point at a temporary directory.
+func writeClaudeAgent(t *testing.T, repo, id, description string) string {
+	t.Helper()
+	projectDir := filepath.Join(claude.ClaudeProjectsDir(), claude.PathToProjectDir(repo))
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(projectDir, "agent-"+id+".jsonl")
+	line := `{"type":"user","message":{"role":"user","content":"` + description + `"},"timestamp":"2026-01-07T10:00:00Z"}` + "\n"
+	if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveAgent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	database := openTestDB(t)
+	defer database.Close()
+
+	if err := database.CreateAgent(db.Agent{Name: "fix-9c4f", ULID: "01J", SessionFile: "/tmp/fix.jsonl", Type: "codex"}); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := "/code/app"
+	path := writeClaudeAgent(t, repo, "a1b2c3", "Review the API")
+
+	a, spawned, err := resolveAgent(database, "fix-9c4f", repo)
+	if err != nil || a.ID != "01J" || a.TranscriptPath != "/tmp/fix.jsonl" || spawned == nil || spawned.Name != "fix-9c4f" {
+		t.Errorf("resolveAgent(name) = %+v, %+v, %v; want the spawned agent", a, spawned, err)
+	}
+
+	a, spawned, err = resolveAgent(database, "a1b2c3", repo)
+	if err != nil || a.Source != "claude" || a.TranscriptPath != path || spawned != nil {
+		t.Errorf("resolveAgent(id) = %+v, %+v, %v; want the Claude subagent", a, spawned, err)
+	}
+
+	if _, _, err := resolveAgent(database, "a1b2c3", ""); err == nil {
+		t.Error("Claude agent IDs should only resolve inside their repository")
+	}
+	if _, _, err := resolveAgent(database, "nope", repo); err == nil {
+		t.Error("unknown agent should fail")
+	}
+}
+
+func TestResolveAgent_DescriptionPrefix(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	database := openTestDB(t)
+	defer database.Close()
+
+	repo := "/code/app"
+	writeClaudeAgent(t, repo, "a1b2c3", "Review the API")
+	writeClaudeAgent(t, repo, "d4e5f6", "Review the schema")
+
+	a, _, err := resolveAgent(database, "review the a", repo)
+	if err != nil || a.ID != "a1b2c3" {
+		t.Errorf("resolveAgent(prefix) = %+v, %v; want a1b2c3", a, err)
+	}
+
+	_, _, err = resolveAgent(database, "Review", repo)
+	if err == nil || !strings.Contains(err.Error(), "a1b2c3") || !strings.Contains(err.Error(), "d4e5f6") {
+		t.Errorf("ambiguous prefix error = %v, want both candidates listed", err)
+	}
+}
+
+func TestRunPeek_ClaudeAgentCursor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".june"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", repo).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v: %s", err, out)
+	}
+	t.Chdir(repo)
+	path := writeClaudeAgent(t, repo, "a1b2c3", "Review the API")
+
+	var out bytes.Buffer
+	if err := runPeek(&out, "a1b2c3"); err != nil {
+		t.Fatalf("runPeek: %v", err)
+	}
+	if !strings.Contains(out.String(), "Review the API") {
+		t.Errorf("first peek = %q, want the prompt", out.String())
+	}
+
+	out.Reset()
+	if err := runPeek(&out, "Review"); err != nil {
+		t.Fatalf("runPeek: %v", err)
+	}
+	if got := out.String(); got != "(no new output)\n" {
+		t.Errorf("second peek = %q, want nothing new", got)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Looks good"}]},"timestamp":"2026-01-07T10:01:00Z"}` + "\n")
+	f.Close()
+
+	out.Reset()
+	if err := runPeek(&out, "a1b2c3"); err != nil {
+		t.Fatalf("runPeek: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "Looks good") || strings.Contains(got, "Review the API") {
+		t.Errorf("third peek = %q, want only the new reply", got)
+	}
+}
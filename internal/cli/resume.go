@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newResumeCmd() *cobra.Command {
+	var (
+		yolo   bool
+		detach bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "resume <name> <prompt>",
+		Aliases: []string{"send"},
+		Short:   "Send a follow-up prompt to an existing agent",
+		Long: `Continue a finished spawned agent with a follow-up prompt.
+
+The agent keeps its conversation context: Codex resumes the same thread,
+Gemini and Claude the same session. New output is appended to the agent's existing
+transcript, so peek picks up where it left off.
+
+Examples:
+  june resume refactor-9c4f "now add tests for that"
+  june send research-3b7a "summarize in 3 bullets" --detach`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if detach && !isSupervisor() {
+				return runDetached(nil)
+			}
+			return runResume(args[0], args[1], yolo)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&detach, "detach", "d", false, "Return as soon as the agent starts and keep it running in the background")
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "Auto-approve all actions (gemini and claude, default only auto-approves edits)")
+
+	return cmd
+}
+
+func runResume(name, prompt string, yolo bool) error {
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	a, err := database.GetAgent(name)
+	if err == db.ErrAgentNotFound {
+		return fmt.Errorf("agent %q not found", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Two processes writing the same session would interleave transcripts
+	if a.EffectiveStatus() == agent.StatusRunning {
+		return fmt.Errorf("agent %q is still running (use june wait first)", name)
+	}
+	if a.ULID == "" {
+		return fmt.Errorf("agent %q has no session ID to resume", name)
+	}
+
+	p, err := provider.ForAgent(a)
+	if err != nil {
+		return err
+	}
+
+	// Keep the settings the agent was spawned with (model, sandbox, approval mode)
+	opts := a.Options
+	opts.Yolo = opts.Yolo || yolo
+
+	return runResumeProvider(database, p, a, prompt, opts)
+}
+
+func runResumeProvider(database *db.DB, p provider.Provider, a *db.Agent, prompt string, opts db.SpawnOptions) error {
+	// Append recorded output so existing peek cursors (line counts) stay valid
+	var transcript *os.File
+	sessionFile := a.SessionFile
+	if recorder, ok := p.(provider.OutputRecorder); ok {
+		if sessionFile == "" {
+			var err error
+			sessionFile, err = recorder.TranscriptPath(a.ULID)
+			if err != nil {
+				return fmt.Errorf("failed to get session file path: %w", err)
+			}
+		}
+		f, err := os.OpenFile(sessionFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open session file: %w", err)
+		}
+		defer f.Close()
+		transcript = f
+	}
+
+	agentCmd, err := p.ResumeCommand(a.ULID, prompt, opts)
+	if err != nil {
+		return err
+	}
+	releaseFromSupervisor(agentCmd)
+	agentCmd.Stderr = os.Stderr
+	agentCmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	stdout, err := agentCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+
+	if err := agentCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", p.Name(), err)
+	}
+	defer forwardSignals(agentCmd.Process.Pid)()
+
+	if err := database.ResumeAgent(a.Name, agentCmd.Process.Pid); err != nil {
+		agentCmd.Process.Kill()
+		agentCmd.Wait()
+		return fmt.Errorf("failed to update agent record: %w", err)
+	}
+	if sessionFile != a.SessionFile {
+		if err := database.UpdateSessionFile(a.Name, sessionFile); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to update session file: %v\n", err)
+		}
+	}
+
+	notifyDetached(a.Name)
+
+	superviseOutput(database, p, a.Name, a.ULID, sessionFile, stdout, transcript)
+
+	waitErr := agentCmd.Wait()
+	if waitErr != nil {
+		fmt.Fprintf(os.Stderr, "%s exited with error: %v\n", p.Name(), waitErr)
+	}
+	recordErr := recordExit(database, a.Name, waitErr)
+
+	fmt.Println(a.Name)
+	return recordErr
+}
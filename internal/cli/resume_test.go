@@ -0,0 +1,19 @@
+package cli
+
+import "testing"
+
+func TestResumeCmd(t *testing.T) {
+	cmd := newResumeCmd()
+
+	if len(cmd.Aliases) != 1 || cmd.Aliases[0] != "send" {
+		t.Errorf("Aliases = %v, want [send]", cmd.Aliases)
+	}
+	for _, name := range []string{"detach", "yolo"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("flag --%s not found", name)
+		}
+	}
+	if err := cmd.Args(cmd, []string{"only-name"}); err == nil {
+		t.Error("resume with one arg should fail")
+	}
+}
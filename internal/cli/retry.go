@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// retryOptions holds the flags for june retry.
+type retryOptions struct {
+	Name   string
+	Model  string
+	Append string
+}
+
+func newRetryCmd() *cobra.Command {
+	var (
+		opts   retryOptions
+		detach bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retry <name>",
+		Short: "Respawn an agent with the same task and settings",
+		Long: `Spawn a fresh agent with the task and spawn options of an existing one.
+
+The new agent is linked to the original, and the TUI groups it under the
+original agent. By default it reuses the original name prefix.
+
+Examples:
+  june retry refactor-9c4f                          # Same task, same settings
+  june retry refactor-9c4f --model o3               # Try a different model
+  june retry refactor-9c4f --append "run the tests" # Add instructions`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if detach && !isSupervisor() {
+				return runDetached(nil)
+			}
+			return runRetry(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Name prefix for the new agent (defaults to the original's)")
+	cmd.Flags().StringVar(&opts.Model, "model", "", "Model to use instead of the original's")
+	cmd.Flags().StringVar(&opts.Append, "append", "", "Extra instructions appended to the original task")
+	cmd.Flags().BoolVarP(&detach, "detach", "d", false, "Return as soon as the agent starts and keep it running in the background")
+
+	return cmd
+}
+
+func runRetry(name string, opts retryOptions) error {
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	original, err := database.GetAgent(name)
+	database.Close()
+	if err == db.ErrAgentNotFound {
+		return fmt.Errorf("agent %q not found", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	task, spawnOpts, err := retrySpec(original, opts)
+	if err != nil {
+		return err
+	}
+
+	prefix := opts.Name
+	if prefix == "" {
+		prefix = namePrefix(original.Name)
+	}
+
+	// Retries of a retry are linked to the first agent so they group together
+	root := original.Name
+	if original.RetryOf != "" {
+		root = original.RetryOf
+	}
+
+	p, err := provider.ForAgent(original)
+	if err != nil {
+		return err
+	}
+	return runSpawn(p, prefix, task, spawnOpts, root)
+}
+
+// retrySpec returns the task and spawn options for retrying an agent.
+func retrySpec(original *db.Agent, opts retryOptions) (string, db.SpawnOptions, error) {
+	if original.Task == "" {
+		return "", db.SpawnOptions{}, fmt.Errorf("agent %q has no recorded task (spawned before tasks were stored)", original.Name)
+	}
+
+	task := original.Task
+	if opts.Append != "" {
+		task += "\n\n" + opts.Append
+	}
+
+	spawnOpts := original.Options
+	if opts.Model != "" {
+		spawnOpts.Model = opts.Model
+	}
+	return task, spawnOpts, nil
+}
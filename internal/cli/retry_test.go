@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sky-xo/june/internal/db"
+)
+
+func TestRetrySpec(t *testing.T) {
+	original := &db.Agent{
+		Name:    "refactor-9c4f",
+		Task:    "fix the tests",
+		Options: db.SpawnOptions{Model: "o4-mini", Sandbox: "read-only"},
+	}
+
+	task, opts, err := retrySpec(original, retryOptions{})
+	if err != nil {
+		t.Fatalf("retrySpec: %v", err)
+	}
+	if task != "fix the tests" {
+		t.Errorf("task = %q, want original task", task)
+	}
+	if !reflect.DeepEqual(opts, original.Options) {
+		t.Errorf("opts = %+v, want %+v", opts, original.Options)
+	}
+
+	task, opts, err = retrySpec(original, retryOptions{Model: "o3", Append: "run go test"})
+	if err != nil {
+		t.Fatalf("retrySpec: %v", err)
+	}
+	if task != "fix the tests\n\nrun go test" {
+		t.Errorf("task = %q, want appended instructions", task)
+	}
+	if opts.Model != "o3" || opts.Sandbox != "read-only" {
+		t.Errorf("opts = %+v, want model overridden and sandbox kept", opts)
+	}
+}
+
+func TestRetrySpec_NoTask(t *testing.T) {
+	if _, _, err := retrySpec(&db.Agent{Name: "old-1234"}, retryOptions{}); err == nil {
+		t.Error("retrySpec should fail for agents without a recorded task")
+	}
+}
+
+func TestRetryCmdFlags(t *testing.T) {
+	cmd := newRetryCmd()
+	for _, name := range []string{"name", "model", "append", "detach"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("flag --%s not found", name)
+		}
+	}
+}
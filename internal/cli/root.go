@@ -1,12 +1,14 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime/debug"
 
 	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/config"
 	"github.com/sky-xo/june/internal/scope"
 	"github.com/sky-xo/june/internal/tui"
 
@@ -44,56 +46,99 @@ func Version() string {
 	return "dev"
 }
 
+// exitError is returned by commands whose exit code carries meaning beyond
+// success/failure (e.g. june wait). Execute exits with its code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
 func Execute() {
+	var allProjects bool
 	rootCmd := &cobra.Command{
 		Use:     "june",
 		Short:   "Subagent viewer for Claude Code",
 		Version: Version(),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runWatch()
+			return runWatch(allProjects)
 		},
 	}
+	rootCmd.Flags().BoolVar(&allProjects, "all", false, "Show agents from every project, grouped by project")
 
 	rootCmd.AddCommand(newSpawnCmd())
 	rootCmd.AddCommand(newPeekCmd())
 	rootCmd.AddCommand(newLogsCmd())
+	rootCmd.AddCommand(newListCmd())
+	rootCmd.AddCommand(newKillCmd())
+	rootCmd.AddCommand(newWaitCmd())
+	rootCmd.AddCommand(newResumeCmd())
+	rootCmd.AddCommand(newRetryCmd())
+	rootCmd.AddCommand(newUsageCmd())
+	rootCmd.AddCommand(newSearchCmd())
+	rootCmd.AddCommand(newExportCmd())
 
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
-// runWatch starts the TUI.
-func runWatch() error {
+// runWatch starts the TUI. With allProjects it shows every project's agents
+// and works outside a git repository.
+func runWatch(allProjects bool) error {
 	if !term.IsTerminal(int(os.Stdout.Fd())) {
 		return fmt.Errorf("june requires a terminal")
 	}
 
 	// Get current git project root
 	repoRoot := scope.RepoRoot()
-	if repoRoot == "" {
-		return fmt.Errorf("not in a git repository")
+	if repoRoot == "" && !allProjects {
+		return fmt.Errorf("not in a git repository (use --all to show every project)")
 	}
 
-	// Get absolute path and repo name
-	basePath, err := filepath.Abs(repoRoot)
+	// Get Claude projects directory
+	claudeProjectsDir := claude.ClaudeProjectsDir()
+
+	var basePath, repoName string
+	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
-	repoName := filepath.Base(basePath)
+	if repoRoot != "" {
+		// Get absolute path and repo name
+		if basePath, err = filepath.Abs(repoRoot); err != nil {
+			return err
+		}
+		repoName = filepath.Base(basePath)
 
-	// Get Claude projects directory
-	claudeProjectsDir := claude.ClaudeProjectsDir()
+		// Check if any project directory exists for this repo
+		projectDir := claude.ProjectDir(basePath)
+		if _, err := os.Stat(projectDir); os.IsNotExist(err) && !allProjects {
+			return fmt.Errorf("no Claude Code sessions found for this project\n\nExpected: %s", projectDir)
+		}
 
-	// Check if any project directory exists for this repo
-	projectDir := claude.ProjectDir(basePath)
-	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
-		return fmt.Errorf("no Claude Code sessions found for this project\n\nExpected: %s", projectDir)
+		if cfg, err = config.LoadForRepo(basePath); err != nil {
+			return err
+		}
 	}
 
 	// Run TUI
 	model := tui.NewModel(claudeProjectsDir, basePath, repoName)
 	defer model.Close()
+	model.SetAllProjects(allProjects)
+	model.SetPrices(cfg.PriceTable())
+	model.SetRules(cfg.RuleSet())
+	if err := model.StartWatching(); err != nil {
+		// Without file notifications the TUI falls back to polling
+		model.SetStatus("File watching unavailable, polling instead: " + err.Error())
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err = p.Run()
 	return err
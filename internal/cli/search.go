@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/scope"
+	"github.com/sky-xo/june/internal/search"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// searchOptions holds the flags for june search.
+type searchOptions struct {
+	Repo  bool
+	Since string
+	Limit int
+}
+
+// searchResult is a row of june search --json output. The JSON field names
+// are part of the CLI's scripting interface, so keep them stable.
+type searchResult struct {
+	Name     string    `json:"name"` // Agent name, or ID for unnamed Claude subagents
+	Type     string    `json:"type"`
+	RepoPath string    `json:"repo_path"`
+	Branch   string    `json:"branch"`
+	Kind     string    `json:"kind"` // Entry type: user, message, reasoning or tool
+	Time     time.Time `json:"time"`
+	Snippet  string    `json:"snippet"`
+}
+
+func newSearchCmd() *cobra.Command {
+	var (
+		opts     searchOptions
+		jsonMode bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>...",
+		Short: "Search agent transcripts",
+		Long: `Search the transcripts of spawned agents and this repository's Claude
+subagents: messages, reasoning, tool names and tool inputs such as file paths
+and commands. Outside a repository only spawned agents are searched.
+
+Entries matching every term are shown, best matches first. A term ending in *
+matches a prefix. Transcripts are indexed in ~/.june/june.db as they are
+searched, so the first search takes longest.
+
+Examples:
+  june search "connection refused"     # Which agent hit this error?
+  june search internal/db/db.go --repo # Which agents in this repo touched this file?
+  june search migrat* --since 2d       # Prefix match in the last two days`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var since time.Time
+			if opts.Since != "" {
+				var err error
+				if since, err = parseSince(opts.Since, time.Now()); err != nil {
+					return err
+				}
+			}
+			cmd.SilenceUsage = true
+			return runSearch(cmd.OutOrStdout(), strings.Join(args, " "), opts, since, jsonMode)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Repo, "repo", false, "Only search agents in the current repository")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Only search entries since a duration ago (e.g. 2h, 3d) or a date (YYYY-MM-DD)")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "n", 20, "Maximum number of results (0 for all)")
+	cmd.Flags().BoolVar(&jsonMode, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func runSearch(w io.Writer, query string, opts searchOptions, since time.Time, jsonMode bool) error {
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	repoRoot := scope.RepoRoot()
+	if repoRoot == "" {
+		if opts.Repo {
+			return fmt.Errorf("not in a git repository")
+		}
+		fmt.Fprintln(os.Stderr, "note: outside a git repository only spawned agents are searched, not Claude subagents")
+	}
+
+	agents, err := searchableAgents(database, repoRoot)
+	if err != nil {
+		return err
+	}
+	if err := search.Update(database, agents); err != nil {
+		return err
+	}
+
+	q := search.Query{Text: query, Since: since, Limit: opts.Limit}
+	if opts.Repo {
+		if q.RepoPath, err = filepath.Abs(repoRoot); err != nil {
+			return err
+		}
+	}
+	hits, err := search.Search(database, q)
+	if err != nil {
+		return err
+	}
+
+	results := make([]searchResult, len(hits))
+	for i, h := range hits {
+		name := h.AgentName
+		if name == "" {
+			name = h.AgentID
+		}
+		results[i] = searchResult{
+			Name: name, Type: h.Source, RepoPath: h.RepoPath, Branch: h.Branch,
+			Kind: h.Kind, Time: h.Time, Snippet: h.Snippet,
+		}
+	}
+
+	if jsonMode {
+		for i := range results {
+			results[i].Snippet = highlightSnippet(results[i].Snippet, "", "")
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(w, "(no matches)")
+		return nil
+	}
+	start, end := "", ""
+	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		start, end = "\x1b[1m", "\x1b[22m" // Bold
+	}
+	writeSearchResults(w, results, start, end)
+	return nil
+}
+
+// searchableAgents returns the spawned agents in the database plus, inside a
+// repository, its Claude subagents, including those hide rules hide.
+func searchableAgents(database *db.DB, repoRoot string) ([]agent.Agent, error) {
+	dbAgents, err := database.ListAgents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	var agents []agent.Agent
+	for _, a := range dbAgents {
+		agents = append(agents, a.ToUnified())
+	}
+
+	if repoRoot == "" {
+		return agents, nil
+	}
+	basePath, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	// Without a database ScanChannels returns only Claude agents, which are
+	// the ones not listed above. Hide rules declutter the TUI; a search
+	// should still find the agents they hide.
+	channels, err := claude.ScanChannels(claude.ClaudeProjectsDir(), basePath, filepath.Base(basePath), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range channels {
+		agents = append(agents, ch.Agents...)
+	}
+	return agents, nil
+}
+
+// writeSearchResults prints each result as a heading line followed by its
+// snippet, with matches between start and end.
+func writeSearchResults(w io.Writer, results []searchResult, start, end string) {
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		where := r.Type
+		if r.Branch != "" {
+			where += ", " + r.Branch
+		}
+		fmt.Fprintf(w, "%s (%s) · %s · %s\n", r.Name, where, r.Kind, relativeTime(r.Time))
+		snippet := strings.Join(strings.Fields(highlightSnippet(r.Snippet, start, end)), " ")
+		fmt.Fprintf(w, "  %s\n", snippet)
+	}
+}
+
+// highlightSnippet replaces the match markers in a snippet with start and end.
+func highlightSnippet(snippet, start, end string) string {
+	return strings.NewReplacer(db.SnippetMatchStart, start, db.SnippetMatchEnd, end).Replace(snippet)
+}
+
+// parseSince parses a --since value: a duration before now such as "90m",
+// "2h" or "3d", or a date such as "2026-01-07" (midnight, local time).
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration such as 2h or 3d, or a date such as 2026-01-07", value)
+}
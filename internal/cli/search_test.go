@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/config"
+	"github.com/sky-xo/june/internal/db"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 1, 7, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2h", now.Add(-2 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"3d", now.AddDate(0, 0, -3)},
+		{"2026-01-01", time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"yesterday", "-d", "2026/01/01"} {
+		if _, err := parseSince(bad, now); err == nil {
+			t.Errorf("parseSince(%q) should fail", bad)
+		}
+	}
+}
+
+func TestWriteSearchResults(t *testing.T) {
+	results := []searchResult{{
+		Name:    "impl-9c4f",
+		Type:    "codex",
+		Branch:  "feature",
+		Kind:    "tool",
+		Time:    time.Now().Add(-5 * time.Minute),
+		Snippet: "shell_command\ngo test ./" + db.SnippetMatchStart + "internal/db" + db.SnippetMatchEnd + "/...",
+	}}
+
+	var buf bytes.Buffer
+	writeSearchResults(&buf, results, "[", "]")
+	got := buf.String()
+
+	if !strings.HasPrefix(got, "impl-9c4f (codex, feature) · tool · 5 minutes ago\n") {
+		t.Errorf("heading = %q", got)
+	}
+	// Snippets are flattened to one line
+	if !strings.Contains(got, "  shell_command go test ./[internal/db]/...\n") {
+		t.Errorf("snippet line missing or not highlighted:\n%s", got)
+	}
+}
+
+func TestSearchableAgents_IncludesHidden(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	database := openTestDB(t)
+	defer database.Close()
+
+	repo := t.TempDir()
+	rules := "[[rules]]\naction = \"hide\"\ndescription = \"^Review \"\n"
+	if err := os.WriteFile(filepath.Join(repo, config.RepoFile), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeClaudeAgent(t, repo, "a1b2c3", "Review the API")
+
+	agents, err := searchableAgents(database, repo)
+	if err != nil {
+		t.Fatalf("searchableAgents: %v", err)
+	}
+	if len(agents) != 1 || agents[0].ID != "a1b2c3" {
+		t.Errorf("searchableAgents = %+v, want the hidden Claude agent", agents)
+	}
+}
@@ -2,16 +2,21 @@ package cli
 
 import (
 	"bufio"
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/sky-xo/june/internal/codex"
+	"github.com/sky-xo/june/internal/agent"
 	"github.com/sky-xo/june/internal/db"
-	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/provider"
 	"github.com/sky-xo/june/internal/scope"
 	"github.com/spf13/cobra"
 )
@@ -24,12 +29,15 @@ func newSpawnCmd() *cobra.Command {
 		sandbox         string
 		reasoningEffort string
 		maxTokens       int
+		detach          bool
+		taskFile        string
+		contextFiles    []string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "spawn <type> <task>",
+		Use:   "spawn <type> [task]",
 		Short: "Spawn an agent",
-		Long: `Spawn a Codex or Gemini agent to perform a task.
+		Long: `Spawn a Codex, Gemini or Claude agent to perform a task.
 
 On success, prints the agent name to stdout (e.g., "swift-falcon-7d1e").
 Use this name with peek, logs, and other commands.
@@ -37,158 +45,196 @@ Use this name with peek, logs, and other commands.
 Naming: --name sets a prefix; if omitted, an adjective-noun is auto-generated.
 A 4-char suffix is always appended to ensure uniqueness.
 
+With --detach, the name is printed as soon as the agent has started and the
+agent keeps running in the background. Its output is logged to
+~/.june/logs/<name>.log.
+
+Long tasks can be read with --task-file instead, "-" meaning stdin. Each
+--context file is appended to the task under its path; images are attached
+with the CLI's own flag instead (Codex only). The composed task is what the
+agent is sent and what june records.
+
 Examples:
   june spawn codex "fix the tests" --name refactor  # Output: refactor-9c4f
   june spawn codex "add feature"                    # Output: swift-falcon-7d1e
+  june spawn codex "add feature" --detach           # Returns immediately
+  june spawn claude "review the diff" --model opus  # Claude Code, headless
+  june spawn codex --task-file plan.md --context internal/db/db.go
+  june spawn codex "match the mockup" --context mockup.png
   june peek swift-falcon-7d1e                       # Show new output`,
-		Args:  cobra.ExactArgs(2),
+		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			agentType := args[0]
-			task := args[1]
-
-			switch agentType {
-			case "codex":
-				// For Codex, if --sandbox was passed without value, default to workspace-write
-				codexSandbox := sandbox
-				if sandbox == "true" {
-					codexSandbox = "workspace-write"
-				}
-				return runSpawnCodex(name, task, model, reasoningEffort, codexSandbox, maxTokens)
-			case "gemini":
-				// Gemini sandbox is boolean-only, reject explicit values
-				if sandbox != "" && sandbox != "true" {
-					return fmt.Errorf("Gemini --sandbox does not accept values, use --sandbox without a value")
+
+			p, err := provider.Get(agentType)
+			if err != nil {
+				return err
+			}
+			rawTask, err := readTask(args[1:], taskFile, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			task, images, err := composeTask(rawTask, contextFiles)
+			if err != nil {
+				return err
+			}
+			opts, err := p.ResolveOptions(db.SpawnOptions{
+				Model:           model,
+				Sandbox:         sandbox,
+				ReasoningEffort: reasoningEffort,
+				MaxTokens:       maxTokens,
+				Yolo:            yolo,
+				Images:          images,
+			})
+			if err != nil {
+				return err
+			}
+
+			if detach && !isSupervisor() {
+				// The supervisor reads the task again, so hand it stdin
+				var stdin io.Reader
+				if taskFile == "-" {
+					stdin = strings.NewReader(rawTask)
 				}
-				geminiSandbox := cmd.Flags().Changed("sandbox")
-				return runSpawnGemini(name, task, model, yolo, geminiSandbox)
-			default:
-				return fmt.Errorf("unsupported agent type: %s (supported: codex, gemini)", agentType)
+				return runDetached(stdin)
 			}
+			return runSpawn(p, name, task, opts, "")
 		},
 	}
 
 	// Shared flags
 	cmd.Flags().StringVar(&name, "name", "", "Name prefix for the agent (auto-generated if omitted)")
 	cmd.Flags().StringVar(&model, "model", "", "Model to use")
-	cmd.Flags().StringVar(&sandbox, "sandbox", "", "Enable sandbox (Codex: optional value read-only|workspace-write|danger-full-access, defaults to workspace-write; Gemini: boolean)")
+	cmd.Flags().StringVar(&sandbox, "sandbox", "", "Enable sandbox (Codex: optional value read-only|workspace-write|danger-full-access, defaults to workspace-write; Gemini: boolean; not supported by Claude)")
 	cmd.Flags().Lookup("sandbox").NoOptDefVal = "true" // Allow --sandbox without value
+	cmd.Flags().BoolVarP(&detach, "detach", "d", false, "Return as soon as the agent starts and keep it running in the background")
+	cmd.Flags().StringVar(&taskFile, "task-file", "", "Read the task from a file (- for stdin) instead of an argument")
+	cmd.Flags().StringArrayVar(&contextFiles, "context", nil, "File to include with the task; repeatable. Images are attached (codex only)")
 
 	// Codex-specific flags
 	cmd.Flags().StringVar(&reasoningEffort, "reasoning-effort", "", "Reasoning effort (codex only)")
 	cmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Max output tokens (codex only)")
 
-	// Gemini-specific flags
-	cmd.Flags().BoolVar(&yolo, "yolo", false, "Auto-approve all actions (gemini only, default is auto_edit)")
+	// Gemini and Claude flags
+	cmd.Flags().BoolVar(&yolo, "yolo", false, "Auto-approve all actions (gemini and claude, default only auto-approves edits)")
 
 	return cmd
 }
 
-func runSpawnCodex(prefix, task string, model, reasoningEffort, sandbox string, maxTokens int) error {
+// runSpawn starts an agent with the given provider, records it, and supervises
+// it until it exits. retryOf names the agent this one retries, or is empty.
+func runSpawn(p provider.Provider, prefix, task string, opts db.SpawnOptions, retryOf string) error {
 	// Capture git context before spawning
 	// Non-fatal if not in a git repo - we just won't have channel info
 	repoPath := scope.RepoRoot()
 	branch := scope.BranchName()
 
-	// Open database
-	home, err := juneHome()
+	database, err := openDB()
 	if err != nil {
-		return fmt.Errorf("failed to get june home: %w", err)
-	}
-	dbPath := filepath.Join(home, "june.db")
-	database, err := db.Open(dbPath)
-	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return err
 	}
 	defer database.Close()
 
-	// Before creating the command, ensure isolated codex home
-	isolatedCodexHome, err := codex.EnsureCodexHome()
+	agentCmd, err := p.Command(task, opts)
 	if err != nil {
-		return fmt.Errorf("failed to setup isolated codex home: %w", err)
+		return err
 	}
+	releaseFromSupervisor(agentCmd)
+	agentCmd.Stderr = os.Stderr
+	// Own process group so `june kill` can take down the agent and its children
+	agentCmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
-	// Build codex command arguments dynamically
-	args := buildCodexArgs(task, model, reasoningEffort, sandbox, maxTokens)
-
-	// Start codex exec --json
-	codexCmd := exec.Command("codex", args...)
-	codexCmd.Stderr = os.Stderr
-	codexCmd.Env = append(os.Environ(), fmt.Sprintf("CODEX_HOME=%s", isolatedCodexHome))
-
-	stdout, err := codexCmd.StdoutPipe()
+	stdout, err := agentCmd.StdoutPipe()
 	if err != nil {
 		return fmt.Errorf("failed to get stdout pipe: %w", err)
 	}
 
-	if err := codexCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start codex: %w", err)
+	if err := agentCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", p.Name(), err)
 	}
+	defer forwardSignals(agentCmd.Process.Pid)()
 
-	// Read first line to get thread_id
-	scanner := bufio.NewScanner(stdout)
-	var threadID string
-	if scanner.Scan() {
-		var event struct {
-			Type     string `json:"type"`
-			ThreadID string `json:"thread_id"`
-		}
-		if err := json.Unmarshal(scanner.Bytes(), &event); err == nil {
-			if event.Type == "thread.started" {
-				threadID = event.ThreadID
-			}
-		}
+	// abort kills the agent when we can't supervise it
+	abort := func(format string, args ...any) error {
+		agentCmd.Process.Kill()
+		agentCmd.Wait() // Reap the killed process
+		return fmt.Errorf(format, args...)
 	}
 
-	if threadID == "" {
-		codexCmd.Process.Kill()
-		codexCmd.Wait() // Reap the killed process
-		return fmt.Errorf("failed to get thread_id from codex output")
+	// Read first line to get the session ID
+	// Use bufio.Reader instead of Scanner to handle arbitrarily large lines
+	reader := bufio.NewReader(stdout)
+	firstLine, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return abort("failed to read first line from %s: %w", p.Name(), err)
 	}
+	firstLine = bytes.TrimSuffix(firstLine, []byte("\n"))
 
-	// Resolve agent name using ULID (now that we have it)
-	name, err := resolveAgentNameWithULID(database, prefix, threadID)
-	if err != nil {
-		codexCmd.Process.Kill()
-		codexCmd.Wait()
-		return fmt.Errorf("failed to resolve agent name: %w", err)
+	sessionID := p.SessionID(firstLine)
+	if sessionID == "" {
+		return abort("failed to get session ID from %s output", p.Name())
 	}
 
-	// Find the session file
-	sessionFile, err := codex.FindSessionFile(threadID)
+	// Providers without their own transcript get stdout recorded to a file
+	var transcript *os.File
+	var sessionFile string
+	if recorder, ok := p.(provider.OutputRecorder); ok {
+		sessionFile, err = recorder.TranscriptPath(sessionID)
+		if err != nil {
+			return abort("failed to get session file path: %w", err)
+		}
+		transcript, err = os.Create(sessionFile)
+		if err != nil {
+			return abort("failed to create session file: %w", err)
+		}
+		defer transcript.Close()
+		if err := writeLine(transcript, firstLine); err != nil {
+			return abort("failed to write to session file: %w", err)
+		}
+	} else if found, err := p.FindSessionFile(sessionID); err == nil {
+		sessionFile = found
+	}
+	// Otherwise the session file might not exist yet; it's looked up below
+
+	// Resolve agent name using the session ID (now that we have it)
+	name, err := resolveAgentNameWithULID(database, prefix, sessionID)
 	if err != nil {
-		// Session file might not exist yet, construct expected path
-		// For now, we'll store it and look it up later
-		sessionFile = "" // Will be populated later
+		return abort("failed to resolve agent name: %w", err)
 	}
 
 	// Create agent record
-	agent := db.Agent{
+	record := db.Agent{
 		Name:        name,
-		ULID:        threadID,
+		ULID:        sessionID,
 		SessionFile: sessionFile,
-		PID:         codexCmd.Process.Pid,
+		PID:         agentCmd.Process.Pid,
 		RepoPath:    repoPath,
 		Branch:      branch,
-		Type:        "codex",
+		Type:        p.Name(),
+		Status:      agent.StatusRunning,
+		Task:        task,
+		Options:     opts,
+		RetryOf:     retryOf,
 	}
-	if err := database.CreateAgent(agent); err != nil {
-		return fmt.Errorf("failed to create agent record: %w", err)
+	if err := database.CreateAgent(record); err != nil {
+		return abort("failed to create agent record: %w", err)
 	}
 
-	// Drain remaining output (without printing)
-	for scanner.Scan() {
-		// Consume output silently
-	}
+	// When detached, the caller gets the name now while we keep supervising
+	notifyDetached(name)
+
+	superviseOutput(database, p, name, sessionID, sessionFile, reader, transcript)
 
 	// Wait for process to finish
-	if err := codexCmd.Wait(); err != nil {
-		fmt.Fprintf(os.Stderr, "codex exited with error: %v\n", err)
+	waitErr := agentCmd.Wait()
+	if waitErr != nil {
+		fmt.Fprintf(os.Stderr, "%s exited with error: %v\n", p.Name(), waitErr)
 	}
+	recordErr := recordExit(database, name, waitErr)
 
 	// Update session file if we didn't have it
 	if sessionFile == "" {
-		if found, err := codex.FindSessionFile(threadID); err == nil {
-			// Update the agent record with the session file
+		if found, err := p.FindSessionFile(sessionID); err == nil {
 			if err := database.UpdateSessionFile(name, found); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to update session file: %v\n", err)
 			}
@@ -198,221 +244,104 @@ func runSpawnCodex(prefix, task string, model, reasoningEffort, sandbox string,
 	// Print the agent name to confirm what was created
 	fmt.Println(name)
 
-	return nil
-}
-
-// buildGeminiArgs constructs the argument slice for the gemini command.
-// sandbox is a boolean - for Gemini we just pass --sandbox if true.
-func buildGeminiArgs(task, model string, yolo, sandbox bool) []string {
-	args := []string{"-p", task, "--output-format", "stream-json"}
-
-	if yolo {
-		args = append(args, "--yolo")
-	} else {
-		args = append(args, "--approval-mode", "auto_edit")
-	}
-
-	if model != "" {
-		args = append(args, "-m", model)
-	}
-
-	if sandbox {
-		args = append(args, "--sandbox")
-	}
-
-	return args
+	return recordErr
 }
 
-// geminiInstalled checks if the gemini CLI is available in PATH.
-func geminiInstalled() bool {
-	_, err := exec.LookPath("gemini")
-	return err == nil
-}
-
-func runSpawnGemini(prefix, task string, model string, yolo, sandbox bool) error {
-	// Check if gemini is installed
-	if !geminiInstalled() {
-		return fmt.Errorf("gemini CLI not found - install with: npm install -g @google/gemini-cli")
-	}
-
-	// Capture git context before spawning
-	repoPath := scope.RepoRoot()
-	branch := scope.BranchName()
-
-	// Open database
-	home, err := juneHome()
-	if err != nil {
-		return fmt.Errorf("failed to get june home: %w", err)
-	}
-	dbPath := filepath.Join(home, "june.db")
-	database, err := db.Open(dbPath)
-	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
-	}
-	defer database.Close()
-
-	// Ensure gemini home exists (copies auth files, creates sessions directory)
-	_, err = gemini.EnsureGeminiHome()
-	if err != nil {
-		return fmt.Errorf("failed to setup gemini home: %w", err)
-	}
-
-	// Build gemini command arguments
-	args := buildGeminiArgs(task, model, yolo, sandbox)
-
-	// Start gemini -p ...
-	geminiCmd := exec.Command("gemini", args...)
-	geminiCmd.Stderr = os.Stderr
-
-	stdout, err := geminiCmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("failed to get stdout pipe: %w", err)
-	}
-
-	if err := geminiCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start gemini: %w", err)
-	}
-
-	// Read first line to get session_id
-	// Use bufio.Reader instead of Scanner to handle arbitrarily large lines
-	reader := bufio.NewReader(stdout)
-	firstLine, err := reader.ReadBytes('\n')
-	if err != nil && err != io.EOF {
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to read first line from gemini: %w", err)
-	}
-
-	// Trim newline if present
-	if len(firstLine) > 0 && firstLine[len(firstLine)-1] == '\n' {
-		firstLine = firstLine[:len(firstLine)-1]
-	}
-
-	var sessionID string
-	var event struct {
-		Type      string `json:"type"`
-		SessionID string `json:"session_id"`
-	}
-	if err := json.Unmarshal(firstLine, &event); err == nil {
-		if event.Type == "init" {
-			sessionID = event.SessionID
+// superviseOutput consumes the agent's stdout until it closes. When transcript
+// is set, each line is recorded to it. Otherwise the output is discarded, and
+// the session file is stored as soon as the CLI creates it so peek/logs work
+// while the agent is running.
+func superviseOutput(database *db.DB, p provider.Provider, name, sessionID, sessionFile string, r io.Reader, transcript *os.File) {
+	streamErr := streamLines(r, func(line []byte) error {
+		if transcript != nil {
+			return writeLine(transcript, line)
 		}
-	}
-
-	if sessionID == "" {
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to get session_id from gemini output")
-	}
-
-	// Get session file path and create it
-	sessionFile, err := gemini.SessionFilePath(sessionID)
-	if err != nil {
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to get session file path: %w", err)
-	}
-
-	// Create session file and write first line
-	f, err := os.Create(sessionFile)
-	if err != nil {
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to create session file: %w", err)
-	}
-
-	// Write the buffered first line
-	if _, err := f.Write(firstLine); err != nil {
-		f.Close()
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to write to session file: %w", err)
-	}
-	if _, err := f.Write([]byte("\n")); err != nil {
-		f.Close()
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to write to session file: %w", err)
-	}
-
-	// Resolve agent name using session ID
-	name, err := resolveAgentNameWithULID(database, prefix, sessionID)
-	if err != nil {
-		f.Close()
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to resolve agent name: %w", err)
-	}
-
-	// Create agent record
-	agent := db.Agent{
-		Name:        name,
-		ULID:        sessionID,
-		SessionFile: sessionFile,
-		PID:         geminiCmd.Process.Pid,
-		RepoPath:    repoPath,
-		Branch:      branch,
-		Type:        "gemini",
-	}
-	if err := database.CreateAgent(agent); err != nil {
-		f.Close()
-		geminiCmd.Process.Kill()
-		geminiCmd.Wait()
-		return fmt.Errorf("failed to create agent record: %w", err)
-	}
-
-	// Stream remaining output to session file using streamLines (handles large lines)
-	var writeErr error
-	streamErr := streamLines(reader, func(line []byte) error {
-		if _, err := f.Write(line); err != nil {
-			return err
-		}
-		if _, err := f.Write([]byte("\n")); err != nil {
-			return err
+		if sessionFile == "" {
+			if found, err := p.FindSessionFile(sessionID); err == nil {
+				sessionFile = found
+				if err := database.UpdateSessionFile(name, found); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to update session file: %v\n", err)
+				}
+			}
 		}
 		return nil
 	})
 	if streamErr != nil {
-		writeErr = fmt.Errorf("error streaming gemini output: %w", streamErr)
-	}
-
-	// Check f.Close() error
-	if err := f.Close(); err != nil && writeErr == nil {
-		writeErr = fmt.Errorf("failed to close session file: %w", err)
+		fmt.Fprintf(os.Stderr, "warning: error writing session file: %v\n", streamErr)
+		// Keep draining so the agent doesn't block on a full pipe
+		io.Copy(io.Discard, r)
 	}
+}
 
-	if writeErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: error writing session file: %v\n", writeErr)
+// writeLine writes line followed by a newline.
+func writeLine(w io.Writer, line []byte) error {
+	if _, err := w.Write(line); err != nil {
+		return err
 	}
+	_, err := w.Write([]byte("\n"))
+	return err
+}
 
-	// Wait for process to finish
-	if err := geminiCmd.Wait(); err != nil {
-		fmt.Fprintf(os.Stderr, "gemini exited with error: %v\n", err)
+// forwardSignals relays SIGINT/SIGTERM to the agent's process group. The agent
+// runs in its own group, so Ctrl-C in the terminal would otherwise only reach
+// june. We keep running so the exit is recorded once the agent stops.
+// Call the returned function to stop forwarding.
+func forwardSignals(pid int) func() {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				_ = syscall.Kill(-pid, sig.(syscall.Signal))
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigs)
+		close(done)
 	}
-
-	// Print the agent name
-	fmt.Println(name)
-
-	return nil
 }
 
-// buildCodexArgs constructs the argument slice for the codex exec command.
-func buildCodexArgs(task, model, reasoningEffort, sandbox string, maxTokens int) []string {
-	args := []string{"exec", "--json"}
-	if model != "" {
-		args = append(args, "--model", model)
+// exitStatus maps the error returned by exec.Cmd.Wait to a lifecycle status,
+// exit code and error message. Agents terminated by a signal count as killed.
+func exitStatus(waitErr error) (status string, exitCode int, errMsg string) {
+	if waitErr == nil {
+		return agent.StatusSucceeded, 0, ""
 	}
-	if reasoningEffort != "" {
-		args = append(args, "-c", "model_reasoning_effort="+reasoningEffort)
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			return agent.StatusKilled, 128 + int(ws.Signal()), waitErr.Error()
+		}
+		return agent.StatusFailed, exitErr.ExitCode(), waitErr.Error()
 	}
-	if maxTokens > 0 {
-		args = append(args, "-c", fmt.Sprintf("model_max_output_tokens=%d", maxTokens))
+	return agent.StatusFailed, -1, waitErr.Error()
+}
+
+// recordExitBackoff is how long recordExit waits before each retry.
+var recordExitBackoff = []time.Duration{100 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second, 5 * time.Second}
+
+// recordExit stores the outcome of an agent's process in the database. Once
+// the process is gone a row left running reads as failed, so failed writes
+// are retried before giving up.
+func recordExit(database *db.DB, name string, waitErr error) error {
+	status, exitCode, errMsg := exitStatus(waitErr)
+	err := database.FinishAgent(name, status, exitCode, errMsg)
+	for _, delay := range recordExitBackoff {
+		if err == nil || errors.Is(err, db.ErrAgentNotFound) {
+			break
+		}
+		time.Sleep(delay)
+		err = database.FinishAgent(name, status, exitCode, errMsg)
 	}
-	if sandbox != "" {
-		args = append(args, "--sandbox", sandbox)
+	if err != nil {
+		return fmt.Errorf("failed to record agent exit: %w", err)
 	}
-	args = append(args, task)
-	return args
+	return nil
 }
 
 // streamLines reads lines from r and calls fn for each line.
@@ -446,3 +375,16 @@ func juneHome() (string, error) {
 	}
 	return filepath.Join(home, ".june"), nil
 }
+
+// openDB opens the june database at ~/.june/june.db.
+func openDB() (*db.DB, error) {
+	home, err := juneHome()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get june home: %w", err)
+	}
+	database, err := db.Open(filepath.Join(home, "june.db"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return database, nil
+}
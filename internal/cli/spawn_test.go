@@ -1,130 +1,18 @@
 package cli
 
 import (
+	"errors"
+	"os/exec"
 	"path/filepath"
-	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/sky-xo/june/internal/agent"
 	"github.com/sky-xo/june/internal/db"
 	"github.com/spf13/cobra"
 )
 
-func TestBuildCodexArgs(t *testing.T) {
-	tests := []struct {
-		name            string
-		task            string
-		model           string
-		reasoningEffort string
-		sandbox         string
-		maxTokens       int
-		want            []string
-	}{
-		{
-			name: "task only (no flags)",
-			task: "implement feature",
-			want: []string{"exec", "--json", "implement feature"},
-		},
-		{
-			name:  "with model flag",
-			task:  "implement feature",
-			model: "o3",
-			want:  []string{"exec", "--json", "--model", "o3", "implement feature"},
-		},
-		{
-			name:            "with reasoning-effort flag",
-			task:            "implement feature",
-			reasoningEffort: "high",
-			want:            []string{"exec", "--json", "-c", "model_reasoning_effort=high", "implement feature"},
-		},
-		{
-			name:      "with max-tokens flag",
-			task:      "implement feature",
-			maxTokens: 4096,
-			want:      []string{"exec", "--json", "-c", "model_max_output_tokens=4096", "implement feature"},
-		},
-		{
-			name:    "with sandbox flag",
-			task:    "implement feature",
-			sandbox: "workspace-write",
-			want:    []string{"exec", "--json", "--sandbox", "workspace-write", "implement feature"},
-		},
-		{
-			name:            "with all flags",
-			task:            "implement feature",
-			model:           "o3",
-			reasoningEffort: "medium",
-			maxTokens:       8192,
-			sandbox:         "read-only",
-			want: []string{
-				"exec", "--json",
-				"--model", "o3",
-				"-c", "model_reasoning_effort=medium",
-				"-c", "model_max_output_tokens=8192",
-				"--sandbox", "read-only",
-				"implement feature",
-			},
-		},
-		{
-			name:            "with model and reasoning-effort",
-			task:            "fix bug",
-			model:           "gpt-4",
-			reasoningEffort: "low",
-			want: []string{
-				"exec", "--json",
-				"--model", "gpt-4",
-				"-c", "model_reasoning_effort=low",
-				"fix bug",
-			},
-		},
-		{
-			name:      "with sandbox and max-tokens",
-			task:      "refactor code",
-			maxTokens: 2048,
-			sandbox:   "danger-full-access",
-			want: []string{
-				"exec", "--json",
-				"-c", "model_max_output_tokens=2048",
-				"--sandbox", "danger-full-access",
-				"refactor code",
-			},
-		},
-		{
-			name:      "zero max-tokens is ignored",
-			task:      "implement feature",
-			maxTokens: 0,
-			want:      []string{"exec", "--json", "implement feature"},
-		},
-		{
-			name:  "empty model is ignored",
-			task:  "implement feature",
-			model: "",
-			want:  []string{"exec", "--json", "implement feature"},
-		},
-		{
-			name:            "empty reasoning-effort is ignored",
-			task:            "implement feature",
-			reasoningEffort: "",
-			want:            []string{"exec", "--json", "implement feature"},
-		},
-		{
-			name:    "empty sandbox is ignored",
-			task:    "implement feature",
-			sandbox: "",
-			want:    []string{"exec", "--json", "implement feature"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := buildCodexArgs(tt.task, tt.model, tt.reasoningEffort, tt.sandbox, tt.maxTokens)
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("buildCodexArgs() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestSpawnCmdFlags(t *testing.T) {
 	cmd := newSpawnCmd()
 
@@ -138,6 +26,9 @@ func TestSpawnCmdFlags(t *testing.T) {
 		{"reasoning-effort", "string"},
 		{"max-tokens", "int"},
 		{"sandbox", "string"},
+		{"detach", "bool"},
+		{"task-file", "string"},
+		{"context", "stringArray"},
 	}
 
 	for _, f := range flags {
@@ -166,6 +57,9 @@ func TestSpawnCmdFlagDefaults(t *testing.T) {
 		{"reasoning-effort", ""},
 		{"max-tokens", "0"},
 		{"sandbox", ""},
+		{"detach", "false"},
+		{"task-file", ""},
+		{"context", "[]"},
 	}
 
 	for _, tt := range tests {
@@ -223,6 +117,45 @@ func TestSpawnCmdFlagParsing(t *testing.T) {
 			args:    []string{"gemini", "task", "--sandbox", "--name", "test"},
 			wantErr: false,
 		},
+		{
+			name:    "detach short flag",
+			args:    []string{"codex", "task", "-d"},
+			wantErr: false,
+		},
+		{
+			name:    "task from file",
+			args:    []string{"codex", "--task-file", "plan.md", "--context", "a.go", "--context", "b.go"},
+			wantErr: false,
+		},
+		{
+			name:    "too many arguments",
+			args:    []string{"codex", "task", "extra"},
+			wantErr: true,
+		},
+		{
+			name:          "task argument and task file errors",
+			args:          []string{"codex", "task", "--task-file", "plan.md"},
+			wantErr:       true,
+			runValidation: true,
+		},
+		{
+			name:          "missing task errors",
+			args:          []string{"codex"},
+			wantErr:       true,
+			runValidation: true,
+		},
+		{
+			name:          "detach with unsupported type errors",
+			args:          []string{"amp", "task", "--detach"},
+			wantErr:       true,
+			runValidation: true,
+		},
+		{
+			name:          "claude sandbox errors",
+			args:          []string{"claude", "task", "--sandbox"},
+			wantErr:       true,
+			runValidation: true,
+		},
 		{
 			name:          "gemini sandbox with explicit value errors",
 			args:          []string{"gemini", "task", "--sandbox=read-only"},
@@ -318,63 +251,61 @@ func TestStreamLines(t *testing.T) {
 	}
 }
 
-func TestBuildGeminiArgs(t *testing.T) {
-	tests := []struct {
-		name    string
-		task    string
-		model   string
-		yolo    bool
-		sandbox bool
-		want    []string
-	}{
-		{
-			name:    "basic task with defaults",
-			task:    "fix the bug",
-			model:   "",
-			yolo:    false,
-			sandbox: false,
-			want:    []string{"-p", "fix the bug", "--output-format", "stream-json", "--approval-mode", "auto_edit"},
-		},
-		{
-			name:    "with yolo mode",
-			task:    "refactor code",
-			model:   "",
-			yolo:    true,
-			sandbox: false,
-			want:    []string{"-p", "refactor code", "--output-format", "stream-json", "--yolo"},
-		},
-		{
-			name:    "with model",
-			task:    "write tests",
-			model:   "gemini-2.5-pro",
-			yolo:    false,
-			sandbox: false,
-			want:    []string{"-p", "write tests", "--output-format", "stream-json", "--approval-mode", "auto_edit", "-m", "gemini-2.5-pro"},
-		},
-		{
-			name:    "with sandbox",
-			task:    "dangerous task",
-			model:   "",
-			yolo:    true,
-			sandbox: true,
-			want:    []string{"-p", "dangerous task", "--output-format", "stream-json", "--yolo", "--sandbox"},
-		},
-		{
-			name:    "all options",
-			task:    "full task",
-			model:   "gemini-2.5-flash",
-			yolo:    true,
-			sandbox: true,
-			want:    []string{"-p", "full task", "--output-format", "stream-json", "--yolo", "-m", "gemini-2.5-flash", "--sandbox"},
-		},
+func TestExitStatus(t *testing.T) {
+	// Success
+	status, code, msg := exitStatus(nil)
+	if status != agent.StatusSucceeded || code != 0 || msg != "" {
+		t.Errorf("exitStatus(nil) = (%q, %d, %q), want (succeeded, 0, \"\")", status, code, msg)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := buildGeminiArgs(tt.task, tt.model, tt.yolo, tt.sandbox)
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("buildGeminiArgs() = %v, want %v", got, tt.want)
-			}
-		})
+	// Non-zero exit
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	status, code, msg = exitStatus(err)
+	if status != agent.StatusFailed || code != 3 || msg == "" {
+		t.Errorf("exitStatus(exit 3) = (%q, %d, %q), want (failed, 3, non-empty)", status, code, msg)
+	}
+
+	// Killed by signal
+	err = exec.Command("sh", "-c", "kill -TERM $$").Run()
+	status, code, _ = exitStatus(err)
+	if status != agent.StatusKilled || code != 128+15 {
+		t.Errorf("exitStatus(SIGTERM) = (%q, %d), want (killed, 143)", status, code)
+	}
+
+	// Non-exit errors (e.g. I/O) are failures with unknown exit code
+	status, code, _ = exitStatus(errors.New("boom"))
+	if status != agent.StatusFailed || code != -1 {
+		t.Errorf("exitStatus(other) = (%q, %d), want (failed, -1)", status, code)
+	}
+}
+
+func TestRecordExit_RetriesFailedWrites(t *testing.T) {
+	database := openTestDB(t)
+	defer database.Close()
+	if err := database.CreateAgent(db.Agent{Name: "impl-9c4f", ULID: "01J", SessionFile: "/tmp/s.jsonl"}); err != nil {
+		t.Fatal(err)
+	}
+	backoff := recordExitBackoff
+	recordExitBackoff = []time.Duration{10 * time.Millisecond, 50 * time.Millisecond, 200 * time.Millisecond}
+	t.Cleanup(func() { recordExitBackoff = backoff })
+
+	// Make writes fail until the table comes back
+	if _, err := database.Exec(`ALTER TABLE agents RENAME TO agents_away`); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		database.Exec(`ALTER TABLE agents_away RENAME TO agents`)
+	}()
+
+	if err := recordExit(database, "impl-9c4f", nil); err != nil {
+		t.Fatalf("recordExit = %v, want it to succeed on a retry", err)
+	}
+	if a, err := database.GetAgent("impl-9c4f"); err != nil || a.Status != agent.StatusSucceeded {
+		t.Errorf("agent = %+v, %v; want succeeded", a, err)
+	}
+
+	if err := recordExit(database, "nope", nil); !errors.Is(err, db.ErrAgentNotFound) {
+		t.Errorf("recordExit(unknown) = %v, want ErrAgentNotFound", err)
 	}
 }
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// imageExtensions are the context files attached as images instead of being
+// inlined into the task.
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+}
+
+// readTask returns the task given as an argument or, with taskFile, read
+// from that file; "-" reads stdin.
+func readTask(args []string, taskFile string, stdin io.Reader) (string, error) {
+	if taskFile == "" {
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing task: pass it as an argument or with --task-file")
+		}
+		return args[0], nil
+	}
+	if len(args) > 0 {
+		return "", fmt.Errorf("pass the task as an argument or with --task-file, not both")
+	}
+
+	var data []byte
+	var err error
+	if taskFile == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(taskFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read task: %w", err)
+	}
+	task := strings.TrimSpace(string(data))
+	if task == "" {
+		return "", fmt.Errorf("task is empty")
+	}
+	return task, nil
+}
+
+// composeTask appends the contents of the text files among contextFiles to
+// task, each under its path. Image files are returned as absolute paths for
+// the CLI to attach.
+func composeTask(task string, contextFiles []string) (string, []string, error) {
+	var b strings.Builder
+	b.WriteString(task)
+	var images []string
+	for _, path := range contextFiles {
+		if imageExtensions[strings.ToLower(filepath.Ext(path))] {
+			if _, err := os.Stat(path); err != nil {
+				return "", nil, fmt.Errorf("failed to read context file: %w", err)
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return "", nil, err
+			}
+			images = append(images, abs)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read context file: %w", err)
+		}
+		if !utf8.Valid(data) {
+			return "", nil, fmt.Errorf("context file %s is not text or a supported image", path)
+		}
+		fmt.Fprintf(&b, "\n\n## %s\n\n", path)
+		writeFenced(&b, strings.TrimRight(string(data), "\n"))
+	}
+	return b.String(), images, nil
+}
+
+// writeFenced writes content in a code fence longer than any run of
+// backticks it contains.
+func writeFenced(b *strings.Builder, content string) {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s\n%s\n%s", fence, content, fence)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.md")
+	if err := os.WriteFile(path, []byte("# Plan\n\n1. Fix the tests\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		taskFile string
+		stdin    string
+		want     string
+		wantErr  bool
+	}{
+		{name: "argument", args: []string{"fix the tests"}, want: "fix the tests"},
+		{name: "file", taskFile: path, want: "# Plan\n\n1. Fix the tests"},
+		{name: "stdin", taskFile: "-", stdin: "fix the tests\n", want: "fix the tests"},
+		{name: "missing", wantErr: true},
+		{name: "both", args: []string{"fix the tests"}, taskFile: path, wantErr: true},
+		{name: "empty stdin", taskFile: "-", stdin: "\n", wantErr: true},
+		{name: "missing file", taskFile: path + ".missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTask(tt.args, tt.taskFile, strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readTask() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposeTask(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	files := map[string]string{
+		"db.go":      "package db\n",
+		"README.md":  "Run:\n\n```sh\ngo test\n```\n",
+		"mockup.png": "\x89PNG\r\n\x1a\n",
+		"blob.bin":   "\xff\xfe\x00",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	task, images, err := composeTask("fix the tests", []string{"db.go", "mockup.png", "README.md"})
+	if err != nil {
+		t.Fatalf("composeTask: %v", err)
+	}
+	want := "fix the tests\n\n## db.go\n\n```\npackage db\n```\n\n## README.md\n\n````\nRun:\n\n```sh\ngo test\n```\n````"
+	if task != want {
+		t.Errorf("task = %q, want %q", task, want)
+	}
+	if len(images) != 1 || images[0] != filepath.Join(dir, "mockup.png") {
+		t.Errorf("images = %v, want the absolute mockup path", images)
+	}
+
+	if _, _, err := composeTask("fix the tests", []string{"blob.bin"}); err == nil {
+		t.Error("binary context files should be rejected")
+	}
+	if _, _, err := composeTask("fix the tests", []string{"missing.go"}); err == nil {
+		t.Error("missing context files should be rejected")
+	}
+}
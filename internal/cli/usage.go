@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/config"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/scope"
+	"github.com/sky-xo/june/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+// Ways june usage can group totals.
+const (
+	usageByAgent  = "agent"
+	usageByBranch = "branch"
+	usageByDay    = "day"
+)
+
+// agentUsage is an agent with the usage records read from its transcript.
+type agentUsage struct {
+	Agent   agent.Agent
+	Records []usage.Record
+}
+
+// usageRow is a row of june usage output. The JSON field names are part of
+// the CLI's scripting interface, so keep them stable.
+type usageRow struct {
+	Name   string `json:"name,omitempty"`   // --by agent
+	Type   string `json:"type,omitempty"`   // --by agent
+	Branch string `json:"branch,omitempty"` // --by agent, --by branch
+	Day    string `json:"day,omitempty"`    // --by day, as YYYY-MM-DD
+	Agents int    `json:"agents"`
+	usage.Tokens
+	Cost     float64 `json:"cost_usd"`
+	Unpriced bool    `json:"unpriced,omitempty"` // Cost leaves out tokens from models without a price
+}
+
+func newUsageCmd() *cobra.Command {
+	var (
+		by       string
+		jsonMode bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show token usage and estimated cost",
+		Long: `Show the tokens used by the agents in this repository (Claude subagents and
+spawned agents) and their estimated cost, per agent, branch or day.
+
+Costs are estimated from list prices per million tokens. Add or override
+prices in ~/.june/config.toml:
+
+  [prices."claude-sonnet-4"]
+  input = 3
+  output = 15
+  cache_read = 0.3
+  cache_write = 3.75
+
+A "+" after a cost means some tokens are from models without a price.
+
+Examples:
+  june usage                # Per agent, most expensive first
+  june usage --by branch    # Per branch
+  june usage --by day       # Per day
+  june usage --json         # Machine-readable output`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch by {
+			case usageByAgent, usageByBranch, usageByDay:
+			default:
+				return fmt.Errorf("--by must be agent, branch or day, got %q", by)
+			}
+			return runUsage(cmd.OutOrStdout(), by, jsonMode)
+		},
+	}
+
+	cmd.Flags().StringVar(&by, "by", usageByAgent, "Group totals by agent, branch or day")
+	cmd.Flags().BoolVar(&jsonMode, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func runUsage(w io.Writer, by string, jsonMode bool) error {
+	repoRoot := scope.RepoRoot()
+	if repoRoot == "" {
+		return fmt.Errorf("not in a git repository")
+	}
+	basePath, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadForRepo(basePath)
+	if err != nil {
+		return err
+	}
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	// No rules: hidden agents still cost tokens
+	channels, err := claude.ScanChannels(claude.ClaudeProjectsDir(), basePath, filepath.Base(basePath), database, nil)
+	if err != nil {
+		return err
+	}
+
+	var agents []agentUsage
+	for _, ch := range channels {
+		for _, a := range ch.Agents {
+			records, err := readAgentUsage(a)
+			if err != nil {
+				continue // Transcript gone or unreadable
+			}
+			agents = append(agents, agentUsage{Agent: a, Records: records})
+		}
+	}
+
+	rows := summarizeUsage(agents, by, cfg.PriceTable())
+
+	if jsonMode {
+		if rows == nil {
+			rows = []usageRow{} // Emit [] rather than null
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "(no usage recorded)")
+		return nil
+	}
+	return writeUsageTable(w, rows, by)
+}
+
+// readAgentUsage reads the usage records in a's transcript. Records without
+// a time are dated to the agent's last activity.
+func readAgentUsage(a agent.Agent) ([]usage.Record, error) {
+	source := a.Source
+	if source == "" {
+		source = agent.SourceClaude
+	}
+	p, err := provider.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	records, err := p.ReadUsage(a.TranscriptPath)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].Time.IsZero() {
+			records[i].Time = a.LastActivity
+		}
+	}
+	return records, nil
+}
+
+// summarizeUsage totals the agents' usage by agent, branch or day. Agent
+// and branch rows are sorted most expensive first, day rows by date.
+// Agents without usage are left out.
+func summarizeUsage(agents []agentUsage, by string, prices usage.Prices) []usageRow {
+	rowIdx := make(map[string]int) // Group key -> index in rows
+	var rows []usageRow
+	add := func(key string, proto usageRow, records []usage.Record) {
+		s := prices.Summarize(records)
+		if s.Total() == 0 {
+			return
+		}
+		i, ok := rowIdx[key]
+		if !ok {
+			i = len(rows)
+			rowIdx[key] = i
+			rows = append(rows, proto)
+		}
+		rows[i].Agents++
+		rows[i].Tokens = rows[i].Tokens.Add(s.Tokens)
+		rows[i].Cost += s.Cost
+		rows[i].Unpriced = rows[i].Unpriced || s.Unpriced
+	}
+
+	for _, au := range agents {
+		a := au.Agent
+		switch by {
+		case usageByAgent:
+			add(a.Source+"/"+a.ID, usageRow{Name: a.DisplayName(), Type: a.Source, Branch: a.Branch}, au.Records)
+		case usageByBranch:
+			add(a.Branch, usageRow{Branch: a.Branch}, au.Records)
+		case usageByDay:
+			days, byDay := usage.ByDay(au.Records)
+			for _, day := range days {
+				add(day, usageRow{Day: day}, byDay[day])
+			}
+		}
+	}
+
+	if by == usageByDay {
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Day < rows[j].Day })
+	} else {
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].Cost != rows[j].Cost {
+				return rows[i].Cost > rows[j].Cost
+			}
+			return rows[i].Total() > rows[j].Total()
+		})
+	}
+	return rows
+}
+
+// writeUsageTable prints usage rows as aligned columns, with a total.
+func writeUsageTable(w io.Writer, rows []usageRow, by string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	switch by {
+	case usageByAgent:
+		fmt.Fprint(tw, "NAME\tTYPE\tBRANCH\t")
+	case usageByBranch:
+		fmt.Fprint(tw, "BRANCH\tAGENTS\t")
+	case usageByDay:
+		fmt.Fprint(tw, "DAY\tAGENTS\t")
+	}
+	fmt.Fprintln(tw, "INPUT\tOUTPUT\tCACHE READ\tCACHE WRITE\tCOST")
+
+	var total usage.Summary
+	for _, r := range rows {
+		switch by {
+		case usageByAgent:
+			fmt.Fprintf(tw, "%s\t%s\t%s\t", r.Name, r.Type, orDash(r.Branch))
+		case usageByBranch:
+			fmt.Fprintf(tw, "%s\t%d\t", orDash(r.Branch), r.Agents)
+		case usageByDay:
+			fmt.Fprintf(tw, "%s\t%d\t", r.Day, r.Agents)
+		}
+		s := usage.Summary{Tokens: r.Tokens, Cost: r.Cost, Unpriced: r.Unpriced}
+		writeUsageColumns(tw, s)
+		total = total.Add(s)
+	}
+
+	if len(rows) > 1 {
+		fmt.Fprint(tw, "TOTAL\t\t")
+		if by == usageByAgent {
+			fmt.Fprint(tw, "\t")
+		}
+		writeUsageColumns(tw, total)
+	}
+	return tw.Flush()
+}
+
+// writeUsageColumns prints the token and cost columns of a usage row.
+func writeUsageColumns(w io.Writer, s usage.Summary) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		usage.FormatTokens(s.Input), usage.FormatTokens(s.Output),
+		usage.FormatTokens(s.CacheRead), usage.FormatTokens(s.CacheWrite), usage.FormatCost(s))
+}
+
+// orDash returns s, or "-" if it's empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func testAgentUsage() []agentUsage {
+	day1 := time.Date(2026, 1, 7, 12, 0, 0, 0, time.Local)
+	day2 := day1.Add(24 * time.Hour)
+	return []agentUsage{
+		{
+			Agent: agent.Agent{ID: "a1", Name: "explore-auth", Source: "claude", Branch: "main"},
+			Records: []usage.Record{
+				{Time: day1, Model: "claude-sonnet-4-5", Tokens: usage.Tokens{Input: 1_000_000}},
+			},
+		},
+		{
+			Agent: agent.Agent{ID: "01J", Name: "impl-9c4f", Source: "codex", Branch: "feature"},
+			Records: []usage.Record{
+				{Time: day1, Model: "gpt-5-codex", Tokens: usage.Tokens{Output: 1_000_000}},
+				{Time: day2, Model: "gpt-5-codex", Tokens: usage.Tokens{Output: 1_000_000}},
+			},
+		},
+		{
+			Agent: agent.Agent{ID: "a2", Name: "idle", Source: "claude", Branch: "main"},
+		},
+	}
+}
+
+func testPrices() usage.Prices {
+	return usage.Prices{"claude-sonnet-4": {Input: 3}, "gpt-5": {Output: 10}}
+}
+
+func TestSummarizeUsageByAgent(t *testing.T) {
+	rows := summarizeUsage(testAgentUsage(), usageByAgent, testPrices())
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (agents without usage are left out)", len(rows))
+	}
+	if rows[0].Name != "impl-9c4f" || rows[0].Cost != 20 {
+		t.Errorf("rows[0] = %+v, want impl-9c4f costing $20 first", rows[0])
+	}
+	if rows[1].Name != "explore-auth" || rows[1].Type != "claude" || rows[1].Cost != 3 {
+		t.Errorf("rows[1] = %+v, want explore-auth costing $3", rows[1])
+	}
+}
+
+func TestSummarizeUsageByBranch(t *testing.T) {
+	agents := append(testAgentUsage(), agentUsage{
+		Agent:   agent.Agent{ID: "a3", Source: "claude", Branch: "main"},
+		Records: []usage.Record{{Model: "claude-sonnet-4-5", Tokens: usage.Tokens{Input: 1_000_000}}},
+	})
+	rows := summarizeUsage(agents, usageByBranch, testPrices())
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	main := rows[1]
+	if main.Branch != "main" || main.Agents != 2 || main.Input != 2_000_000 || main.Cost != 6 {
+		t.Errorf("main row = %+v, want 2 agents, 2M input, $6", main)
+	}
+}
+
+func TestSummarizeUsageByDay(t *testing.T) {
+	rows := summarizeUsage(testAgentUsage(), usageByDay, testPrices())
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0].Day != "2026-01-07" || rows[0].Agents != 2 || rows[0].Cost != 13 {
+		t.Errorf("rows[0] = %+v, want 2026-01-07 with 2 agents costing $13", rows[0])
+	}
+	if rows[1].Day != "2026-01-08" || rows[1].Agents != 1 || rows[1].Cost != 10 {
+		t.Errorf("rows[1] = %+v, want 2026-01-08 with 1 agent costing $10", rows[1])
+	}
+}
+
+func TestWriteUsageTable(t *testing.T) {
+	rows := summarizeUsage(testAgentUsage(), usageByAgent, testPrices())
+
+	var buf bytes.Buffer
+	if err := writeUsageTable(&buf, rows, usageByAgent); err != nil {
+		t.Fatalf("writeUsageTable: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want header, 2 rows and a total:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "NAME") || !strings.Contains(lines[0], "CACHE WRITE") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "impl-9c4f") || !strings.Contains(lines[1], "2.0M") {
+		t.Errorf("first row = %q, want impl-9c4f with 2.0M output tokens", lines[1])
+	}
+	if !strings.HasPrefix(lines[3], "TOTAL") || !strings.HasSuffix(lines[3], "$23.00") {
+		t.Errorf("total = %q, want TOTAL ... $23.00", lines[3])
+	}
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// waitPollInterval is how often june wait re-checks agent status.
+	waitPollInterval = 500 * time.Millisecond
+	// exitCodeTimeout matches timeout(1) so scripts can tell a timeout apart
+	// from an agent failure.
+	exitCodeTimeout = 124
+	// statusExited is reported for agents spawned before status tracking
+	// existed, whose outcome is unknown.
+	statusExited = "exited"
+)
+
+// waitOptions holds the flags for june wait.
+type waitOptions struct {
+	Any     bool
+	Timeout time.Duration
+	Print   bool
+}
+
+// waitResult is the terminal state of an agent june wait was waiting for.
+type waitResult struct {
+	Agent  db.Agent
+	Status string
+}
+
+func newWaitCmd() *cobra.Command {
+	var (
+		opts waitOptions
+		all  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait <name>...",
+		Short: "Wait for agents to finish",
+		Long: `Block until the named agents reach a terminal state (succeeded, failed or killed).
+
+By default june wait returns once all agents have finished (--all). With --any
+it returns as soon as the first one finishes.
+
+Exit codes:
+  0    every agent waited for succeeded
+  1    at least one agent failed or was killed
+  124  --timeout elapsed first
+
+Examples:
+  june wait impl-9c4f review-3b7a           # Wait for both
+  june wait impl-9c4f review-3b7a --any     # Wait for whichever finishes first
+  june wait impl-9c4f --timeout 10m --print # Print the final message`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Any && all {
+				return fmt.Errorf("--any and --all are mutually exclusive")
+			}
+			cmd.SilenceUsage = true
+			return runWait(cmd.OutOrStdout(), args, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Any, "any", false, "Return when any agent finishes")
+	cmd.Flags().BoolVar(&all, "all", false, "Return when all agents finish (default)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, "Give up after this long (0 waits forever)")
+	cmd.Flags().BoolVarP(&opts.Print, "print", "p", false, "Print each agent's final assistant message")
+
+	return cmd
+}
+
+func runWait(w io.Writer, names []string, opts waitOptions) error {
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	// Fail fast on typos rather than waiting forever
+	for _, name := range names {
+		if _, err := database.GetAgent(name); err == db.ErrAgentNotFound {
+			return fmt.Errorf("agent %q not found", name)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	results, timedOut, err := waitForAgents(names, opts, database.GetAgent, waitPollInterval)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		fmt.Fprintf(w, "%s: %s\n", r.Agent.Name, formatWaitStatus(r))
+		if opts.Print {
+			msg, err := finalMessage(r.Agent)
+			if err != nil {
+				fmt.Fprintf(w, "  (no final message: %v)\n", err)
+			} else if msg != "" {
+				fmt.Fprintln(w, msg)
+			}
+		}
+	}
+
+	if timedOut {
+		return &exitError{code: exitCodeTimeout, err: fmt.Errorf("timed out after %s", opts.Timeout)}
+	}
+	for _, r := range results {
+		if r.Status == agent.StatusFailed || r.Status == agent.StatusKilled {
+			return &exitError{code: 1, err: fmt.Errorf("agent %q %s", r.Agent.Name, r.Status)}
+		}
+	}
+	return nil
+}
+
+// waitForAgents polls until the agents finish (all of them, or the first with
+// opts.Any) or opts.Timeout elapses. Results are in order of completion.
+func waitForAgents(names []string, opts waitOptions, lookup func(name string) (*db.Agent, error), interval time.Duration) ([]waitResult, bool, error) {
+	var deadline time.Time
+	if opts.Timeout > 0 {
+		deadline = time.Now().Add(opts.Timeout)
+	}
+
+	pending := append([]string(nil), names...)
+	var results []waitResult
+	for {
+		var still []string
+		for _, name := range pending {
+			a, err := lookup(name)
+			if err != nil {
+				return results, false, fmt.Errorf("failed to get agent %q: %w", name, err)
+			}
+			if status, done := terminalStatus(*a); done {
+				results = append(results, waitResult{Agent: *a, Status: status})
+			} else {
+				still = append(still, name)
+			}
+		}
+		pending = still
+
+		if len(pending) == 0 || (opts.Any && len(results) > 0) {
+			return results, false, nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return results, true, nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+// terminalStatus reports the agent's final status and whether it has finished.
+// Agents without a recorded status are considered finished once their
+// process is gone.
+func terminalStatus(a db.Agent) (string, bool) {
+	status := a.EffectiveStatus()
+	switch status {
+	case agent.StatusSucceeded, agent.StatusFailed, agent.StatusKilled:
+		return status, true
+	case "":
+		if !a.ProcessAlive() {
+			return statusExited, true
+		}
+	}
+	return status, false
+}
+
+// formatWaitStatus renders a result line, e.g. "failed (exit 1)".
+func formatWaitStatus(r waitResult) string {
+	if r.Status != statusExited && r.Agent.ExitCode != 0 {
+		return fmt.Sprintf("%s (exit %d)", r.Status, r.Agent.ExitCode)
+	}
+	return r.Status
+}
+
+// finalMessage returns the last assistant message in the agent's transcript.
+func finalMessage(a db.Agent) (string, error) {
+	p, err := provider.ForAgent(&a)
+	if err != nil {
+		return "", err
+	}
+
+	sessionFile := a.SessionFile
+	if sessionFile == "" {
+		sessionFile, err = p.FindSessionFile(a.ULID)
+		if err != nil {
+			return "", fmt.Errorf("session file not found")
+		}
+	}
+
+	events, _, err := p.ReadTranscript(sessionFile, 0)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(transcript.LastText(events)), nil
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/db"
+)
+
+func TestWaitCmdFlags(t *testing.T) {
+	cmd := newWaitCmd()
+
+	for _, name := range []string{"any", "all", "timeout", "print"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("flag --%s not found", name)
+		}
+	}
+	if err := cmd.Args(cmd, nil); err == nil {
+		t.Error("wait with no args should fail")
+	}
+}
+
+func TestTerminalStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		agent      db.Agent
+		wantStatus string
+		wantDone   bool
+	}{
+		{"running with live pid", db.Agent{Status: agent.StatusRunning, PID: os.Getpid(), StartedAt: time.Now()}, agent.StatusRunning, false},
+		{"running with dead pid", db.Agent{Status: agent.StatusRunning, PID: 0}, agent.StatusFailed, true},
+		{"succeeded", db.Agent{Status: agent.StatusSucceeded}, agent.StatusSucceeded, true},
+		{"killed", db.Agent{Status: agent.StatusKilled}, agent.StatusKilled, true},
+		{"legacy with live pid", db.Agent{PID: os.Getpid(), StartedAt: time.Now()}, "", false},
+		{"legacy with dead pid", db.Agent{PID: 0}, statusExited, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, done := terminalStatus(tt.agent)
+			if status != tt.wantStatus || done != tt.wantDone {
+				t.Errorf("terminalStatus() = (%q, %v), want (%q, %v)", status, done, tt.wantStatus, tt.wantDone)
+			}
+		})
+	}
+}
+
+// fakeLookup returns agents from a map, letting tests flip their status
+// between polls.
+func fakeLookup(agents map[string]*db.Agent) func(string) (*db.Agent, error) {
+	return func(name string) (*db.Agent, error) {
+		a, ok := agents[name]
+		if !ok {
+			return nil, db.ErrAgentNotFound
+		}
+		copied := *a
+		return &copied, nil
+	}
+}
+
+func TestWaitForAgents_All(t *testing.T) {
+	agents := map[string]*db.Agent{
+		"a": {Name: "a", Status: agent.StatusSucceeded},
+		"b": {Name: "b", Status: agent.StatusFailed, ExitCode: 1},
+	}
+
+	results, timedOut, err := waitForAgents([]string{"a", "b"}, waitOptions{}, fakeLookup(agents), time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitForAgents: %v", err)
+	}
+	if timedOut {
+		t.Error("timedOut = true, want false")
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+}
+
+func TestWaitForAgents_Any(t *testing.T) {
+	agents := map[string]*db.Agent{
+		"slow": {Name: "slow", Status: agent.StatusRunning, PID: os.Getpid(), StartedAt: time.Now()},
+		"fast": {Name: "fast", Status: agent.StatusSucceeded},
+	}
+
+	results, timedOut, err := waitForAgents([]string{"slow", "fast"}, waitOptions{Any: true}, fakeLookup(agents), time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitForAgents: %v", err)
+	}
+	if timedOut {
+		t.Error("timedOut = true, want false")
+	}
+	if len(results) != 1 || results[0].Agent.Name != "fast" {
+		t.Errorf("results = %+v, want only fast", results)
+	}
+}
+
+func TestWaitForAgents_Timeout(t *testing.T) {
+	agents := map[string]*db.Agent{
+		"slow": {Name: "slow", Status: agent.StatusRunning, PID: os.Getpid(), StartedAt: time.Now()},
+	}
+
+	results, timedOut, err := waitForAgents([]string{"slow"}, waitOptions{Timeout: 20 * time.Millisecond}, fakeLookup(agents), 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitForAgents: %v", err)
+	}
+	if !timedOut {
+		t.Error("timedOut = false, want true")
+	}
+	if len(results) != 0 {
+		t.Errorf("results = %+v, want none", results)
+	}
+}
+
+func TestFormatWaitStatus(t *testing.T) {
+	tests := []struct {
+		result waitResult
+		want   string
+	}{
+		{waitResult{Status: agent.StatusSucceeded}, "succeeded"},
+		{waitResult{Agent: db.Agent{ExitCode: 2}, Status: agent.StatusFailed}, "failed (exit 2)"},
+		{waitResult{Status: statusExited}, "exited"},
+	}
+	for _, tt := range tests {
+		if got := formatWaitStatus(tt.result); got != tt.want {
+			t.Errorf("formatWaitStatus(%+v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
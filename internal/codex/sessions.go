@@ -21,17 +21,24 @@ func CodexHome() (string, error) {
 	return filepath.Join(home, ".codex"), nil
 }
 
+// SessionsDir returns the June codex home's sessions directory,
+// ~/.june/codex/sessions.
+func SessionsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".june", "codex", "sessions"), nil
+}
+
 // FindSessionFile finds a session file by thread ID in the June codex home.
 // It searches in ~/.june/codex/sessions/YYYY/MM/DD/
 func FindSessionFile(threadID string) (string, error) {
-	home, err := os.UserHomeDir()
+	sessionsDir, err := SessionsDir()
 	if err != nil {
 		return "", err
 	}
 
-	// Look in ~/.june/codex/sessions/
-	sessionsDir := filepath.Join(home, ".june", "codex", "sessions")
-
 	// First, try today's directory (most common case)
 	now := time.Now()
 	todayDir := filepath.Join(sessionsDir, now.Format("2006"), now.Format("01"), now.Format("02"))
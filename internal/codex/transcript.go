@@ -5,20 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
-)
+	"time"
 
-// TranscriptEntry represents a parsed entry from a Codex session file
-type TranscriptEntry struct {
-	Type      string
-	Content   string
-	ToolName  string                 // Tool name for function_call entries
-	ToolInput map[string]interface{} // Tool arguments for function_call entries
-}
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
 
-// ReadTranscript reads a Codex session file from the given line offset
-// Returns entries and the new line count
-func ReadTranscript(path string, fromLine int) ([]TranscriptEntry, int, error) {
+// ReadTranscript reads the events of a Codex session file from the given
+// line offset. Returns the events and the new line count.
+func ReadTranscript(path string, fromLine int) ([]transcript.Event, int, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fromLine, err
@@ -30,7 +25,7 @@ func ReadTranscript(path string, fromLine int) ([]TranscriptEntry, int, error) {
 	buf := make([]byte, 0, 256*1024)
 	scanner.Buffer(buf, 1024*1024)
 
-	var entries []TranscriptEntry
+	var events []transcript.Event
 	lineNum := 0
 
 	for scanner.Scan() {
@@ -38,26 +33,37 @@ func ReadTranscript(path string, fromLine int) ([]TranscriptEntry, int, error) {
 		if lineNum <= fromLine {
 			continue
 		}
-
-		entry := parseEntry(scanner.Bytes())
-		if entry.Content != "" {
-			entries = append(entries, entry)
-		}
+		events = append(events, ParseLine(scanner.Bytes())...)
 	}
 
-	return entries, lineNum, scanner.Err()
+	return events, lineNum, scanner.Err()
 }
 
-func parseEntry(data []byte) TranscriptEntry {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return TranscriptEntry{}
+// ParseLine parses a single line of a Codex session file. Returns nil for
+// lines without events.
+func ParseLine(data []byte) []transcript.Event {
+	var line struct {
+		Timestamp time.Time       `json:"timestamp"`
+		Type      string          `json:"type"`
+		Payload   json.RawMessage `json:"payload"`
 	}
-
-	// Get payload for nested type info
-	payload, _ := raw["payload"].(map[string]interface{})
-	if payload == nil {
-		return TranscriptEntry{}
+	if err := json.Unmarshal(data, &line); err != nil {
+		return nil
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(line.Payload, &payload); err != nil || payload == nil {
+		return nil
+	}
+	when := line.Timestamp
+
+	switch line.Type {
+	case "session_meta":
+		id, _ := payload["id"].(string)
+		return []transcript.Event{transcript.Lifecycle{Time: when, Phase: transcript.LifecycleStart, Detail: id}}
+	case "turn_context":
+		// Starts each turn, with the model it uses
+		model, _ := payload["model"].(string)
+		return []transcript.Event{transcript.Lifecycle{Time: when, Phase: transcript.LifecycleStart, Detail: model, Model: model}}
 	}
 
 	// Actual Codex format uses payload.type for the event type
@@ -67,28 +73,25 @@ func parseEntry(data []byte) TranscriptEntry {
 	// Skip agent_reasoning - it duplicates "reasoning" response_item
 	case "reasoning":
 		// response_item with payload.type = "reasoning", summary[0].text = content
-		if summary, ok := payload["summary"].([]interface{}); ok && len(summary) > 0 {
-			if first, ok := summary[0].(map[string]interface{}); ok {
-				if text, ok := first["text"].(string); ok {
-					return TranscriptEntry{Type: "reasoning", Content: text}
-				}
-			}
+		if text := firstText(payload["summary"]); text != "" {
+			return []transcript.Event{transcript.Reasoning{Time: when, Text: text}}
 		}
 	case "message":
 		// response_item with payload.type = "message", content[0].text = content
-		if content, ok := payload["content"].([]interface{}); ok && len(content) > 0 {
-			if first, ok := content[0].(map[string]interface{}); ok {
-				if text, ok := first["text"].(string); ok {
-					return TranscriptEntry{Type: "message", Content: text}
-				}
-			}
+		text := firstText(payload["content"])
+		if text == "" {
+			return nil
 		}
+		if role, _ := payload["role"].(string); role == "user" {
+			return []transcript.Event{transcript.UserPrompt{Time: when, Text: text}}
+		}
+		return []transcript.Event{transcript.AssistantText{Time: when, Text: text}}
 	// Skip agent_message - it duplicates "message" response_item
 	case "function_call":
 		// response_item with payload.type = "function_call", payload.name = tool name
 		name, _ := payload["name"].(string)
 		if name == "" {
-			return TranscriptEntry{}
+			return nil
 		}
 
 		// Parse arguments JSON string into map
@@ -99,47 +102,51 @@ func parseEntry(data []byte) TranscriptEntry {
 			}
 		}
 
-		return TranscriptEntry{
-			Type:      "tool",
-			Content:   fmt.Sprintf("[tool: %s]", name), // Keep for backwards compat
-			ToolName:  name,
-			ToolInput: toolInput,
-		}
+		callID, _ := payload["call_id"].(string)
+		return []transcript.Event{transcript.ToolCall{Time: when, ID: callID, Name: name, Input: toolInput}}
 	case "function_call_output":
 		// response_item with payload.type = "function_call_output", payload.output = result
 		if output, ok := payload["output"].(string); ok {
-			// Truncate long outputs (using runes to avoid splitting multi-byte UTF-8 chars)
-			runes := []rune(output)
-			if len(runes) > 200 {
-				output = string(runes[:200]) + "..."
-			}
-			return TranscriptEntry{Type: "tool_output", Content: output}
+			callID, _ := payload["call_id"].(string)
+			return []transcript.Event{transcript.ToolResult{Time: when, ID: callID, Output: output}}
+		}
+	case "token_count":
+		// event_msg with the session's running totals, null before the first response
+		var info struct {
+			Info *struct {
+				TotalTokenUsage codexTokenUsage `json:"total_token_usage"`
+			} `json:"info"`
 		}
+		if err := json.Unmarshal(line.Payload, &info); err != nil || info.Info == nil {
+			return nil
+		}
+		total := info.Info.TotalTokenUsage
+		return []transcript.Event{transcript.Usage{
+			Time: when,
+			Tokens: usage.Tokens{
+				Input:     total.InputTokens - total.CachedInputTokens,
+				Output:    total.OutputTokens,
+				CacheRead: total.CachedInputTokens,
+			},
+			Cumulative: true,
+		}}
+	case "task_complete":
+		return []transcript.Event{transcript.Lifecycle{Time: when, Phase: transcript.LifecycleEnd}}
+	case "turn_aborted":
+		reason, _ := payload["reason"].(string)
+		return []transcript.Event{transcript.Lifecycle{Time: when, Phase: transcript.LifecycleEnd, Detail: reason, IsError: true}}
 	}
 
-	return TranscriptEntry{}
+	return nil
 }
 
-// FormatEntries formats transcript entries for display
-func FormatEntries(entries []TranscriptEntry) string {
-	var sb strings.Builder
-	for _, e := range entries {
-		switch e.Type {
-		case "message":
-			sb.WriteString(e.Content)
-			sb.WriteString("\n\n")
-		case "reasoning":
-			sb.WriteString("[thinking] ")
-			sb.WriteString(e.Content)
-			sb.WriteString("\n\n")
-		case "tool":
-			sb.WriteString(e.Content)
-			sb.WriteString("\n")
-		case "tool_output":
-			sb.WriteString("  -> ")
-			sb.WriteString(e.Content)
-			sb.WriteString("\n")
-		}
+// firstText returns the text of the first block in a list of content blocks.
+func firstText(blocks interface{}) string {
+	list, _ := blocks.([]interface{})
+	if len(list) == 0 {
+		return ""
 	}
-	return sb.String()
+	first, _ := list[0].(map[string]interface{})
+	text, _ := first["text"].(string)
+	return text
 }
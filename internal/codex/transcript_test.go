@@ -3,154 +3,143 @@ package codex
 import (
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
-	"unicode/utf8"
+	"time"
+
+	"github.com/sky-xo/june/internal/transcript"
 )
 
-func TestParseEntryReasoning(t *testing.T) {
-	// Actual Codex format: type is "response_item", payload.type is "reasoning", summary[0].text has content
-	data := []byte(`{"type":"response_item","payload":{"type":"reasoning","summary":[{"type":"summary_text","text":"**Thinking about this**"}]}}`)
+// parseOne parses a line that should produce exactly one event.
+func parseOne(t *testing.T, data string) transcript.Event {
+	t.Helper()
+	events := ParseLine([]byte(data))
+	if len(events) != 1 {
+		t.Fatalf("ParseLine() = %+v, want one event", events)
+	}
+	return events[0]
+}
 
-	entry := parseEntry(data)
+func TestParseLineReasoning(t *testing.T) {
+	// Actual Codex format: type is "response_item", payload.type is "reasoning", summary[0].text has content
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"reasoning","summary":[{"type":"summary_text","text":"**Thinking about this**"}]}}`)
 
-	if entry.Type != "reasoning" {
-		t.Errorf("Type = %q, want %q", entry.Type, "reasoning")
-	}
-	if entry.Content != "**Thinking about this**" {
-		t.Errorf("Content = %q, want %q", entry.Content, "**Thinking about this**")
+	r, ok := e.(transcript.Reasoning)
+	if !ok || r.Text != "**Thinking about this**" {
+		t.Errorf("event = %+v, want Reasoning %q", e, "**Thinking about this**")
 	}
 }
 
-func TestParseEntryFunctionCall(t *testing.T) {
+func TestParseLineFunctionCall(t *testing.T) {
 	// Actual Codex format: type is "response_item", payload.type is "function_call"
-	data := []byte(`{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{}"}}`)
-
-	entry := parseEntry(data)
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{}"}}`)
 
-	if entry.Type != "tool" {
-		t.Errorf("Type = %q, want %q", entry.Type, "tool")
-	}
-	if entry.Content != "[tool: shell_command]" {
-		t.Errorf("Content = %q, want %q", entry.Content, "[tool: shell_command]")
+	if call, ok := e.(transcript.ToolCall); !ok || call.Name != "shell_command" {
+		t.Errorf("event = %+v, want a shell_command ToolCall", e)
 	}
 }
 
-func TestParseEntryFunctionCallWithArguments(t *testing.T) {
-	data := []byte(`{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{\"command\":\"go test ./...\",\"workdir\":\"/tmp\"}"}}`)
-
-	entry := parseEntry(data)
+func TestParseLineFunctionCallWithArguments(t *testing.T) {
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{\"command\":\"go test ./...\",\"workdir\":\"/tmp\"}"}}`)
 
-	if entry.Type != "tool" {
-		t.Errorf("Type = %q, want %q", entry.Type, "tool")
+	call, ok := e.(transcript.ToolCall)
+	if !ok {
+		t.Fatalf("event = %+v, want a ToolCall", e)
 	}
-	if entry.ToolName != "shell_command" {
-		t.Errorf("ToolName = %q, want %q", entry.ToolName, "shell_command")
+	if call.Name != "shell_command" {
+		t.Errorf("Name = %q, want %q", call.Name, "shell_command")
 	}
-	if entry.ToolInput == nil {
-		t.Fatal("ToolInput is nil, want map with command")
+	if call.Input == nil {
+		t.Fatal("Input is nil, want map with command")
 	}
-	if cmd, ok := entry.ToolInput["command"].(string); !ok || cmd != "go test ./..." {
-		t.Errorf("ToolInput[command] = %v, want %q", entry.ToolInput["command"], "go test ./...")
+	if cmd, ok := call.Input["command"].(string); !ok || cmd != "go test ./..." {
+		t.Errorf("Input[command] = %v, want %q", call.Input["command"], "go test ./...")
 	}
 }
 
-func TestParseEntryFunctionCallMalformedArguments(t *testing.T) {
+func TestParseLineFunctionCallMalformedArguments(t *testing.T) {
 	// Malformed JSON in arguments field - should handle gracefully (not panic)
-	data := []byte(`{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{invalid json here"}}`)
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{invalid json here"}}`)
 
-	entry := parseEntry(data)
-
-	// Should still return a valid tool entry
-	if entry.Type != "tool" {
-		t.Errorf("Type = %q, want %q", entry.Type, "tool")
-	}
-	if entry.ToolName != "shell_command" {
-		t.Errorf("ToolName = %q, want %q", entry.ToolName, "shell_command")
+	// Should still return a valid tool call
+	call, ok := e.(transcript.ToolCall)
+	if !ok || call.Name != "shell_command" {
+		t.Fatalf("event = %+v, want a shell_command ToolCall", e)
 	}
-	// ToolInput should be nil when JSON parsing fails
-	if entry.ToolInput != nil {
-		t.Errorf("ToolInput = %v, want nil for malformed JSON", entry.ToolInput)
-	}
-	// Content should still be populated
-	if entry.Content != "[tool: shell_command]" {
-		t.Errorf("Content = %q, want %q", entry.Content, "[tool: shell_command]")
+	// Input should be nil when JSON parsing fails
+	if call.Input != nil {
+		t.Errorf("Input = %v, want nil for malformed JSON", call.Input)
 	}
 }
 
-func TestParseEntryFunctionCallOutput(t *testing.T) {
+func TestParseLineFunctionCallOutput(t *testing.T) {
 	// Actual Codex format: type is "response_item", payload.type is "function_call_output"
-	data := []byte(`{"type":"response_item","payload":{"type":"function_call_output","output":"Exit code: 0\nOutput: hello"}}`)
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"function_call_output","output":"Exit code: 0\nOutput: hello"}}`)
 
-	entry := parseEntry(data)
+	if r, ok := e.(transcript.ToolResult); !ok || r.Output != "Exit code: 0\nOutput: hello" {
+		t.Errorf("event = %+v, want ToolResult with the output", e)
+	}
+}
 
-	if entry.Type != "tool_output" {
-		t.Errorf("Type = %q, want %q", entry.Type, "tool_output")
+func TestParseLineFunctionCallOutputKeepsLongOutput(t *testing.T) {
+	// Output is kept whole; renderers decide how much to show
+	long := make([]byte, 300)
+	for i := range long {
+		long[i] = 'x'
 	}
-	if entry.Content != "Exit code: 0\nOutput: hello" {
-		t.Errorf("Content = %q, want %q", entry.Content, "Exit code: 0\nOutput: hello")
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"function_call_output","output":"`+string(long)+`"}}`)
+
+	if r, ok := e.(transcript.ToolResult); !ok || len(r.Output) != 300 {
+		t.Errorf("event = %+v, want the full 300-character output", e)
 	}
 }
 
-func TestParseEntryFunctionCallOutputTruncation(t *testing.T) {
-	// Long output should be truncated to 200 chars
-	longOutput := make([]byte, 300)
-	for i := range longOutput {
-		longOutput[i] = 'x'
+func TestParseLineMessageWithOutputText(t *testing.T) {
+	// Actual Codex format: type is "response_item", payload.type is "message", content[0].type is "output_text"
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Hello! I'm Codex, your coding teammate."}]}}`)
+
+	if text, ok := e.(transcript.AssistantText); !ok || text.Text != "Hello! I'm Codex, your coding teammate." {
+		t.Errorf("event = %+v, want AssistantText with the message", e)
 	}
-	data := []byte(`{"type":"response_item","payload":{"type":"function_call_output","output":"` + string(longOutput) + `"}}`)
+}
 
-	entry := parseEntry(data)
+func TestParseLineUserMessage(t *testing.T) {
+	e := parseOne(t, `{"type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"Fix the bug"}]}}`)
 
-	if entry.Content == "" {
-		t.Fatal("Content is empty, parser not working")
-	}
-	if len(entry.Content) != 203 { // 200 + "..."
-		t.Errorf("Content length = %d, want 203", len(entry.Content))
-	}
-	if entry.Content[200:] != "..." {
-		t.Errorf("Content should end with '...'")
+	if p, ok := e.(transcript.UserPrompt); !ok || p.Text != "Fix the bug" {
+		t.Errorf("event = %+v, want UserPrompt %q", e, "Fix the bug")
 	}
 }
 
-func TestParseEntryFunctionCallOutputUTF8Truncation(t *testing.T) {
-	// Create output with multi-byte characters that would be split by byte truncation
-	// 250 emoji (each is 4 bytes in UTF-8) = 250 runes, 1000 bytes
-	longOutput := strings.Repeat("🎉", 250)
-	data := []byte(`{"type":"response_item","payload":{"type":"function_call_output","output":"` + longOutput + `"}}`)
+func TestParseLineTimestamp(t *testing.T) {
+	e := parseOne(t, `{"timestamp":"2026-01-07T10:00:05Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Done"}]}}`)
 
-	entry := parseEntry(data)
-
-	if entry.Content == "" {
-		t.Fatal("Content is empty, parser not working")
+	if want := time.Date(2026, 1, 7, 10, 0, 5, 0, time.UTC); !e.When().Equal(want) {
+		t.Errorf("When() = %v, want %v", e.When(), want)
 	}
+}
 
-	// Should be 200 runes + "..." = 203 runes, all valid UTF-8
-	runes := []rune(entry.Content)
-	if len(runes) != 203 {
-		t.Errorf("rune count = %d, want 203", len(runes))
+func TestParseLineLifecycleAndUsage(t *testing.T) {
+	start := parseOne(t, `{"type":"session_meta","payload":{"id":"s1"}}`)
+	if l, ok := start.(transcript.Lifecycle); !ok || l.Phase != transcript.LifecycleStart || l.Detail != "s1" {
+		t.Errorf("session_meta = %+v, want a start Lifecycle with the session ID", start)
 	}
-	// Verify it's valid UTF-8 (no split chars)
-	if !utf8.ValidString(entry.Content) {
-		t.Error("Content is not valid UTF-8")
+	turn := parseOne(t, `{"type":"turn_context","payload":{"model":"gpt-5-codex"}}`)
+	if l, ok := turn.(transcript.Lifecycle); !ok || l.Phase != transcript.LifecycleStart || l.Model != "gpt-5-codex" {
+		t.Errorf("turn_context = %+v, want a start Lifecycle with the model", turn)
 	}
-	// Verify it ends with "..."
-	if !strings.HasSuffix(entry.Content, "...") {
-		t.Error("Content should end with '...'")
-	}
-}
-
-func TestParseEntryMessageWithOutputText(t *testing.T) {
-	// Actual Codex format: type is "response_item", payload.type is "message", content[0].type is "output_text"
-	data := []byte(`{"type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Hello! I'm Codex, your coding teammate."}]}}`)
 
-	entry := parseEntry(data)
-
-	if entry.Type != "message" {
-		t.Errorf("Type = %q, want %q", entry.Type, "message")
+	tokens := parseOne(t, `{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":800,"output_tokens":50}}}}`)
+	u, ok := tokens.(transcript.Usage)
+	if !ok || !u.Cumulative || u.Input != 200 || u.CacheRead != 800 || u.Output != 50 {
+		t.Errorf("token_count = %+v, want cumulative Usage of 200 input, 800 cache read, 50 output", tokens)
+	}
+	if events := ParseLine([]byte(`{"type":"event_msg","payload":{"type":"token_count","info":null}}`)); events != nil {
+		t.Errorf("token_count without info = %+v, want no events", events)
 	}
-	if entry.Content != "Hello! I'm Codex, your coding teammate." {
-		t.Errorf("Content = %q, want %q", entry.Content, "Hello! I'm Codex, your coding teammate.")
+
+	end := parseOne(t, `{"type":"event_msg","payload":{"type":"task_complete"}}`)
+	if l, ok := end.(transcript.Lifecycle); !ok || l.Phase != transcript.LifecycleEnd || l.IsError {
+		t.Errorf("task_complete = %+v, want a successful end Lifecycle", end)
 	}
 }
 
@@ -167,7 +156,7 @@ func TestReadTranscriptWithRealFormat(t *testing.T) {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
 
-	entries, lineCount, err := ReadTranscript(sessionFile, 0)
+	events, lineCount, err := ReadTranscript(sessionFile, 0)
 	if err != nil {
 		t.Fatalf("ReadTranscript failed: %v", err)
 	}
@@ -176,18 +165,29 @@ func TestReadTranscriptWithRealFormat(t *testing.T) {
 		t.Errorf("lineCount = %d, want 4", lineCount)
 	}
 
-	// Should have 3 entries (session_meta is ignored)
-	if len(entries) != 3 {
-		t.Fatalf("len(entries) = %d, want 3", len(entries))
+	if len(events) != 4 {
+		t.Fatalf("len(events) = %d, want 4", len(events))
 	}
 
-	if entries[0].Type != "reasoning" {
-		t.Errorf("entries[0].Type = %q, want %q", entries[0].Type, "reasoning")
+	if _, ok := events[0].(transcript.Lifecycle); !ok {
+		t.Errorf("events[0] = %T, want Lifecycle", events[0])
+	}
+	if _, ok := events[1].(transcript.Reasoning); !ok {
+		t.Errorf("events[1] = %T, want Reasoning", events[1])
 	}
-	if entries[1].Type != "tool" {
-		t.Errorf("entries[1].Type = %q, want %q", entries[1].Type, "tool")
+	if _, ok := events[2].(transcript.ToolCall); !ok {
+		t.Errorf("events[2] = %T, want ToolCall", events[2])
 	}
-	if entries[2].Type != "tool_output" {
-		t.Errorf("entries[2].Type = %q, want %q", entries[2].Type, "tool_output")
+	if _, ok := events[3].(transcript.ToolResult); !ok {
+		t.Errorf("events[3] = %T, want ToolResult", events[3])
+	}
+}
+
+func TestParseLineFunctionCallIDs(t *testing.T) {
+	call := parseOne(t, `{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{}","call_id":"call_1"}}`)
+	output := parseOne(t, `{"type":"response_item","payload":{"type":"function_call_output","call_id":"call_1","output":"ok"}}`)
+
+	if call.(transcript.ToolCall).ID != "call_1" || output.(transcript.ToolResult).ID != "call_1" {
+		t.Errorf("IDs = %q / %q, want call_1 for both the call and its output", call.(transcript.ToolCall).ID, output.(transcript.ToolResult).ID)
 	}
 }
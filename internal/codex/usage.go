@@ -0,0 +1,20 @@
+package codex
+
+import (
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+// codexTokenUsage is the token usage in a Codex token_count event.
+type codexTokenUsage struct {
+	InputTokens       int64 `json:"input_tokens"` // Including cached
+	CachedInputTokens int64 `json:"cached_input_tokens"`
+	OutputTokens      int64 `json:"output_tokens"` // Including reasoning
+}
+
+// ReadUsage returns the token usage of each turn in a Codex session file.
+// Codex reports running totals in token_count events, so each record is the
+// increase since the previous event; repeated events add nothing.
+func ReadUsage(path string) ([]usage.Record, error) {
+	return transcript.ReadUsage(path, ParseLine)
+}
@@ -0,0 +1,43 @@
+package codex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func TestReadUsageFromTokenCounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rollout.jsonl")
+	content := `{"timestamp":"2026-01-07T10:00:00Z","type":"session_meta","payload":{"id":"s1"}}
+{"timestamp":"2026-01-07T10:00:00Z","type":"turn_context","payload":{"model":"gpt-5-codex"}}
+{"timestamp":"2026-01-07T10:00:01Z","type":"event_msg","payload":{"type":"token_count","info":null}}
+{"timestamp":"2026-01-07T10:00:05Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":800,"output_tokens":50,"reasoning_output_tokens":20,"total_tokens":1050}}}}
+{"timestamp":"2026-01-07T10:00:05Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":800,"output_tokens":50,"reasoning_output_tokens":20,"total_tokens":1050}}}}
+{"timestamp":"2026-01-07T10:00:09Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":2500,"cached_input_tokens":1800,"output_tokens":150,"reasoning_output_tokens":60,"total_tokens":2650}}}}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (repeated totals add nothing)", len(records))
+	}
+	want := []usage.Tokens{
+		{Input: 200, Output: 50, CacheRead: 800},
+		{Input: 500, Output: 100, CacheRead: 1000},
+	}
+	for i, r := range records {
+		if r.Tokens != want[i] {
+			t.Errorf("record %d tokens = %+v, want %+v", i, r.Tokens, want[i])
+		}
+		if r.Model != "gpt-5-codex" {
+			t.Errorf("record %d model = %q, want gpt-5-codex", i, r.Model)
+		}
+	}
+}
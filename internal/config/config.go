@@ -0,0 +1,118 @@
+// Package config loads june's settings from ~/.june/config.toml and, for
+// settings specific to a repository, .june.toml at its root.
+//
+// Example:
+//
+//	# Price a model the built-in table doesn't know, in USD per million tokens
+//	[prices."gpt-5.1-codex-max"]
+//	input = 1.25
+//	output = 10
+//	cache_read = 0.125
+//
+//	# Hide the short subagents a review skill spawns
+//	[[rules]]
+//	action = "hide"
+//	description = "^Review "
+//	max_lines = 20
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+// Config is the contents of the config file. The zero value is the default
+// configuration.
+type Config struct {
+	// Prices adds to or overrides the built-in model prices used to estimate
+	// costs, keyed by model name prefix.
+	Prices usage.Prices `toml:"prices"`
+
+	// Rules hide or de-emphasize agents, in addition to the built-in ones.
+	Rules Rules `toml:"rules"`
+}
+
+// RepoFile is the name of the per-repository config file.
+const RepoFile = ".june.toml"
+
+// Path returns the config file's location: ~/.june/config.toml.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".june", "config.toml"), nil
+}
+
+// Load reads the config file. A missing file is not an error.
+func Load() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Config{}, err
+	}
+	return LoadFile(path)
+}
+
+// LoadForRepo reads the config file and then repoRoot's .june.toml, whose
+// prices override the user's and whose rules are added to them.
+func LoadForRepo(repoRoot string) (Config, error) {
+	cfg, err := Load()
+	if err != nil {
+		return Config{}, err
+	}
+	repoCfg, err := LoadFile(filepath.Join(repoRoot, RepoFile))
+	if err != nil {
+		return Config{}, err
+	}
+	return cfg.Merge(repoCfg), nil
+}
+
+// LoadFile reads the config file at path. A missing file is not an error.
+// Unknown keys are, so typos don't go unnoticed.
+func LoadFile(path string) (Config, error) {
+	var cfg Config
+	md, err := toml.DecodeFile(path, &cfg)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		return Config{}, fmt.Errorf("%s: unknown keys: %s", path, strings.Join(keys, ", "))
+	}
+	if err := cfg.Rules.Compile(); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Merge returns c with o's settings applied on top: o's prices override c's
+// and o's rules follow c's.
+func (c Config) Merge(o Config) Config {
+	merged := Config{Prices: c.Prices.With(o.Prices)}
+	merged.Rules = append(append(merged.Rules, c.Rules...), o.Rules...)
+	return merged
+}
+
+// PriceTable returns the built-in model prices with the configured ones
+// applied.
+func (c Config) PriceTable() usage.Prices {
+	return usage.DefaultPrices().With(c.Prices)
+}
+
+// RuleSet returns the built-in rules followed by the configured ones.
+func (c Config) RuleSet() Rules {
+	return append(DefaultRules(), c.Rules...)
+}
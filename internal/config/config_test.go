@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFileMissing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "config.toml"))
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if len(cfg.Prices) != 0 {
+		t.Errorf("Prices = %v, want none", cfg.Prices)
+	}
+}
+
+func TestLoadFilePrices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[prices."gpt-5"]
+input = 1
+output = 8
+
+[prices."my-model"]
+input = 2
+cache_read = 0.2
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	prices := cfg.PriceTable()
+	if p, _ := prices.Lookup("gpt-5-codex"); p.Input != 1 || p.Output != 8 {
+		t.Errorf("gpt-5 price = %+v, want the configured override", p)
+	}
+	if p, ok := prices.Lookup("my-model-v2"); !ok || p.CacheRead != 0.2 {
+		t.Errorf("my-model price = %+v, %v, want the configured price", p, ok)
+	}
+	if _, ok := prices.Lookup("claude-sonnet-4-5"); !ok {
+		t.Error("built-in prices should still apply")
+	}
+}
+
+func TestLoadFileUnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[prices.\"gpt-5\"]\ncache-read = 0.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), "cache-read") {
+		t.Errorf("LoadFile error = %v, want it to name the unknown key", err)
+	}
+}
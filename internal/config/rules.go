@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/sky-xo/june/internal/agent"
+)
+
+// What a rule does to the agents it matches.
+const (
+	ActionHide = "hide" // Leave the agent out of agent lists
+	ActionDim  = "dim"  // Show the agent de-emphasized
+)
+
+// Rule hides or de-emphasizes the agents matching all of its conditions.
+// Patterns are regular expressions, matched anywhere unless anchored.
+type Rule struct {
+	Action       string `toml:"action"`        // ActionHide or ActionDim
+	Description  string `toml:"description"`   // Pattern on the agent's name or description
+	FirstMessage string `toml:"first_message"` // Pattern on the first user message (the task of spawned agents)
+	Source       string `toml:"source"`        // "claude", "codex" or "gemini"
+	MaxLines     int    `toml:"max_lines"`     // Transcript has at most this many lines; running agents never match
+
+	description  *regexp.Regexp
+	firstMessage *regexp.Regexp
+}
+
+// Rules is a list of rules. Hiding takes precedence over dimming.
+type Rules []Rule
+
+// DefaultRules returns the rules that apply without configuration: Claude
+// Code starts a "Warmup" subagent with each session to prime its cache.
+func DefaultRules() Rules {
+	rules := Rules{
+		{Action: ActionHide, Source: agent.SourceClaude, FirstMessage: `^Warmup$`},
+	}
+	if err := rules.Compile(); err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// Compile validates the rules and compiles their patterns. Rules loaded
+// from config files are already compiled.
+func (rs Rules) Compile() error {
+	for i := range rs {
+		r := &rs[i]
+		switch r.Action {
+		case ActionHide, ActionDim:
+		default:
+			return fmt.Errorf("rule %d: action must be %q or %q, got %q", i+1, ActionHide, ActionDim, r.Action)
+		}
+		switch r.Source {
+		case "", agent.SourceClaude, agent.SourceCodex, agent.SourceGemini:
+		default:
+			return fmt.Errorf("rule %d: unknown source %q", i+1, r.Source)
+		}
+		if r.Description == "" && r.FirstMessage == "" && r.Source == "" && r.MaxLines == 0 {
+			return fmt.Errorf("rule %d: no conditions; it would match every agent", i+1)
+		}
+		var err error
+		if r.Description != "" {
+			if r.description, err = regexp.Compile(r.Description); err != nil {
+				return fmt.Errorf("rule %d: description: %w", i+1, err)
+			}
+		}
+		if r.FirstMessage != "" {
+			if r.firstMessage, err = regexp.Compile(r.FirstMessage); err != nil {
+				return fmt.Errorf("rule %d: first_message: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Transcript gives rules the parts of an agent's transcript they match on.
+// Its methods are only called when a rule needs them, since they may read
+// the transcript.
+type Transcript interface {
+	FirstMessage() string
+	Lines() int
+}
+
+// Match reports whether the agent matches all of the rule's conditions.
+func (r Rule) Match(a agent.Agent, t Transcript) bool {
+	source := a.Source
+	if source == "" {
+		source = agent.SourceClaude
+	}
+	if r.Source != "" && r.Source != source {
+		return false
+	}
+	if r.description != nil && !r.description.MatchString(a.DisplayName()) {
+		return false
+	}
+	if r.MaxLines > 0 && (a.IsActive() || t.Lines() > r.MaxLines) {
+		return false
+	}
+	if r.firstMessage != nil && !r.firstMessage.MatchString(t.FirstMessage()) {
+		return false
+	}
+	return true
+}
+
+// Action returns what the rules do to the agent: ActionHide, ActionDim, or
+// "" if no rule matches.
+func (rs Rules) Action(a agent.Agent, t Transcript) string {
+	action := ""
+	for _, r := range rs {
+		if !r.Match(a, t) {
+			continue
+		}
+		if r.Action == ActionHide {
+			return ActionHide
+		}
+		action = r.Action
+	}
+	return action
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+)
+
+// fakeTranscript is a Transcript with fixed contents that records whether
+// it was read.
+type fakeTranscript struct {
+	first string
+	lines int
+	read  bool
+}
+
+func (t *fakeTranscript) FirstMessage() string { t.read = true; return t.first }
+func (t *fakeTranscript) Lines() int           { t.read = true; return t.lines }
+
+func TestDefaultRulesHideWarmup(t *testing.T) {
+	done := time.Now().Add(-time.Hour)
+	warmup := agent.Agent{ID: "a1", Name: "Warmup", Source: agent.SourceClaude, LastActivity: done}
+	rules := DefaultRules()
+
+	if got := rules.Action(warmup, &fakeTranscript{first: "Warmup"}); got != ActionHide {
+		t.Errorf("warmup agent action = %q, want hide", got)
+	}
+	if got := rules.Action(warmup, &fakeTranscript{first: "Warmup the cache, then run the tests"}); got != "" {
+		t.Errorf("task mentioning warmup action = %q, want none", got)
+	}
+	codex := agent.Agent{ID: "01J", Name: "impl-9c4f", Source: agent.SourceCodex, LastActivity: done}
+	transcript := &fakeTranscript{first: "Warmup"}
+	if got := rules.Action(codex, transcript); got != "" {
+		t.Errorf("codex agent action = %q, want none", got)
+	}
+	if transcript.read {
+		t.Error("a rule for another source should not read the transcript")
+	}
+}
+
+func TestRuleMatchConditions(t *testing.T) {
+	done := agent.Agent{ID: "a1", Name: "Review auth changes", LastActivity: time.Now().Add(-time.Hour)}
+	running := agent.Agent{ID: "a2", Name: "Review db changes", LastActivity: time.Now()}
+
+	rules := Rules{{Action: ActionDim, Description: "^Review ", MaxLines: 20}}
+	if err := rules.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	if got := rules.Action(done, &fakeTranscript{lines: 12}); got != ActionDim {
+		t.Errorf("short review action = %q, want dim", got)
+	}
+	if got := rules.Action(done, &fakeTranscript{lines: 400}); got != "" {
+		t.Errorf("long review action = %q, want none", got)
+	}
+	if got := rules.Action(running, &fakeTranscript{lines: 3}); got != "" {
+		t.Errorf("running agent action = %q, want none (it's still growing)", got)
+	}
+
+	// Hiding wins over dimming, whatever the order
+	rules = Rules{{Action: ActionDim, Source: agent.SourceClaude}, {Action: ActionHide, Description: "auth"}}
+	if err := rules.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	if got := rules.Action(done, &fakeTranscript{}); got != ActionHide {
+		t.Errorf("action = %q, want hide", got)
+	}
+}
+
+func TestLoadFileRulesInvalid(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"[[rules]]\naction = \"remove\"\nsource = \"codex\"\n", `action must be "hide" or "dim"`},
+		{"[[rules]]\naction = \"hide\"\n", "no conditions"},
+		{"[[rules]]\naction = \"hide\"\nsource = \"cursor\"\n", `unknown source "cursor"`},
+		{"[[rules]]\naction = \"hide\"\ndescription = \"(\"\n", "rule 1: description"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadFile(path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("LoadFile(%q) error = %v, want %q", tt.content, err, tt.want)
+		}
+	}
+}
+
+func TestLoadForRepoMergesRepoConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".june"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	user := "[prices.\"my-model\"]\ninput = 1\n\n[[rules]]\naction = \"dim\"\nsource = \"gemini\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".june", "config.toml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := t.TempDir()
+	repoCfg := "[prices.\"my-model\"]\ninput = 2\n\n[[rules]]\naction = \"hide\"\nfirst_message = \"^/lint\"\n"
+	if err := os.WriteFile(filepath.Join(repo, RepoFile), []byte(repoCfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadForRepo(repo)
+	if err != nil {
+		t.Fatalf("LoadForRepo: %v", err)
+	}
+	if p, _ := cfg.PriceTable().Lookup("my-model"); p.Input != 2 {
+		t.Errorf("my-model input price = %v, want the repo's 2", p.Input)
+	}
+	rules := cfg.RuleSet()
+	if len(rules) != 3 || rules[1].Source != agent.SourceGemini || rules[2].FirstMessage != "^/lint" {
+		t.Errorf("rules = %+v, want the defaults, then the user's, then the repo's", rules)
+	}
+	lint := agent.Agent{ID: "a1", LastActivity: time.Now()}
+	if got := rules.Action(lint, &fakeTranscript{first: "/lint internal/"}); got != ActionHide {
+		t.Errorf("repo rule action = %q, want hide", got)
+	}
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"database/sql"
+)
+
+// cursorSchema holds june peek's cursors for agents that aren't in the
+// agents table, i.e. Claude Code subagents, keyed by agent ID.
+const cursorSchema = `
+CREATE TABLE IF NOT EXISTS peek_cursors (
+	agent_id TEXT PRIMARY KEY,
+	cursor INTEGER DEFAULT 0
+);
+`
+
+// GetPeekCursor returns the peek cursor of an agent outside the agents
+// table: the number of transcript lines already shown, 0 if none.
+func (db *DB) GetPeekCursor(agentID string) (int, error) {
+	var cursor int
+	err := db.QueryRow(`SELECT cursor FROM peek_cursors WHERE agent_id = ?`, agentID).Scan(&cursor)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return cursor, err
+}
+
+// SetPeekCursor records the peek cursor of an agent outside the agents
+// table.
+func (db *DB) SetPeekCursor(agentID string, cursor int) error {
+	_, err := db.Exec(
+		`INSERT INTO peek_cursors (agent_id, cursor) VALUES (?, ?)
+		 ON CONFLICT(agent_id) DO UPDATE SET cursor = excluded.cursor`,
+		agentID, cursor,
+	)
+	return err
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPeekCursor(t *testing.T) {
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if cursor, err := database.GetPeekCursor("a1b2c3"); err != nil || cursor != 0 {
+		t.Errorf("unknown agent cursor = %d, %v; want 0", cursor, err)
+	}
+	for _, want := range []int{12, 30} {
+		if err := database.SetPeekCursor("a1b2c3", want); err != nil {
+			t.Fatalf("SetPeekCursor: %v", err)
+		}
+		if cursor, err := database.GetPeekCursor("a1b2c3"); err != nil || cursor != want {
+			t.Errorf("cursor = %d, %v; want %d", cursor, err, want)
+		}
+	}
+}
@@ -2,13 +2,18 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/proc"
 	_ "modernc.org/sqlite"
 )
 
@@ -23,9 +28,85 @@ type Agent struct {
 	Cursor      int
 	PID         int
 	SpawnedAt   time.Time
-	RepoPath    string // Git repo path for channel grouping
-	Branch      string // Git branch for channel grouping
-	Type        string // "codex" or "gemini"
+	StartedAt   time.Time // When PID was recorded: at spawn, or the last resume
+	RepoPath    string    // Git repo path for channel grouping
+	Branch      string    // Git branch for channel grouping
+	Type        string    // provider name: "codex", "gemini" or "claude"
+
+	// Lifecycle (see agent.Status* constants). Status is empty for agents
+	// spawned before lifecycle tracking existed.
+	Status     string
+	ExitCode   int       // Only meaningful once the agent has finished
+	FinishedAt time.Time // Zero while running
+	Error      string    // Wait error message for failed agents
+
+	Task    string       // The prompt the agent was spawned with
+	Options SpawnOptions // Flags the agent was spawned with
+	RetryOf string       // Name of the agent this one retries, empty if not a retry
+}
+
+// SpawnOptions are the settings an agent was spawned with, stored as JSON in
+// the spawn_options column. Empty fields mean the CLI default was used.
+type SpawnOptions struct {
+	Model           string `json:"model,omitempty"`
+	Sandbox         string `json:"sandbox,omitempty"` // Codex sandbox mode, or "true" for Gemini
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
+	MaxTokens       int    `json:"max_tokens,omitempty"`
+	Yolo            bool   `json:"yolo,omitempty"`
+
+	// Images are files attached with the CLI's own flag rather than inlined
+	// into the task (Codex only)
+	Images []string `json:"images,omitempty"`
+}
+
+// String formats the options as space-separated key=value pairs, e.g.
+// "model=o3 sandbox=read-only". Unset options are omitted.
+func (o SpawnOptions) String() string {
+	var parts []string
+	if o.Model != "" {
+		parts = append(parts, "model="+o.Model)
+	}
+	if o.Sandbox != "" {
+		parts = append(parts, "sandbox="+o.Sandbox)
+	}
+	if o.ReasoningEffort != "" {
+		parts = append(parts, "reasoning-effort="+o.ReasoningEffort)
+	}
+	if o.MaxTokens > 0 {
+		parts = append(parts, fmt.Sprintf("max-tokens=%d", o.MaxTokens))
+	}
+	if o.Yolo {
+		parts = append(parts, "yolo")
+	}
+	for _, image := range o.Images {
+		parts = append(parts, "image="+image)
+	}
+	return strings.Join(parts, " ")
+}
+
+// IsFinished returns true if the agent has reached a terminal status.
+func (a Agent) IsFinished() bool {
+	switch a.Status {
+	case agent.StatusSucceeded, agent.StatusFailed, agent.StatusKilled:
+		return true
+	}
+	return false
+}
+
+// ProcessAlive reports whether the agent's process is still running, and
+// not a newer process that was given its PID.
+func (a Agent) ProcessAlive() bool {
+	return proc.BelongsTo(a.PID, a.StartedAt)
+}
+
+// EffectiveStatus returns the agent's status, treating a "running" agent whose
+// process has disappeared (e.g. the spawning june was killed before it could
+// record the exit) as failed.
+func (a Agent) EffectiveStatus() string {
+	if a.Status == agent.StatusRunning && !a.ProcessAlive() {
+		return agent.StatusFailed
+	}
+	return a.Status
 }
 
 // ToUnified converts a db.Agent to the unified agent.Agent type.
@@ -38,9 +119,10 @@ func (a Agent) ToUnified() agent.Agent {
 		}
 	}
 
-	source := agent.SourceCodex
-	if a.Type == "gemini" {
-		source = agent.SourceGemini
+	// The agent type doubles as its source; untyped rows predate Gemini
+	source := a.Type
+	if source == "" {
+		source = agent.SourceCodex
 	}
 
 	return agent.Agent{
@@ -52,6 +134,10 @@ func (a Agent) ToUnified() agent.Agent {
 		TranscriptPath: a.SessionFile,
 		LastActivity:   lastActivity,
 		PID:            a.PID,
+		Status:         a.EffectiveStatus(),
+		Task:           a.Task,
+		Options:        a.Options.String(),
+		RetryOf:        a.RetryOf,
 	}
 }
 
@@ -65,9 +151,21 @@ CREATE TABLE IF NOT EXISTS agents (
 	spawned_at TEXT NOT NULL,
 	repo_path TEXT DEFAULT '',
 	branch TEXT DEFAULT '',
-	type TEXT DEFAULT 'codex'
+	type TEXT DEFAULT 'codex',
+	status TEXT DEFAULT '',
+	exit_code INTEGER DEFAULT 0,
+	finished_at TEXT DEFAULT '',
+	error TEXT DEFAULT '',
+	task TEXT DEFAULT '',
+	spawn_options TEXT DEFAULT '',
+	retry_of TEXT DEFAULT '',
+	started_at TEXT DEFAULT ''
 );
-`
+` + searchSchema + cursorSchema
+
+// agentColumns is the column list shared by all agent queries; keep it in sync with scanAgent.
+const agentColumns = `name, ulid, session_file, cursor, pid, spawned_at, repo_path, branch, type,
+	status, exit_code, finished_at, error, task, spawn_options, retry_of, started_at`
 
 // DB wraps a SQLite database connection
 type DB struct {
@@ -81,7 +179,10 @@ func Open(path string) (*DB, error) {
 		return nil, err
 	}
 
-	db, err := sql.Open("sqlite", path)
+	// Agents, supervisors and the search indexer write from separate
+	// processes: wait for each other's locks instead of failing with
+	// SQLITE_BUSY, and let readers proceed during long index writes
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate")
 	if err != nil {
 		return nil, err
 	}
@@ -103,43 +204,84 @@ func Open(path string) (*DB, error) {
 
 // migrate runs schema migrations for existing databases
 func migrate(db *sql.DB) error {
-	// Check if repo_path column exists and add if missing
-	var repoPathCount int
-	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('agents') WHERE name='repo_path'`).Scan(&repoPathCount)
-	if err != nil {
-		return err
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"repo_path", "TEXT DEFAULT ''"},
+		{"branch", "TEXT DEFAULT ''"},
+		{"type", "TEXT DEFAULT 'codex'"},
+		{"status", "TEXT DEFAULT ''"},
+		{"exit_code", "INTEGER DEFAULT 0"},
+		{"finished_at", "TEXT DEFAULT ''"},
+		{"error", "TEXT DEFAULT ''"},
+		{"task", "TEXT DEFAULT ''"},
+		{"spawn_options", "TEXT DEFAULT ''"},
+		{"retry_of", "TEXT DEFAULT ''"},
+		{"started_at", "TEXT DEFAULT ''"},
 	}
-	if repoPathCount == 0 {
-		if _, err := db.Exec(`ALTER TABLE agents ADD COLUMN repo_path TEXT DEFAULT ''`); err != nil {
+	// Check each column independently so partially migrated DBs are fixed up too
+	for _, c := range columns {
+		if err := addColumnIfMissing(db, c.name, c.definition); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	// Check if branch column exists independently and add if missing
-	var branchCount int
-	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('agents') WHERE name='branch'`).Scan(&branchCount)
+// addColumnIfMissing adds a column to the agents table if it doesn't exist yet.
+func addColumnIfMissing(db *sql.DB, name, definition string) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('agents') WHERE name=?`, name).Scan(&count)
 	if err != nil {
 		return err
 	}
-	if branchCount == 0 {
-		if _, err := db.Exec(`ALTER TABLE agents ADD COLUMN branch TEXT DEFAULT ''`); err != nil {
-			return err
-		}
+	if count > 0 {
+		return nil
 	}
+	_, err = db.Exec(`ALTER TABLE agents ADD COLUMN ` + name + ` ` + definition)
+	return err
+}
 
-	// Check if type column exists and add if missing
-	var typeCount int
-	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('agents') WHERE name='type'`).Scan(&typeCount)
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAgent scans a row selected with agentColumns.
+func scanAgent(row rowScanner) (Agent, error) {
+	var a Agent
+	var spawnedAt, finishedAt, options, startedAt string
+	err := row.Scan(&a.Name, &a.ULID, &a.SessionFile, &a.Cursor, &a.PID, &spawnedAt, &a.RepoPath, &a.Branch, &a.Type,
+		&a.Status, &a.ExitCode, &finishedAt, &a.Error, &a.Task, &options, &a.RetryOf, &startedAt)
 	if err != nil {
-		return err
+		return Agent{}, err
 	}
-	if typeCount == 0 {
-		if _, err := db.Exec(`ALTER TABLE agents ADD COLUMN type TEXT DEFAULT 'codex'`); err != nil {
-			return err
+	var parseErr error
+	a.SpawnedAt, parseErr = time.Parse(time.RFC3339, spawnedAt)
+	if parseErr != nil {
+		log.Printf("warning: failed to parse spawned_at for agent %s: %v", a.Name, parseErr)
+	}
+	// Rows from before resume times were recorded still have their spawn time
+	a.StartedAt = a.SpawnedAt
+	if startedAt != "" {
+		a.StartedAt, parseErr = time.Parse(time.RFC3339, startedAt)
+		if parseErr != nil {
+			log.Printf("warning: failed to parse started_at for agent %s: %v", a.Name, parseErr)
 		}
 	}
-
-	return nil
+	if finishedAt != "" {
+		a.FinishedAt, parseErr = time.Parse(time.RFC3339, finishedAt)
+		if parseErr != nil {
+			log.Printf("warning: failed to parse finished_at for agent %s: %v", a.Name, parseErr)
+		}
+	}
+	if options != "" {
+		if err := json.Unmarshal([]byte(options), &a.Options); err != nil {
+			log.Printf("warning: failed to parse spawn_options for agent %s: %v", a.Name, err)
+		}
+	}
+	return a, nil
 }
 
 // CreateAgent inserts a new agent record
@@ -148,34 +290,29 @@ func (db *DB) CreateAgent(a Agent) error {
 	if agentType == "" {
 		agentType = "codex"
 	}
-	_, err := db.Exec(
-		`INSERT INTO agents (name, ulid, session_file, cursor, pid, spawned_at, repo_path, branch, type)
-		 VALUES (?, ?, ?, 0, ?, ?, ?, ?, ?)`,
-		a.Name, a.ULID, a.SessionFile, a.PID, time.Now().UTC().Format(time.RFC3339),
-		a.RepoPath, a.Branch, agentType,
+	options, err := json.Marshal(a.Options)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err = db.Exec(
+		`INSERT INTO agents (name, ulid, session_file, cursor, pid, spawned_at, repo_path, branch, type, status, task, spawn_options, retry_of, started_at)
+		 VALUES (?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.Name, a.ULID, a.SessionFile, a.PID, now,
+		a.RepoPath, a.Branch, agentType, a.Status, a.Task, string(options), a.RetryOf, now,
 	)
 	return err
 }
 
 // GetAgent retrieves an agent by name
 func (db *DB) GetAgent(name string) (*Agent, error) {
-	var a Agent
-	var spawnedAt string
-	err := db.QueryRow(
-		`SELECT name, ulid, session_file, cursor, pid, spawned_at, repo_path, branch, type
-		 FROM agents WHERE name = ?`, name,
-	).Scan(&a.Name, &a.ULID, &a.SessionFile, &a.Cursor, &a.PID, &spawnedAt, &a.RepoPath, &a.Branch, &a.Type)
+	a, err := scanAgent(db.QueryRow(`SELECT `+agentColumns+` FROM agents WHERE name = ?`, name))
 	if err == sql.ErrNoRows {
 		return nil, ErrAgentNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
-	var parseErr error
-	a.SpawnedAt, parseErr = time.Parse(time.RFC3339, spawnedAt)
-	if parseErr != nil {
-		log.Printf("warning: failed to parse spawned_at for agent %s: %v", name, parseErr)
-	}
 	return &a, nil
 }
 
@@ -205,44 +342,61 @@ func (db *DB) UpdateSessionFile(name string, sessionFile string) error {
 	return nil
 }
 
-// ListAgents returns all agents
-func (db *DB) ListAgents() ([]Agent, error) {
-	rows, err := db.Query(
-		`SELECT name, ulid, session_file, cursor, pid, spawned_at, repo_path, branch, type
-		 FROM agents ORDER BY spawned_at DESC`,
+// FinishAgent records that an agent's process has exited. An agent already
+// recorded as killed keeps that status, so the exit its supervisor sees
+// after june kill doesn't overwrite it.
+func (db *DB) FinishAgent(name, status string, exitCode int, errMsg string) error {
+	result, err := db.Exec(
+		`UPDATE agents SET status = ?, exit_code = ?, finished_at = ?, error = ? WHERE name = ? AND status != ?`,
+		status, exitCode, time.Now().UTC().Format(time.RFC3339), errMsg, name, agent.StatusKilled,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
-
-	var agents []Agent
-	for rows.Next() {
-		var a Agent
-		var spawnedAt string
-		if err := rows.Scan(&a.Name, &a.ULID, &a.SessionFile, &a.Cursor, &a.PID, &spawnedAt, &a.RepoPath, &a.Branch, &a.Type); err != nil {
-			return nil, err
-		}
-		var parseErr error
-		a.SpawnedAt, parseErr = time.Parse(time.RFC3339, spawnedAt)
-		if parseErr != nil {
-			log.Printf("warning: failed to parse spawned_at for agent %s: %v", a.Name, parseErr)
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		if _, err := db.GetAgent(name); err != nil {
+			return err
 		}
-		agents = append(agents, a)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return nil
+}
+
+// MarkKilled records that june stopped an agent with sig.
+func (db *DB) MarkKilled(name string, sig syscall.Signal) error {
+	return db.FinishAgent(name, agent.StatusKilled, 128+int(sig), fmt.Sprintf("killed by june (%s)", sig))
+}
+
+// ResumeAgent marks a finished agent as running again under a new process,
+// clearing the previous outcome.
+func (db *DB) ResumeAgent(name string, pid int) error {
+	result, err := db.Exec(
+		`UPDATE agents SET status = ?, pid = ?, started_at = ?, exit_code = 0, finished_at = '', error = '' WHERE name = ?`,
+		agent.StatusRunning, pid, time.Now().UTC().Format(time.RFC3339), name,
+	)
+	if err != nil {
+		return err
 	}
-	return agents, nil
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrAgentNotFound
+	}
+	return nil
+}
+
+// ListAgents returns all agents
+func (db *DB) ListAgents() ([]Agent, error) {
+	return db.queryAgents(`SELECT ` + agentColumns + ` FROM agents ORDER BY spawned_at DESC`)
 }
 
 // ListAgentsByRepo returns agents matching the given repo path.
 func (db *DB) ListAgentsByRepo(repoPath string) ([]Agent, error) {
-	rows, err := db.Query(
-		`SELECT name, ulid, session_file, cursor, pid, spawned_at, repo_path, branch, type
-		 FROM agents WHERE repo_path = ? ORDER BY spawned_at DESC`,
-		repoPath,
-	)
+	return db.queryAgents(`SELECT `+agentColumns+` FROM agents WHERE repo_path = ? ORDER BY spawned_at DESC`, repoPath)
+}
+
+// queryAgents runs a query selecting agentColumns and scans all rows.
+func (db *DB) queryAgents(query string, args ...any) ([]Agent, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -250,16 +404,10 @@ func (db *DB) ListAgentsByRepo(repoPath string) ([]Agent, error) {
 
 	var agents []Agent
 	for rows.Next() {
-		var a Agent
-		var spawnedAt string
-		if err := rows.Scan(&a.Name, &a.ULID, &a.SessionFile, &a.Cursor, &a.PID, &spawnedAt, &a.RepoPath, &a.Branch, &a.Type); err != nil {
+		a, err := scanAgent(rows)
+		if err != nil {
 			return nil, err
 		}
-		var parseErr error
-		a.SpawnedAt, parseErr = time.Parse(time.RFC3339, spawnedAt)
-		if parseErr != nil {
-			log.Printf("warning: failed to parse spawned_at for agent %s: %v", a.Name, parseErr)
-		}
 		agents = append(agents, a)
 	}
 	if err := rows.Err(); err != nil {
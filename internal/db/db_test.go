@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"reflect"
+	"syscall"
 	"testing"
 	"time"
 
@@ -556,3 +558,291 @@ func TestMigration_PartialMigration_AddsMissingBranchColumn(t *testing.T) {
 		t.Errorf("expected empty Branch for partially migrated agent, got %q", agent.Branch)
 	}
 }
+
+func TestFinishAgent(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	if err := db.CreateAgent(Agent{Name: "impl-1", ULID: "ulid", SessionFile: "/s.jsonl", Status: agent.StatusRunning}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+
+	got, err := db.GetAgent("impl-1")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.Status != agent.StatusRunning {
+		t.Errorf("Status = %q, want %q", got.Status, agent.StatusRunning)
+	}
+	if got.IsFinished() {
+		t.Error("IsFinished() = true for running agent")
+	}
+
+	if err := db.FinishAgent("impl-1", agent.StatusFailed, 2, "exit status 2"); err != nil {
+		t.Fatalf("FinishAgent failed: %v", err)
+	}
+
+	got, err = db.GetAgent("impl-1")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.Status != agent.StatusFailed {
+		t.Errorf("Status = %q, want %q", got.Status, agent.StatusFailed)
+	}
+	if got.ExitCode != 2 {
+		t.Errorf("ExitCode = %d, want 2", got.ExitCode)
+	}
+	if got.Error != "exit status 2" {
+		t.Errorf("Error = %q, want %q", got.Error, "exit status 2")
+	}
+	if got.FinishedAt.IsZero() {
+		t.Error("FinishedAt was not set")
+	}
+	if !got.IsFinished() {
+		t.Error("IsFinished() = false for failed agent")
+	}
+}
+
+func TestFinishAgentNotFound(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	err := db.FinishAgent("nonexistent", agent.StatusSucceeded, 0, "")
+	if err != ErrAgentNotFound {
+		t.Errorf("err = %v, want ErrAgentNotFound", err)
+	}
+}
+
+func TestAgent_EffectiveStatus(t *testing.T) {
+	// Our own process is alive, so a running agent stays running
+	running := Agent{Status: agent.StatusRunning, PID: os.Getpid(), StartedAt: time.Now()}
+	if got := running.EffectiveStatus(); got != agent.StatusRunning {
+		t.Errorf("EffectiveStatus() = %q, want %q", got, agent.StatusRunning)
+	}
+
+	// A running agent without a live process is reported as failed
+	orphaned := Agent{Status: agent.StatusRunning, PID: 0}
+	if got := orphaned.EffectiveStatus(); got != agent.StatusFailed {
+		t.Errorf("EffectiveStatus() = %q, want %q", got, agent.StatusFailed)
+	}
+
+	// Terminal statuses are left alone
+	done := Agent{Status: agent.StatusSucceeded}
+	if got := done.ToUnified().Status; got != agent.StatusSucceeded {
+		t.Errorf("ToUnified().Status = %q, want %q", got, agent.StatusSucceeded)
+	}
+}
+
+func TestMigration_AddsLifecycleColumns(t *testing.T) {
+	// Create a DB with the schema from before lifecycle tracking
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	rawDB, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rawDB.Exec(`
+		CREATE TABLE agents (
+			name TEXT PRIMARY KEY,
+			ulid TEXT NOT NULL,
+			session_file TEXT NOT NULL,
+			cursor INTEGER DEFAULT 0,
+			pid INTEGER,
+			spawned_at TEXT NOT NULL,
+			repo_path TEXT DEFAULT '',
+			branch TEXT DEFAULT '',
+			type TEXT DEFAULT 'codex'
+		);
+		INSERT INTO agents (name, ulid, session_file, pid, spawned_at, repo_path, branch, type)
+		VALUES ('old-agent', 'ulid123', '/tmp/session.jsonl', 0, '2025-01-01T00:00:00Z', '/code/project', 'main', 'gemini');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawDB.Close()
+
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer database.Close()
+
+	got, err := database.GetAgent("old-agent")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.Status != "" {
+		t.Errorf("expected empty Status for migrated agent, got %q", got.Status)
+	}
+	if !got.FinishedAt.IsZero() {
+		t.Errorf("expected zero FinishedAt for migrated agent, got %v", got.FinishedAt)
+	}
+
+	// New columns should be writable
+	if err := database.FinishAgent("old-agent", agent.StatusSucceeded, 0, ""); err != nil {
+		t.Fatalf("FinishAgent failed on migrated DB: %v", err)
+	}
+}
+
+func TestFinishAgent_KeepsKilled(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	if err := db.CreateAgent(Agent{Name: "impl-1", ULID: "ulid", Status: agent.StatusRunning}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.MarkKilled("impl-1", syscall.SIGTERM); err != nil {
+		t.Fatalf("MarkKilled: %v", err)
+	}
+	// The supervisor records the exit it saw afterwards
+	if err := db.FinishAgent("impl-1", agent.StatusFailed, 143, "signal: terminated"); err != nil {
+		t.Fatalf("FinishAgent: %v", err)
+	}
+
+	got, err := db.GetAgent("impl-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != agent.StatusKilled || got.Error != "killed by june (terminated)" {
+		t.Errorf("status = %q, error = %q; want the kill kept", got.Status, got.Error)
+	}
+	if err := db.FinishAgent("nonexistent", agent.StatusFailed, 1, ""); err != ErrAgentNotFound {
+		t.Errorf("err = %v, want ErrAgentNotFound", err)
+	}
+}
+
+func TestResumeAgent(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	if err := db.CreateAgent(Agent{Name: "impl-1", ULID: "ulid", PID: 100, Status: agent.StatusRunning}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+	if err := db.FinishAgent("impl-1", agent.StatusFailed, 1, "exit status 1"); err != nil {
+		t.Fatalf("FinishAgent failed: %v", err)
+	}
+
+	if err := db.ResumeAgent("impl-1", 200); err != nil {
+		t.Fatalf("ResumeAgent failed: %v", err)
+	}
+
+	got, err := db.GetAgent("impl-1")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.Status != agent.StatusRunning {
+		t.Errorf("Status = %q, want %q", got.Status, agent.StatusRunning)
+	}
+	if got.PID != 200 {
+		t.Errorf("PID = %d, want 200", got.PID)
+	}
+	if got.ExitCode != 0 || got.Error != "" || !got.FinishedAt.IsZero() {
+		t.Errorf("previous outcome not cleared: exit=%d error=%q finished=%v", got.ExitCode, got.Error, got.FinishedAt)
+	}
+
+	if err := db.ResumeAgent("nonexistent", 1); err != ErrAgentNotFound {
+		t.Errorf("err = %v, want ErrAgentNotFound", err)
+	}
+}
+
+func TestCreateAgent_StoresTaskAndOptions(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	opts := SpawnOptions{Model: "o3", Sandbox: "read-only", ReasoningEffort: "high", MaxTokens: 4000}
+	if err := db.CreateAgent(Agent{Name: "impl-1", ULID: "ulid", Task: "fix the tests", Options: opts}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+
+	got, err := db.GetAgent("impl-1")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.Task != "fix the tests" {
+		t.Errorf("Task = %q, want %q", got.Task, "fix the tests")
+	}
+	if !reflect.DeepEqual(got.Options, opts) {
+		t.Errorf("Options = %+v, want %+v", got.Options, opts)
+	}
+
+	unified := got.ToUnified()
+	if unified.Task != "fix the tests" {
+		t.Errorf("ToUnified().Task = %q, want %q", unified.Task, "fix the tests")
+	}
+	if unified.Options != opts.String() {
+		t.Errorf("ToUnified().Options = %q, want %q", unified.Options, opts.String())
+	}
+}
+
+func TestSpawnOptions_String(t *testing.T) {
+	tests := []struct {
+		opts SpawnOptions
+		want string
+	}{
+		{SpawnOptions{}, ""},
+		{SpawnOptions{Model: "o3", Sandbox: "read-only"}, "model=o3 sandbox=read-only"},
+		{SpawnOptions{ReasoningEffort: "high", MaxTokens: 100}, "reasoning-effort=high max-tokens=100"},
+		{SpawnOptions{Model: "gemini-2.5-pro", Yolo: true}, "model=gemini-2.5-pro yolo"},
+		{SpawnOptions{Images: []string{"/tmp/a.png", "/tmp/b.png"}}, "image=/tmp/a.png image=/tmp/b.png"},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestCreateAgent_StoresRetryOf(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	if err := db.CreateAgent(Agent{Name: "impl-bbbb", ULID: "ulid", RetryOf: "impl-aaaa"}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+
+	got, err := db.GetAgent("impl-bbbb")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if got.RetryOf != "impl-aaaa" {
+		t.Errorf("RetryOf = %q, want %q", got.RetryOf, "impl-aaaa")
+	}
+	if got.ToUnified().RetryOf != "impl-aaaa" {
+		t.Errorf("ToUnified().RetryOf = %q, want %q", got.ToUnified().RetryOf, "impl-aaaa")
+	}
+}
+
+func TestOpen_WritersWaitForEachOther(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	first, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer first.Close()
+	second, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer second.Close()
+
+	// Hold a write lock on the first connection while the second writes
+	tx, err := first.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`INSERT INTO peek_cursors (agent_id, cursor) VALUES ('a1', 1)`); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- second.CreateAgent(Agent{Name: "impl-9c4f", ULID: "01J", SessionFile: "/tmp/s.jsonl"})
+	}()
+	time.Sleep(200 * time.Millisecond)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("CreateAgent during another write = %v, want it to wait for the lock", err)
+	}
+}
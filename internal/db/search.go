@@ -0,0 +1,165 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// searchSchema holds the full-text index of agent transcripts. search_files
+// records how far each transcript has been indexed, so indexing only reads
+// what was appended since.
+const searchSchema = `
+CREATE TABLE IF NOT EXISTS search_files (
+	path TEXT PRIMARY KEY,
+	agent_id TEXT NOT NULL,
+	agent_name TEXT DEFAULT '',
+	source TEXT NOT NULL,
+	repo_path TEXT DEFAULT '',
+	branch TEXT DEFAULT '',
+	inode INTEGER DEFAULT 0,
+	indexed_bytes INTEGER DEFAULT 0
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS search_entries USING fts5(
+	text,
+	path UNINDEXED,
+	kind UNINDEXED,
+	time UNINDEXED
+);
+`
+
+// Markers around the matched terms in SearchHit.Snippet.
+const (
+	SnippetMatchStart = "\x02"
+	SnippetMatchEnd   = "\x03"
+)
+
+// searchTimeFormat stores entry times in UTC at a fixed width, so they
+// compare correctly as strings.
+const searchTimeFormat = "2006-01-02T15:04:05Z"
+
+// SearchFile is the agent a transcript belongs to and how far it has been
+// indexed.
+type SearchFile struct {
+	Path      string
+	AgentID   string
+	AgentName string
+	Source    string
+	RepoPath  string
+	Branch    string
+	Inode     uint64 // Identifies the file, to notice it being replaced
+	Offset    int64  // Bytes indexed, always at the end of a complete line
+}
+
+// SearchEntry is the searchable text of a transcript entry.
+type SearchEntry struct {
+	Kind string // Entry type, e.g. "message" or "tool"
+	Text string
+	Time time.Time
+}
+
+// SearchQuery selects search hits.
+type SearchQuery struct {
+	Match    string    // FTS5 query expression
+	RepoPath string    // Only agents in this repo, if set
+	Since    time.Time // Only entries written since, if set
+	Limit    int
+}
+
+// SearchHit is an entry matching a search, with the agent it belongs to.
+type SearchHit struct {
+	SearchFile
+	Kind    string
+	Time    time.Time
+	Snippet string // Text around the match, with matches between SnippetMatchStart and SnippetMatchEnd
+}
+
+// GetSearchFile returns the indexing state of a transcript. The bool is
+// false if it hasn't been indexed.
+func (db *DB) GetSearchFile(path string) (SearchFile, bool, error) {
+	var f SearchFile
+	err := db.QueryRow(`SELECT path, agent_id, agent_name, source, repo_path, branch, inode, indexed_bytes
+		FROM search_files WHERE path = ?`, path).
+		Scan(&f.Path, &f.AgentID, &f.AgentName, &f.Source, &f.RepoPath, &f.Branch, &f.Inode, &f.Offset)
+	if errors.Is(err, sql.ErrNoRows) {
+		return SearchFile{}, false, nil
+	}
+	if err != nil {
+		return SearchFile{}, false, err
+	}
+	return f, true, nil
+}
+
+// IndexSearchEntries adds entries read from a transcript to the index and
+// records f as its new state. With reset, the transcript's previously indexed
+// entries are removed first (e.g. it was replaced).
+func (db *DB) IndexSearchEntries(f SearchFile, reset bool, entries []SearchEntry) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if reset {
+		if _, err := tx.Exec(`DELETE FROM search_entries WHERE path = ?`, f.Path); err != nil {
+			return err
+		}
+	}
+	for _, e := range entries {
+		_, err := tx.Exec(`INSERT INTO search_entries (text, path, kind, time) VALUES (?, ?, ?, ?)`,
+			e.Text, f.Path, e.Kind, e.Time.UTC().Format(searchTimeFormat))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`INSERT INTO search_files (path, agent_id, agent_name, source, repo_path, branch, inode, indexed_bytes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET agent_id = excluded.agent_id, agent_name = excluded.agent_name,
+			source = excluded.source, repo_path = excluded.repo_path, branch = excluded.branch,
+			inode = excluded.inode, indexed_bytes = excluded.indexed_bytes`,
+		f.Path, f.AgentID, f.AgentName, f.Source, f.RepoPath, f.Branch, f.Inode, f.Offset)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Search returns the indexed entries matching q, best matches first.
+func (db *DB) Search(q SearchQuery) ([]SearchHit, error) {
+	since := ""
+	if !q.Since.IsZero() {
+		since = q.Since.UTC().Format(searchTimeFormat)
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+
+	rows, err := db.Query(`SELECT f.path, f.agent_id, f.agent_name, f.source, f.repo_path, f.branch,
+			search_entries.kind, search_entries.time,
+			snippet(search_entries, 0, ?, ?, '…', 16)
+		FROM search_entries JOIN search_files f ON f.path = search_entries.path
+		WHERE search_entries MATCH ?
+			AND (? = '' OR f.repo_path = ?)
+			AND (? = '' OR search_entries.time >= ?)
+		ORDER BY rank
+		LIMIT ?`,
+		SnippetMatchStart, SnippetMatchEnd, q.Match, q.RepoPath, q.RepoPath, since, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var h SearchHit
+		var entryTime string
+		err := rows.Scan(&h.Path, &h.AgentID, &h.AgentName, &h.Source, &h.RepoPath, &h.Branch, &h.Kind, &entryTime, &h.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		h.Time, _ = time.Parse(searchTimeFormat, entryTime)
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
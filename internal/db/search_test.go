@@ -0,0 +1,76 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchIndexAndQuery(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	now := time.Now()
+
+	main := SearchFile{Path: "/s/a.jsonl", AgentID: "a1", AgentName: "impl-9c4f", Source: "codex", RepoPath: "/code/june", Branch: "main", Inode: 7, Offset: 100}
+	other := SearchFile{Path: "/s/b.jsonl", AgentID: "b1", Source: "claude", RepoPath: "/code/other"}
+	if err := db.IndexSearchEntries(main, false, []SearchEntry{
+		{Kind: "tool", Text: "Edit\ninternal/db/db.go", Time: now.Add(-48 * time.Hour)},
+		{Kind: "message", Text: "The migration failed: connection refused", Time: now},
+	}); err != nil {
+		t.Fatalf("IndexSearchEntries: %v", err)
+	}
+	if err := db.IndexSearchEntries(other, false, []SearchEntry{
+		{Kind: "message", Text: "connection refused again", Time: now},
+	}); err != nil {
+		t.Fatalf("IndexSearchEntries: %v", err)
+	}
+
+	hits, err := db.Search(SearchQuery{Match: `"db/db.go"`})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 1 || hits[0].AgentName != "impl-9c4f" || hits[0].Kind != "tool" || hits[0].Branch != "main" {
+		t.Fatalf("hits = %+v, want the Edit entry of impl-9c4f", hits)
+	}
+	if !strings.Contains(hits[0].Snippet, SnippetMatchStart+"db/db.go"+SnippetMatchEnd) {
+		t.Errorf("snippet = %q, want the match marked", hits[0].Snippet)
+	}
+
+	hits, _ = db.Search(SearchQuery{Match: `"connection refused"`, RepoPath: "/code/june"})
+	if len(hits) != 1 || hits[0].AgentID != "a1" {
+		t.Errorf("repo-filtered hits = %+v, want only a1", hits)
+	}
+	hits, _ = db.Search(SearchQuery{Match: `"db/db.go"`, Since: now.Add(-time.Hour)})
+	if len(hits) != 0 {
+		t.Errorf("hits since an hour ago = %+v, want none", hits)
+	}
+	hits, _ = db.Search(SearchQuery{Match: `"connection"`, Limit: 1})
+	if len(hits) != 1 {
+		t.Errorf("limited hits = %d, want 1", len(hits))
+	}
+
+	f, found, err := db.GetSearchFile("/s/a.jsonl")
+	if err != nil || !found || f != main {
+		t.Errorf("GetSearchFile = %+v, %v, %v; want %+v", f, found, err, main)
+	}
+	if _, found, _ := db.GetSearchFile("/s/missing.jsonl"); found {
+		t.Error("GetSearchFile found a transcript that was never indexed")
+	}
+}
+
+func TestSearchIndexReset(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	f := SearchFile{Path: "/s/a.jsonl", AgentID: "a1", Source: "codex"}
+	db.IndexSearchEntries(f, false, []SearchEntry{{Kind: "message", Text: "old content"}})
+
+	// Appending keeps earlier entries; a reset replaces them
+	db.IndexSearchEntries(f, false, []SearchEntry{{Kind: "message", Text: "appended content"}})
+	if hits, _ := db.Search(SearchQuery{Match: `"content"`}); len(hits) != 2 {
+		t.Errorf("after append: %d hits, want 2", len(hits))
+	}
+	db.IndexSearchEntries(f, true, []SearchEntry{{Kind: "message", Text: "new content"}})
+	if hits, _ := db.Search(SearchQuery{Match: `"content"`}); len(hits) != 1 {
+		t.Errorf("after reset: %d hits, want 1", len(hits))
+	}
+}
@@ -3,23 +3,17 @@ package gemini
 import (
 	"bufio"
 	"encoding/json"
-	"fmt"
 	"os"
-	"strings"
-)
+	"time"
 
-// TranscriptEntry represents a parsed entry from a Gemini session file
-type TranscriptEntry struct {
-	Type      string
-	Content   string
-	ToolName  string                 // Tool name for tool_use entries
-	ToolInput map[string]interface{} // Tool parameters for tool_use entries
-}
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
 
-// ReadTranscript reads a Gemini session file from the given line offset.
-// Returns entries and the new line count.
-// Note: Assistant messages with delta:true are accumulated into single entries.
-func ReadTranscript(path string, fromLine int) ([]TranscriptEntry, int, error) {
+// ReadTranscript reads the events of a Gemini session file from the given
+// line offset. Returns the events and the new line count. Streamed
+// assistant message chunks are joined into single events.
+func ReadTranscript(path string, fromLine int) ([]transcript.Event, int, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fromLine, err
@@ -30,8 +24,7 @@ func ReadTranscript(path string, fromLine int) ([]TranscriptEntry, int, error) {
 	buf := make([]byte, 0, 256*1024)
 	scanner.Buffer(buf, 1024*1024)
 
-	var entries []TranscriptEntry
-	var pendingMessage strings.Builder
+	var events []transcript.Event
 	lineNum := 0
 
 	for scanner.Scan() {
@@ -39,107 +32,80 @@ func ReadTranscript(path string, fromLine int) ([]TranscriptEntry, int, error) {
 		if lineNum <= fromLine {
 			continue
 		}
-
-		entry := parseEntry(scanner.Bytes())
-
-		// Accumulate assistant message deltas
-		if entry.Type == "message" {
-			pendingMessage.WriteString(entry.Content)
-			continue
-		}
-
-		// Flush pending message when we hit a non-message entry
-		if pendingMessage.Len() > 0 {
-			entries = append(entries, TranscriptEntry{
-				Type:    "message",
-				Content: pendingMessage.String(),
-			})
-			pendingMessage.Reset()
-		}
-
-		if entry.Content != "" {
-			entries = append(entries, entry)
+		for _, e := range ParseLine(scanner.Bytes()) {
+			events = transcript.Append(events, e)
 		}
 	}
 
-	// Flush any remaining message
-	if pendingMessage.Len() > 0 {
-		entries = append(entries, TranscriptEntry{
-			Type:    "message",
-			Content: pendingMessage.String(),
-		})
-	}
-
-	return entries, lineNum, scanner.Err()
+	return events, lineNum, scanner.Err()
 }
 
-func parseEntry(data []byte) TranscriptEntry {
+// ParseLine parses a single line of a Gemini session file. Returns nil for
+// lines without events. Assistant messages are streamed in chunks, marked
+// Delta; ReadTranscript joins consecutive ones.
+func ParseLine(data []byte) []transcript.Event {
 	var raw struct {
 		Type       string                 `json:"type"`
+		Timestamp  string                 `json:"timestamp"`
 		Role       string                 `json:"role"`
 		Content    string                 `json:"content"`
 		ToolName   string                 `json:"tool_name"`
+		ToolID     string                 `json:"tool_id"`
 		Output     string                 `json:"output"`
 		Status     string                 `json:"status"`
+		Model      string                 `json:"model"`
 		Parameters map[string]interface{} `json:"parameters"`
+		Error      struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Stats *struct {
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
+		} `json:"stats"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
-		return TranscriptEntry{}
+		return nil
 	}
+	when, _ := time.Parse(time.RFC3339Nano, raw.Timestamp) // Zero if missing
 
 	switch raw.Type {
+	case "init":
+		return []transcript.Event{transcript.Lifecycle{Time: when, Phase: transcript.LifecycleStart, Detail: raw.Model, Model: raw.Model}}
+
 	case "message":
+		if raw.Content == "" {
+			return nil
+		}
 		if raw.Role == "user" {
-			return TranscriptEntry{Type: "user", Content: raw.Content}
+			return []transcript.Event{transcript.UserPrompt{Time: when, Text: raw.Content}}
 		}
-		// Assistant message (may be delta)
-		return TranscriptEntry{Type: "message", Content: raw.Content}
+		return []transcript.Event{transcript.AssistantText{Time: when, Text: raw.Content, Delta: true}}
 
 	case "tool_use":
-		return TranscriptEntry{
-			Type:      "tool",
-			Content:   fmt.Sprintf("[tool: %s]", raw.ToolName), // Keep for backwards compat
-			ToolName:  raw.ToolName,
-			ToolInput: raw.Parameters,
-		}
+		return []transcript.Event{transcript.ToolCall{Time: when, ID: raw.ToolID, Name: raw.ToolName, Input: raw.Parameters}}
 
 	case "tool_result":
 		output := raw.Output
-		// Truncate long outputs
-		runes := []rune(output)
-		if len(runes) > 200 {
-			output = string(runes[:200]) + "..."
+		if output == "" {
+			output = raw.Error.Message
 		}
-		return TranscriptEntry{Type: "tool_output", Content: output}
-
-	case "init", "result":
-		// Skip these - init is metadata, result is just stats
-		return TranscriptEntry{}
-	}
-
-	return TranscriptEntry{}
-}
-
-// FormatEntries formats transcript entries for display
-func FormatEntries(entries []TranscriptEntry) string {
-	var sb strings.Builder
-	for _, e := range entries {
-		switch e.Type {
-		case "user":
-			sb.WriteString("[user] ")
-			sb.WriteString(e.Content)
-			sb.WriteString("\n\n")
-		case "message":
-			sb.WriteString(e.Content)
-			sb.WriteString("\n\n")
-		case "tool":
-			sb.WriteString(e.Content)
-			sb.WriteString("\n")
-		case "tool_output":
-			sb.WriteString("  -> ")
-			sb.WriteString(e.Content)
-			sb.WriteString("\n")
+		return []transcript.Event{transcript.ToolResult{Time: when, ID: raw.ToolID, Output: output, IsError: raw.Status == "error"}}
+
+	case "result":
+		var events []transcript.Event
+		if raw.Stats != nil {
+			events = append(events, transcript.Usage{
+				Time:   when,
+				Tokens: usage.Tokens{Input: raw.Stats.InputTokens, Output: raw.Stats.OutputTokens},
+			})
 		}
+		return append(events, transcript.Lifecycle{
+			Time:    when,
+			Phase:   transcript.LifecycleEnd,
+			Detail:  raw.Status,
+			IsError: raw.Status == "error",
+		})
 	}
-	return sb.String()
+
+	return nil
 }
@@ -4,72 +4,76 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
-)
+	"time"
 
-func TestParseEntryInit(t *testing.T) {
-	data := []byte(`{"type":"init","timestamp":"2026-01-07T10:02:12.875Z","session_id":"8b6238bf","model":"auto-gemini-3"}`)
-	entry := parseEntry(data)
+	"github.com/sky-xo/june/internal/transcript"
+)
 
-	if entry.Type != "" {
-		t.Errorf("init should be skipped, got Type=%q", entry.Type)
+// parseOne parses a line that should produce exactly one event.
+func parseOne(t *testing.T, data string) transcript.Event {
+	t.Helper()
+	events := ParseLine([]byte(data))
+	if len(events) != 1 {
+		t.Fatalf("ParseLine() = %+v, want one event", events)
 	}
+	return events[0]
 }
 
-func TestParseEntryUserMessage(t *testing.T) {
-	data := []byte(`{"type":"message","timestamp":"...","role":"user","content":"Fix the bug"}`)
-	entry := parseEntry(data)
+func TestParseLineInit(t *testing.T) {
+	e := parseOne(t, `{"type":"init","timestamp":"2026-01-07T10:02:12.875Z","session_id":"8b6238bf","model":"auto-gemini-3"}`)
 
-	if entry.Type != "user" {
-		t.Errorf("Type = %q, want %q", entry.Type, "user")
+	l, ok := e.(transcript.Lifecycle)
+	if !ok || l.Phase != transcript.LifecycleStart || l.Detail != "auto-gemini-3" {
+		t.Errorf("event = %+v, want a start Lifecycle with the model", e)
 	}
-	if entry.Content != "Fix the bug" {
-		t.Errorf("Content = %q, want %q", entry.Content, "Fix the bug")
+	if want := time.Date(2026, 1, 7, 10, 2, 12, 875000000, time.UTC); !l.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", l.Time, want)
 	}
 }
 
-func TestParseEntryAssistantMessage(t *testing.T) {
-	data := []byte(`{"type":"message","timestamp":"...","role":"assistant","content":"I fixed it","delta":true}`)
-	entry := parseEntry(data)
+func TestParseLineUserMessage(t *testing.T) {
+	e := parseOne(t, `{"type":"message","timestamp":"...","role":"user","content":"Fix the bug"}`)
 
-	if entry.Type != "message" {
-		t.Errorf("Type = %q, want %q", entry.Type, "message")
-	}
-	if entry.Content != "I fixed it" {
-		t.Errorf("Content = %q, want %q", entry.Content, "I fixed it")
+	if p, ok := e.(transcript.UserPrompt); !ok || p.Text != "Fix the bug" {
+		t.Errorf("event = %+v, want UserPrompt %q", e, "Fix the bug")
 	}
 }
 
-func TestParseEntryToolUse(t *testing.T) {
-	data := []byte(`{"type":"tool_use","timestamp":"...","tool_name":"read_file","tool_id":"abc","parameters":{"path":"main.go"}}`)
-	entry := parseEntry(data)
+func TestParseLineAssistantMessage(t *testing.T) {
+	e := parseOne(t, `{"type":"message","timestamp":"...","role":"assistant","content":"I fixed it","delta":true}`)
 
-	if entry.Type != "tool" {
-		t.Errorf("Type = %q, want %q", entry.Type, "tool")
-	}
-	if entry.Content != "[tool: read_file]" {
-		t.Errorf("Content = %q, want %q", entry.Content, "[tool: read_file]")
+	if text, ok := e.(transcript.AssistantText); !ok || text.Text != "I fixed it" || !text.Delta {
+		t.Errorf("event = %+v, want Delta AssistantText %q", e, "I fixed it")
 	}
 }
 
-func TestParseEntryToolResult(t *testing.T) {
-	data := []byte(`{"type":"tool_result","timestamp":"...","tool_id":"abc","status":"success","output":"file contents here"}`)
-	entry := parseEntry(data)
+func TestParseLineToolUse(t *testing.T) {
+	e := parseOne(t, `{"type":"tool_use","timestamp":"...","tool_name":"read_file","tool_id":"abc","parameters":{"path":"main.go"}}`)
 
-	if entry.Type != "tool_output" {
-		t.Errorf("Type = %q, want %q", entry.Type, "tool_output")
+	if call, ok := e.(transcript.ToolCall); !ok || call.Name != "read_file" || call.ID != "abc" {
+		t.Errorf("event = %+v, want a read_file ToolCall with ID abc", e)
 	}
-	if entry.Content != "file contents here" {
-		t.Errorf("Content = %q, want %q", entry.Content, "file contents here")
+}
+
+func TestParseLineToolResult(t *testing.T) {
+	e := parseOne(t, `{"type":"tool_result","timestamp":"...","tool_id":"abc","status":"success","output":"file contents here"}`)
+
+	if r, ok := e.(transcript.ToolResult); !ok || r.Output != "file contents here" || r.ID != "abc" || r.IsError {
+		t.Errorf("event = %+v, want a successful ToolResult for abc", e)
 	}
 }
 
-func TestParseEntryResult(t *testing.T) {
-	data := []byte(`{"type":"result","timestamp":"...","status":"success","stats":{"total_tokens":100}}`)
-	entry := parseEntry(data)
+func TestParseLineResult(t *testing.T) {
+	events := ParseLine([]byte(`{"type":"result","timestamp":"...","status":"success","stats":{"input_tokens":80,"output_tokens":20}}`))
 
-	// Result events are skipped (just stats)
-	if entry.Type != "" {
-		t.Errorf("result should be skipped, got Type=%q", entry.Type)
+	if len(events) != 2 {
+		t.Fatalf("ParseLine() = %+v, want usage then the end of the run", events)
+	}
+	if u, ok := events[0].(transcript.Usage); !ok || u.Input != 80 || u.Output != 20 {
+		t.Errorf("events[0] = %+v, want Usage of 80 input, 20 output tokens", events[0])
+	}
+	if l, ok := events[1].(transcript.Lifecycle); !ok || l.Phase != transcript.LifecycleEnd || l.Detail != "success" || l.IsError {
+		t.Errorf("events[1] = %+v, want a successful end Lifecycle", events[1])
 	}
 }
 
@@ -89,43 +93,48 @@ func TestReadTranscriptAccumulatesDeltas(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	entries, _, err := ReadTranscript(sessionFile, 0)
+	events, _, err := ReadTranscript(sessionFile, 0)
 	if err != nil {
 		t.Fatalf("ReadTranscript failed: %v", err)
 	}
 
-	// Should have: user message, accumulated assistant message, tool
-	if len(entries) != 3 {
-		t.Fatalf("len(entries) = %d, want 3", len(entries))
+	// Should have: start, user message, accumulated assistant message, tool, end
+	if len(events) != 5 {
+		t.Fatalf("len(events) = %d, want 5", len(events))
 	}
 
-	if entries[0].Type != "user" || entries[0].Content != "Hello" {
-		t.Errorf("entries[0] = %+v, want user/Hello", entries[0])
+	if p, ok := events[1].(transcript.UserPrompt); !ok || p.Text != "Hello" {
+		t.Errorf("events[1] = %+v, want UserPrompt Hello", events[1])
 	}
 
-	if entries[1].Type != "message" || entries[1].Content != "Hi there!" {
-		t.Errorf("entries[1] = %+v, want message/'Hi there!'", entries[1])
+	if text, ok := events[2].(transcript.AssistantText); !ok || text.Text != "Hi there!" {
+		t.Errorf("events[2] = %+v, want AssistantText 'Hi there!'", events[2])
 	}
 
-	if entries[2].Type != "tool" {
-		t.Errorf("entries[2].Type = %q, want tool", entries[2].Type)
+	if _, ok := events[3].(transcript.ToolCall); !ok {
+		t.Errorf("events[3] = %T, want ToolCall", events[3])
 	}
 }
 
-func TestParseEntryToolUseWithParameters(t *testing.T) {
-	data := []byte(`{"type":"tool_use","timestamp":"...","tool_name":"read_file","tool_id":"abc","parameters":{"path":"main.go","encoding":"utf-8"}}`)
-	entry := parseEntry(data)
+func TestParseLineToolUseWithParameters(t *testing.T) {
+	e := parseOne(t, `{"type":"tool_use","timestamp":"...","tool_name":"read_file","tool_id":"abc","parameters":{"path":"main.go","encoding":"utf-8"}}`)
 
-	if entry.Type != "tool" {
-		t.Errorf("Type = %q, want %q", entry.Type, "tool")
+	call, ok := e.(transcript.ToolCall)
+	if !ok {
+		t.Fatalf("event = %+v, want a ToolCall", e)
 	}
-	if entry.ToolName != "read_file" {
-		t.Errorf("ToolName = %q, want %q", entry.ToolName, "read_file")
+	if call.Input == nil {
+		t.Fatal("Input is nil, want map with path")
 	}
-	if entry.ToolInput == nil {
-		t.Fatal("ToolInput is nil, want map with path")
+	if path, ok := call.Input["path"].(string); !ok || path != "main.go" {
+		t.Errorf("Input[path] = %v, want %q", call.Input["path"], "main.go")
 	}
-	if path, ok := entry.ToolInput["path"].(string); !ok || path != "main.go" {
-		t.Errorf("ToolInput[path] = %v, want %q", entry.ToolInput["path"], "main.go")
+}
+
+func TestParseLineToolResultError(t *testing.T) {
+	e := parseOne(t, `{"type":"tool_result","tool_id":"abc","status":"error","error":{"type":"invalid_tool_params","message":"file not found"}}`)
+
+	if r, ok := e.(transcript.ToolResult); !ok || r.Output != "file not found" || !r.IsError {
+		t.Errorf("event = %+v, want an error ToolResult with the error message", e)
 	}
 }
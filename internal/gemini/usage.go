@@ -0,0 +1,12 @@
+package gemini
+
+import (
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+// ReadUsage returns the token usage of each run recorded in a Gemini session
+// file, from the stats of its result events.
+func ReadUsage(path string) ([]usage.Record, error) {
+	return transcript.ReadUsage(path, ParseLine)
+}
@@ -0,0 +1,34 @@
+package gemini
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func TestReadUsageFromResultStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	content := `{"type":"init","timestamp":"2026-01-07T10:00:00Z","session_id":"s1","model":"gemini-2.5-pro"}
+{"type":"message","timestamp":"2026-01-07T10:00:01Z","role":"assistant","content":"Done","delta":true}
+{"type":"result","timestamp":"2026-01-07T10:00:02Z","status":"success","stats":{"total_tokens":1200,"input_tokens":1000,"output_tokens":200,"duration_ms":900,"tool_calls":0}}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadUsage(path)
+	if err != nil {
+		t.Fatalf("ReadUsage: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if want := (usage.Tokens{Input: 1000, Output: 200}); records[0].Tokens != want {
+		t.Errorf("tokens = %+v, want %+v", records[0].Tokens, want)
+	}
+	if records[0].Model != "gemini-2.5-pro" {
+		t.Errorf("model = %q, want gemini-2.5-pro", records[0].Model)
+	}
+}
@@ -0,0 +1,182 @@
+// Package proc inspects the processes of spawned agents.
+package proc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Alive reports whether a process with the given PID exists.
+// A PID of 0 or less is never considered alive.
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// Signal 0 performs error checking only. EPERM means the process exists
+	// but belongs to another user.
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// startSlack allows for the coarse clocks StartTime and recorded times are
+// read from.
+const startSlack = 2 * time.Second
+
+// clockTicks is the unit of process start times in /proc/<pid>/stat. It is
+// USER_HZ, which is 100 on every Linux architecture.
+const clockTicks = 100
+
+// StartTime returns when a running process was started.
+func StartTime(pid int) (time.Time, error) {
+	if pid <= 0 {
+		return time.Time{}, fmt.Errorf("invalid pid %d", pid)
+	}
+	if runtime.GOOS == "linux" {
+		return linuxStartTime(pid)
+	}
+	// No /proc on macOS; ask ps instead
+	cmd := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid))
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation("Mon Jan 2 15:04:05 2006", strings.Join(strings.Fields(string(out)), " "), time.Local)
+}
+
+// linuxStartTime reads a process's start time, which /proc records in clock
+// ticks since boot.
+func linuxStartTime(pid int) (time.Time, error) {
+	fields, err := statFields(pid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// starttime is field 22 of stat; fields start at field 3
+	if len(fields) < 20 {
+		return time.Time{}, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	ticks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if btime, ok := strings.CutPrefix(line, "btime "); ok {
+			boot, err := strconv.ParseInt(strings.TrimSpace(btime), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(boot, 0).Add(time.Duration(ticks) * time.Second / clockTicks), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("boot time not found in /proc/stat")
+}
+
+// statFields returns the fields of /proc/<pid>/stat after the command name,
+// starting with the state (field 3).
+func statFields(pid int) ([]string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	// Format: pid (comm) state ... - comm may contain spaces, so find the last ')'
+	s := string(data)
+	idx := strings.LastIndex(s, ")")
+	if idx == -1 {
+		return nil, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	return strings.Fields(s[idx+1:]), nil
+}
+
+// BelongsTo reports whether pid is alive and is still the process that was
+// recorded at recordedAt, i.e. it started no later than that. This guards
+// against signalling an unrelated process that was assigned a recycled PID
+// after the agent exited.
+func BelongsTo(pid int, recordedAt time.Time) bool {
+	if !Alive(pid) || recordedAt.IsZero() {
+		return false
+	}
+	started, err := StartTime(pid)
+	if err != nil {
+		return false
+	}
+	return !started.After(recordedAt.Add(startSlack))
+}
+
+// Stop terminates the process group of the process recorded with pid at
+// recordedAt, like Terminate. It refuses a process that has exited or a
+// newer one that reused the PID.
+func Stop(pid int, recordedAt time.Time, grace time.Duration) (syscall.Signal, error) {
+	if !Alive(pid) {
+		return 0, fmt.Errorf("process %d is not running", pid)
+	}
+	if !BelongsTo(pid, recordedAt) {
+		return 0, fmt.Errorf("pid %d now belongs to another process, refusing to signal it", pid)
+	}
+	return Terminate(pid, grace)
+}
+
+// Terminate sends SIGTERM to the process group led by pid, waits up to grace
+// for it to exit, then escalates to SIGKILL. It returns the last signal sent.
+// Processes that don't lead their own group are signalled directly.
+func Terminate(pid int, grace time.Duration) (syscall.Signal, error) {
+	if err := signalGroup(pid, syscall.SIGTERM); err != nil {
+		return syscall.SIGTERM, err
+	}
+	if waitExit(pid, grace) {
+		return syscall.SIGTERM, nil
+	}
+	if err := signalGroup(pid, syscall.SIGKILL); err != nil {
+		return syscall.SIGKILL, err
+	}
+	waitExit(pid, time.Second)
+	return syscall.SIGKILL, nil
+}
+
+// signalGroup signals the process group led by pid, falling back to the
+// process itself (agents spawned before they got their own group).
+func signalGroup(pid int, sig syscall.Signal) error {
+	if err := syscall.Kill(-pid, sig); err == nil {
+		return nil
+	}
+	err := syscall.Kill(pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		return nil // Already gone
+	}
+	return err
+}
+
+// waitExit polls until pid exits or timeout elapses. Returns true if it exited.
+func waitExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if !Alive(pid) || isZombie(pid) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// isZombie reports whether pid has exited but not yet been reaped by its
+// parent (e.g. the june process supervising it). Only detectable on Linux.
+func isZombie(pid int) bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	fields, err := statFields(pid)
+	return err == nil && len(fields) > 0 && fields[0] == "Z"
+}
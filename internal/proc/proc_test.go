@@ -0,0 +1,134 @@
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestAlive_CurrentProcess(t *testing.T) {
+	if !Alive(os.Getpid()) {
+		t.Error("Alive(own pid) = false, want true")
+	}
+}
+
+func TestAlive_InvalidPID(t *testing.T) {
+	for _, pid := range []int{0, -1} {
+		if Alive(pid) {
+			t.Errorf("Alive(%d) = true, want false", pid)
+		}
+	}
+}
+
+func TestAlive_ExitedProcess(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run true: %v", err)
+	}
+	if Alive(cmd.Process.Pid) {
+		t.Errorf("Alive(%d) = true for reaped process, want false", cmd.Process.Pid)
+	}
+}
+
+// startSleep starts a sleep process leading its own process group.
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot run sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+func TestStartTime(t *testing.T) {
+	before := time.Now()
+	cmd := startSleep(t)
+
+	started, err := StartTime(cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("StartTime: %v", err)
+	}
+	if started.Before(before.Add(-startSlack)) || started.After(time.Now().Add(startSlack)) {
+		t.Errorf("StartTime = %v, want about %v", started, before)
+	}
+}
+
+func TestBelongsTo(t *testing.T) {
+	cmd := startSleep(t)
+	pid := cmd.Process.Pid
+
+	if !BelongsTo(pid, time.Now()) {
+		t.Errorf("BelongsTo(%d, now) = false, want true", pid)
+	}
+	// A process started after the agent was recorded reused its PID
+	if BelongsTo(pid, time.Now().Add(-time.Hour)) {
+		t.Errorf("BelongsTo(%d, an hour ago) = true, want false", pid)
+	}
+	if BelongsTo(pid, time.Time{}) {
+		t.Errorf("BelongsTo(%d) without a recorded time = true, want false", pid)
+	}
+	if BelongsTo(0, time.Now()) {
+		t.Error("BelongsTo(0) = true, want false")
+	}
+}
+
+func TestStop_RefusesReusedPID(t *testing.T) {
+	cmd := startSleep(t)
+
+	if _, err := Stop(cmd.Process.Pid, time.Now().Add(-time.Hour), time.Second); err == nil {
+		t.Error("Stop should refuse a process started after the agent was recorded")
+	}
+	if !Alive(cmd.Process.Pid) {
+		t.Error("Stop signalled a process it refused")
+	}
+	if _, err := Stop(0, time.Now(), time.Second); err == nil {
+		t.Error("Stop should refuse a process that isn't running")
+	}
+}
+
+func TestTerminate(t *testing.T) {
+	cmd := startSleep(t)
+	pid := cmd.Process.Pid
+
+	sig, err := Terminate(pid, 2*time.Second)
+	if err != nil {
+		t.Fatalf("Terminate: %v", err)
+	}
+	if sig != syscall.SIGTERM {
+		t.Errorf("signal = %v, want SIGTERM", sig)
+	}
+	cmd.Wait()
+	if Alive(pid) {
+		t.Errorf("process %d still alive after Terminate", pid)
+	}
+}
+
+func TestTerminate_EscalatesToSIGKILL(t *testing.T) {
+	// A shell that ignores SIGTERM forces escalation
+	cmd := exec.Command("sh", "-c", "trap '' TERM; while :; do sleep 0.1; done")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot run sh: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	// Give the shell time to install its trap
+	time.Sleep(200 * time.Millisecond)
+
+	sig, err := Terminate(cmd.Process.Pid, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Terminate: %v", err)
+	}
+	if sig != syscall.SIGKILL {
+		t.Errorf("signal = %v, want SIGKILL", sig)
+	}
+}
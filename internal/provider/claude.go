@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func init() {
+	Register(claudeProvider{})
+}
+
+// claudeProvider runs Claude Code headless: `claude -p --output-format
+// stream-json --verbose`. The stream uses the same user/assistant entries as
+// Claude's own transcripts, so june records stdout to
+// ~/.june/claude/sessions/<session_id>.jsonl and the TUI displays it like any
+// Claude transcript.
+type claudeProvider struct{}
+
+func (claudeProvider) Name() string { return "claude" }
+
+func (claudeProvider) ResolveOptions(opts db.SpawnOptions) (db.SpawnOptions, error) {
+	if opts.Sandbox != "" {
+		return opts, fmt.Errorf("Claude does not support --sandbox")
+	}
+	if len(opts.Images) > 0 {
+		return opts, fmt.Errorf("Claude does not support image attachments")
+	}
+	return opts, nil
+}
+
+func (claudeProvider) Command(task string, opts db.SpawnOptions) (*exec.Cmd, error) {
+	return claudeCommand(claudeArgs(task, opts))
+}
+
+func (claudeProvider) ResumeCommand(sessionID, prompt string, opts db.SpawnOptions) (*exec.Cmd, error) {
+	return claudeCommand(claudeResumeArgs(sessionID, prompt, opts))
+}
+
+func (claudeProvider) SessionID(firstLine []byte) string {
+	// Every event carries the session ID; the first is normally system/init
+	var event struct {
+		Type      string `json:"type"`
+		SessionID string `json:"session_id"`
+	}
+	if err := json.Unmarshal(firstLine, &event); err != nil || event.Type != "system" {
+		return ""
+	}
+	return event.SessionID
+}
+
+func (claudeProvider) FindSessionFile(sessionID string) (string, error) {
+	return claude.FindSessionFile(sessionID)
+}
+
+func (claudeProvider) TranscriptPath(sessionID string) (string, error) {
+	return claude.SessionFilePath(sessionID)
+}
+
+func (claudeProvider) ReadTranscript(path string, fromLine int) ([]transcript.Event, int, error) {
+	return claude.ReadEvents(path, fromLine)
+}
+
+func (claudeProvider) ParseLine(line []byte) []transcript.Event {
+	return claude.ParseLine(line)
+}
+
+func (claudeProvider) ReadUsage(path string) ([]usage.Record, error) {
+	return claude.ReadUsage(path)
+}
+
+func (claudeProvider) NormalizeTool(name string, input map[string]interface{}) (string, map[string]interface{}) {
+	// Already Claude's tool names
+	return normalizeTool(nil, name, input)
+}
+
+// claudeCommand builds a claude command after checking the CLI is installed
+// and june's sessions directory exists.
+func claudeCommand(args []string) (*exec.Cmd, error) {
+	if _, err := exec.LookPath("claude"); err != nil {
+		return nil, fmt.Errorf("claude CLI not found - install with: npm install -g @anthropic-ai/claude-code")
+	}
+	if _, err := claude.EnsureSessionsDir(); err != nil {
+		return nil, fmt.Errorf("failed to setup claude sessions directory: %w", err)
+	}
+	return exec.Command("claude", args...), nil
+}
+
+// claudeArgs constructs the argument slice for a headless claude run.
+// Without --yolo, file edits are auto-approved (like Gemini's auto_edit).
+func claudeArgs(task string, opts db.SpawnOptions) []string {
+	args := []string{"-p", task, "--output-format", "stream-json", "--verbose"}
+
+	if opts.Yolo {
+		args = append(args, "--dangerously-skip-permissions")
+	} else {
+		args = append(args, "--permission-mode", "acceptEdits")
+	}
+
+	if opts.Model != "" {
+		args = append(args, "--model", opts.Model)
+	}
+
+	return args
+}
+
+// claudeResumeArgs constructs the arguments for continuing a Claude session.
+func claudeResumeArgs(sessionID, prompt string, opts db.SpawnOptions) []string {
+	return append(claudeArgs(prompt, opts), "--resume", sessionID)
+}
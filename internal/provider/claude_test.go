@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/transcript"
+)
+
+func TestClaudeArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  string
+		model string
+		yolo  bool
+		want  []string
+	}{
+		{
+			name: "basic task with defaults",
+			task: "fix the bug",
+			want: []string{"-p", "fix the bug", "--output-format", "stream-json", "--verbose", "--permission-mode", "acceptEdits"},
+		},
+		{
+			name: "with yolo mode",
+			task: "refactor code",
+			yolo: true,
+			want: []string{"-p", "refactor code", "--output-format", "stream-json", "--verbose", "--dangerously-skip-permissions"},
+		},
+		{
+			name:  "with model",
+			task:  "write tests",
+			model: "sonnet",
+			want:  []string{"-p", "write tests", "--output-format", "stream-json", "--verbose", "--permission-mode", "acceptEdits", "--model", "sonnet"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := claudeArgs(tt.task, db.SpawnOptions{Model: tt.model, Yolo: tt.yolo})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("claudeArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaudeResumeArgs(t *testing.T) {
+	got := claudeResumeArgs("sess-1", "summarize", db.SpawnOptions{Model: "opus"})
+	want := []string{"-p", "summarize", "--output-format", "stream-json", "--verbose", "--permission-mode", "acceptEdits", "--model", "opus", "--resume", "sess-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("claudeResumeArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestClaudeResolveOptions(t *testing.T) {
+	p := claudeProvider{}
+
+	if _, err := p.ResolveOptions(db.SpawnOptions{Model: "sonnet", Yolo: true}); err != nil {
+		t.Errorf("model and yolo should be accepted: %v", err)
+	}
+	if _, err := p.ResolveOptions(db.SpawnOptions{Sandbox: "true"}); err == nil {
+		t.Error("--sandbox should be rejected")
+	}
+	if _, err := p.ResolveOptions(db.SpawnOptions{Images: []string{"/tmp/mockup.png"}}); err == nil {
+		t.Error("image attachments should be rejected")
+	}
+}
+
+func TestClaudeSessionID(t *testing.T) {
+	p := claudeProvider{}
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`{"type":"system","subtype":"init","session_id":"abc-123","model":"claude-sonnet-4-5"}`, "abc-123"},
+		{`{"type":"assistant","message":{"role":"assistant","content":[]},"session_id":"abc-123"}`, ""},
+		{`not json`, ""},
+	}
+	for _, tt := range tests {
+		if got := p.SessionID([]byte(tt.line)); got != tt.want {
+			t.Errorf("SessionID(%s) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestClaudeIsOutputRecorder(t *testing.T) {
+	var p Provider = claudeProvider{}
+	if _, ok := p.(OutputRecorder); !ok {
+		t.Error("claude provider should record its stream-json output")
+	}
+}
+
+func TestClaudeReadTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sess.jsonl")
+	content := `{"type":"system","subtype":"init","session_id":"sess"}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"thinking","thinking":"let me look"}]}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"ls"}}]}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":[{"type":"text","text":"main.go"}]}]}}
+{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Done."}]}}
+{"type":"result","subtype":"success","result":"Done."}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, cursor, err := claudeProvider{}.ReadTranscript(path, 0)
+	if err != nil {
+		t.Fatalf("ReadTranscript: %v", err)
+	}
+	if cursor != 6 {
+		t.Errorf("cursor = %d, want 6", cursor)
+	}
+	want := []transcript.Event{
+		transcript.Lifecycle{Phase: transcript.LifecycleStart},
+		transcript.Reasoning{Text: "let me look"},
+		transcript.ToolCall{ID: "t1", Name: "Bash", Input: map[string]interface{}{"command": "ls"}},
+		transcript.ToolResult{ID: "t1", Output: "main.go"},
+		transcript.AssistantText{Text: "Done."},
+		transcript.Lifecycle{Phase: transcript.LifecycleEnd, Detail: "success"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/sky-xo/june/internal/codex"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func init() {
+	Register(codexProvider{})
+}
+
+// codexProvider runs `codex exec --json`. Codex writes its own session file
+// under the isolated CODEX_HOME (~/.june/codex).
+type codexProvider struct{}
+
+// codexTools maps Codex tool names to Claude equivalents.
+var codexTools = map[string]string{
+	"shell_command": "Bash",
+	"read_file":     "Read",
+	"write_file":    "Write",
+	"edit_file":     "Edit",
+}
+
+func (codexProvider) Name() string { return "codex" }
+
+func (codexProvider) ResolveOptions(opts db.SpawnOptions) (db.SpawnOptions, error) {
+	// --sandbox without a value means workspace-write
+	if opts.Sandbox == "true" {
+		opts.Sandbox = "workspace-write"
+	}
+	return opts, nil
+}
+
+func (codexProvider) Command(task string, opts db.SpawnOptions) (*exec.Cmd, error) {
+	return codexCommand(codexArgs(task, opts))
+}
+
+func (codexProvider) ResumeCommand(sessionID, prompt string, opts db.SpawnOptions) (*exec.Cmd, error) {
+	// Codex appends the resumed turn to the thread's existing session file
+	return codexCommand(codexResumeArgs(sessionID, prompt, opts))
+}
+
+func (codexProvider) SessionID(firstLine []byte) string {
+	var event struct {
+		Type     string `json:"type"`
+		ThreadID string `json:"thread_id"`
+	}
+	if err := json.Unmarshal(firstLine, &event); err != nil || event.Type != "thread.started" {
+		return ""
+	}
+	return event.ThreadID
+}
+
+func (codexProvider) FindSessionFile(sessionID string) (string, error) {
+	return codex.FindSessionFile(sessionID)
+}
+
+func (codexProvider) ReadTranscript(path string, fromLine int) ([]transcript.Event, int, error) {
+	return codex.ReadTranscript(path, fromLine)
+}
+
+func (codexProvider) ParseLine(line []byte) []transcript.Event {
+	return codex.ParseLine(line)
+}
+
+func (codexProvider) ReadUsage(path string) ([]usage.Record, error) {
+	return codex.ReadUsage(path)
+}
+
+func (codexProvider) NormalizeTool(name string, input map[string]interface{}) (string, map[string]interface{}) {
+	return normalizeTool(codexTools, name, input)
+}
+
+// codexCommand builds a codex command that uses june's isolated codex home.
+func codexCommand(args []string) (*exec.Cmd, error) {
+	isolatedCodexHome, err := codex.EnsureCodexHome()
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup isolated codex home: %w", err)
+	}
+	cmd := exec.Command("codex", args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("CODEX_HOME=%s", isolatedCodexHome))
+	return cmd, nil
+}
+
+// codexArgs constructs the argument slice for the codex exec command.
+func codexArgs(task string, opts db.SpawnOptions) []string {
+	args := append([]string{"exec", "--json"}, codexOptionArgs(opts)...)
+	// --image takes several values; attached with "=" it won't swallow the task
+	for _, image := range opts.Images {
+		args = append(args, "--image="+image)
+	}
+	args = append(args, task)
+	return args
+}
+
+// codexResumeArgs constructs the arguments for continuing a Codex thread.
+// The thread keeps the model and sandbox it was spawned with; images were
+// part of the original task and aren't sent again.
+func codexResumeArgs(threadID, prompt string, opts db.SpawnOptions) []string {
+	args := append([]string{"exec", "--json"}, codexOptionArgs(opts)...)
+	return append(args, "resume", threadID, prompt)
+}
+
+// codexOptionArgs returns the codex exec flags for opts.
+func codexOptionArgs(opts db.SpawnOptions) []string {
+	var args []string
+	if opts.Model != "" {
+		args = append(args, "--model", opts.Model)
+	}
+	if opts.ReasoningEffort != "" {
+		args = append(args, "-c", "model_reasoning_effort="+opts.ReasoningEffort)
+	}
+	if opts.MaxTokens > 0 {
+		args = append(args, "-c", fmt.Sprintf("model_max_output_tokens=%d", opts.MaxTokens))
+	}
+	if opts.Sandbox != "" {
+		args = append(args, "--sandbox", opts.Sandbox)
+	}
+	return args
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sky-xo/june/internal/db"
+)
+
+func TestCodexArgs(t *testing.T) {
+	tests := []struct {
+		name            string
+		task            string
+		model           string
+		reasoningEffort string
+		sandbox         string
+		maxTokens       int
+		images          []string
+		want            []string
+	}{
+		{
+			name: "task only (no flags)",
+			task: "implement feature",
+			want: []string{"exec", "--json", "implement feature"},
+		},
+		{
+			name:  "with model flag",
+			task:  "implement feature",
+			model: "o3",
+			want:  []string{"exec", "--json", "--model", "o3", "implement feature"},
+		},
+		{
+			name:            "with reasoning-effort flag",
+			task:            "implement feature",
+			reasoningEffort: "high",
+			want:            []string{"exec", "--json", "-c", "model_reasoning_effort=high", "implement feature"},
+		},
+		{
+			name:      "with max-tokens flag",
+			task:      "implement feature",
+			maxTokens: 4096,
+			want:      []string{"exec", "--json", "-c", "model_max_output_tokens=4096", "implement feature"},
+		},
+		{
+			name:    "with sandbox flag",
+			task:    "implement feature",
+			sandbox: "workspace-write",
+			want:    []string{"exec", "--json", "--sandbox", "workspace-write", "implement feature"},
+		},
+		{
+			name:            "with all flags",
+			task:            "implement feature",
+			model:           "o3",
+			reasoningEffort: "medium",
+			maxTokens:       8192,
+			sandbox:         "read-only",
+			want: []string{
+				"exec", "--json",
+				"--model", "o3",
+				"-c", "model_reasoning_effort=medium",
+				"-c", "model_max_output_tokens=8192",
+				"--sandbox", "read-only",
+				"implement feature",
+			},
+		},
+		{
+			name:            "with model and reasoning-effort",
+			task:            "fix bug",
+			model:           "gpt-4",
+			reasoningEffort: "low",
+			want: []string{
+				"exec", "--json",
+				"--model", "gpt-4",
+				"-c", "model_reasoning_effort=low",
+				"fix bug",
+			},
+		},
+		{
+			name:      "with sandbox and max-tokens",
+			task:      "refactor code",
+			maxTokens: 2048,
+			sandbox:   "danger-full-access",
+			want: []string{
+				"exec", "--json",
+				"-c", "model_max_output_tokens=2048",
+				"--sandbox", "danger-full-access",
+				"refactor code",
+			},
+		},
+		{
+			name:   "with images",
+			task:   "match the mockup",
+			images: []string{"/tmp/mockup.png", "/tmp/current.png"},
+			want:   []string{"exec", "--json", "--image=/tmp/mockup.png", "--image=/tmp/current.png", "match the mockup"},
+		},
+		{
+			name:      "zero max-tokens is ignored",
+			task:      "implement feature",
+			maxTokens: 0,
+			want:      []string{"exec", "--json", "implement feature"},
+		},
+		{
+			name:  "empty model is ignored",
+			task:  "implement feature",
+			model: "",
+			want:  []string{"exec", "--json", "implement feature"},
+		},
+		{
+			name:            "empty reasoning-effort is ignored",
+			task:            "implement feature",
+			reasoningEffort: "",
+			want:            []string{"exec", "--json", "implement feature"},
+		},
+		{
+			name:    "empty sandbox is ignored",
+			task:    "implement feature",
+			sandbox: "",
+			want:    []string{"exec", "--json", "implement feature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := db.SpawnOptions{Model: tt.model, ReasoningEffort: tt.reasoningEffort, Sandbox: tt.sandbox, MaxTokens: tt.maxTokens, Images: tt.images}
+			got := codexArgs(tt.task, opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("codexArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodexResumeArgs(t *testing.T) {
+	got := codexResumeArgs("thread-123", "add tests", db.SpawnOptions{})
+	want := []string{"exec", "--json", "resume", "thread-123", "add tests"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("codexResumeArgs() = %v, want %v", got, want)
+	}
+
+	// The thread keeps its model and sandbox
+	opts := db.SpawnOptions{Model: "o3", Sandbox: "read-only", Images: []string{"/tmp/mockup.png"}}
+	got = codexResumeArgs("thread-123", "add tests", opts)
+	want = []string{"exec", "--json", "--model", "o3", "--sandbox", "read-only", "resume", "thread-123", "add tests"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("codexResumeArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestCodexResolveOptions(t *testing.T) {
+	p := codexProvider{}
+
+	opts, err := p.ResolveOptions(db.SpawnOptions{Sandbox: "true"})
+	if err != nil {
+		t.Fatalf("ResolveOptions: %v", err)
+	}
+	if opts.Sandbox != "workspace-write" {
+		t.Errorf("Sandbox = %q, want workspace-write for bare --sandbox", opts.Sandbox)
+	}
+
+	opts, _ = p.ResolveOptions(db.SpawnOptions{Sandbox: "read-only"})
+	if opts.Sandbox != "read-only" {
+		t.Errorf("Sandbox = %q, want explicit value kept", opts.Sandbox)
+	}
+}
+
+func TestCodexSessionID(t *testing.T) {
+	p := codexProvider{}
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`{"type":"thread.started","thread_id":"019b-abc"}`, "019b-abc"},
+		{`{"type":"turn.started"}`, ""},
+		{`not json`, ""},
+	}
+	for _, tt := range tests {
+		if got := p.SessionID([]byte(tt.line)); got != tt.want {
+			t.Errorf("SessionID(%s) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func init() {
+	Register(geminiProvider{})
+}
+
+// geminiProvider runs `gemini -p --output-format stream-json`. Gemini doesn't
+// write a transcript june can read, so june records its stdout to
+// ~/.june/gemini/sessions/<session_id>.jsonl.
+type geminiProvider struct{}
+
+// geminiTools maps Gemini tool names to Claude equivalents.
+var geminiTools = map[string]string{
+	"shell":      "Bash",
+	"read_file":  "Read",
+	"write_file": "Write",
+	"edit_file":  "Edit",
+}
+
+func (geminiProvider) Name() string { return "gemini" }
+
+func (geminiProvider) ResolveOptions(opts db.SpawnOptions) (db.SpawnOptions, error) {
+	// Gemini sandbox is boolean-only, reject explicit values
+	if opts.Sandbox != "" && opts.Sandbox != "true" {
+		return opts, fmt.Errorf("Gemini --sandbox does not accept values, use --sandbox without a value")
+	}
+	if len(opts.Images) > 0 {
+		return opts, fmt.Errorf("Gemini does not support image attachments")
+	}
+	return opts, nil
+}
+
+func (geminiProvider) Command(task string, opts db.SpawnOptions) (*exec.Cmd, error) {
+	return geminiCommand(geminiArgs(task, opts))
+}
+
+func (geminiProvider) ResumeCommand(sessionID, prompt string, opts db.SpawnOptions) (*exec.Cmd, error) {
+	return geminiCommand(geminiResumeArgs(sessionID, prompt, opts))
+}
+
+func (geminiProvider) SessionID(firstLine []byte) string {
+	var event struct {
+		Type      string `json:"type"`
+		SessionID string `json:"session_id"`
+	}
+	if err := json.Unmarshal(firstLine, &event); err != nil || event.Type != "init" {
+		return ""
+	}
+	return event.SessionID
+}
+
+func (geminiProvider) FindSessionFile(sessionID string) (string, error) {
+	return gemini.FindSessionFile(sessionID)
+}
+
+func (geminiProvider) TranscriptPath(sessionID string) (string, error) {
+	return gemini.SessionFilePath(sessionID)
+}
+
+func (geminiProvider) ReadTranscript(path string, fromLine int) ([]transcript.Event, int, error) {
+	return gemini.ReadTranscript(path, fromLine)
+}
+
+func (geminiProvider) ParseLine(line []byte) []transcript.Event {
+	return gemini.ParseLine(line)
+}
+
+func (geminiProvider) ReadUsage(path string) ([]usage.Record, error) {
+	return gemini.ReadUsage(path)
+}
+
+func (geminiProvider) NormalizeTool(name string, input map[string]interface{}) (string, map[string]interface{}) {
+	return normalizeTool(geminiTools, name, input)
+}
+
+// geminiCommand builds a gemini command after checking the CLI is installed
+// and june's gemini home is set up.
+func geminiCommand(args []string) (*exec.Cmd, error) {
+	if _, err := exec.LookPath("gemini"); err != nil {
+		return nil, fmt.Errorf("gemini CLI not found - install with: npm install -g @google/gemini-cli")
+	}
+	// Ensure gemini home exists (copies auth files, creates sessions directory)
+	if _, err := gemini.EnsureGeminiHome(); err != nil {
+		return nil, fmt.Errorf("failed to setup gemini home: %w", err)
+	}
+	return exec.Command("gemini", args...), nil
+}
+
+// geminiArgs constructs the argument slice for the gemini command.
+// Sandbox is boolean for Gemini - any non-empty value passes --sandbox.
+func geminiArgs(task string, opts db.SpawnOptions) []string {
+	args := []string{"-p", task, "--output-format", "stream-json"}
+
+	if opts.Yolo {
+		args = append(args, "--yolo")
+	} else {
+		args = append(args, "--approval-mode", "auto_edit")
+	}
+
+	if opts.Model != "" {
+		args = append(args, "-m", opts.Model)
+	}
+
+	if opts.Sandbox != "" {
+		args = append(args, "--sandbox")
+	}
+
+	return args
+}
+
+// geminiResumeArgs constructs the arguments for continuing a Gemini session
+// with the options it was spawned with.
+func geminiResumeArgs(sessionID, prompt string, opts db.SpawnOptions) []string {
+	return append(geminiArgs(prompt, opts), "--resume", sessionID)
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/transcript"
+)
+
+func TestGeminiArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		task    string
+		model   string
+		yolo    bool
+		sandbox bool
+		want    []string
+	}{
+		{
+			name:    "basic task with defaults",
+			task:    "fix the bug",
+			model:   "",
+			yolo:    false,
+			sandbox: false,
+			want:    []string{"-p", "fix the bug", "--output-format", "stream-json", "--approval-mode", "auto_edit"},
+		},
+		{
+			name:    "with yolo mode",
+			task:    "refactor code",
+			model:   "",
+			yolo:    true,
+			sandbox: false,
+			want:    []string{"-p", "refactor code", "--output-format", "stream-json", "--yolo"},
+		},
+		{
+			name:    "with model",
+			task:    "write tests",
+			model:   "gemini-2.5-pro",
+			yolo:    false,
+			sandbox: false,
+			want:    []string{"-p", "write tests", "--output-format", "stream-json", "--approval-mode", "auto_edit", "-m", "gemini-2.5-pro"},
+		},
+		{
+			name:    "with sandbox",
+			task:    "dangerous task",
+			model:   "",
+			yolo:    true,
+			sandbox: true,
+			want:    []string{"-p", "dangerous task", "--output-format", "stream-json", "--yolo", "--sandbox"},
+		},
+		{
+			name:    "all options",
+			task:    "full task",
+			model:   "gemini-2.5-flash",
+			yolo:    true,
+			sandbox: true,
+			want:    []string{"-p", "full task", "--output-format", "stream-json", "--yolo", "-m", "gemini-2.5-flash", "--sandbox"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := db.SpawnOptions{Model: tt.model, Yolo: tt.yolo}
+			if tt.sandbox {
+				opts.Sandbox = "true"
+			}
+			got := geminiArgs(tt.task, opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("geminiArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiResumeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts db.SpawnOptions
+		want []string
+	}{
+		{
+			name: "default approval mode",
+			want: []string{"-p", "summarize", "--output-format", "stream-json", "--approval-mode", "auto_edit", "--resume", "sess-1"},
+		},
+		{
+			name: "yolo",
+			opts: db.SpawnOptions{Yolo: true},
+			want: []string{"-p", "summarize", "--output-format", "stream-json", "--yolo", "--resume", "sess-1"},
+		},
+		{
+			name: "keeps model and sandbox",
+			opts: db.SpawnOptions{Model: "gemini-2.5-pro", Sandbox: "true"},
+			want: []string{"-p", "summarize", "--output-format", "stream-json", "--approval-mode", "auto_edit", "-m", "gemini-2.5-pro", "--sandbox", "--resume", "sess-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := geminiResumeArgs("sess-1", "summarize", tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("geminiResumeArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiResolveOptions(t *testing.T) {
+	p := geminiProvider{}
+
+	if _, err := p.ResolveOptions(db.SpawnOptions{Sandbox: "true"}); err != nil {
+		t.Errorf("bare --sandbox should be accepted: %v", err)
+	}
+	if _, err := p.ResolveOptions(db.SpawnOptions{Sandbox: "read-only"}); err == nil {
+		t.Error("explicit sandbox value should be rejected")
+	}
+	if _, err := p.ResolveOptions(db.SpawnOptions{Images: []string{"/tmp/mockup.png"}}); err == nil {
+		t.Error("image attachments should be rejected")
+	}
+}
+
+func TestGeminiSessionID(t *testing.T) {
+	p := geminiProvider{}
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`{"type":"init","session_id":"abc-123","model":"gemini-2.5-pro"}`, "abc-123"},
+		{`{"type":"message","role":"user","content":"hi"}`, ""},
+		{`not json`, ""},
+	}
+	for _, tt := range tests {
+		if got := p.SessionID([]byte(tt.line)); got != tt.want {
+			t.Errorf("SessionID(%s) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestGeminiIsOutputRecorder(t *testing.T) {
+	var p Provider = geminiProvider{}
+	if _, ok := p.(OutputRecorder); !ok {
+		t.Error("gemini provider should record its own transcript")
+	}
+	var c Provider = codexProvider{}
+	if _, ok := c.(OutputRecorder); ok {
+		t.Error("codex writes its own session file and should not be an OutputRecorder")
+	}
+}
+
+func TestGeminiParseLine(t *testing.T) {
+	p := geminiProvider{}
+
+	got := p.ParseLine([]byte(`{"type":"message","role":"assistant","content":"Hel","delta":true}`))
+	if len(got) != 1 || got[0] != (transcript.AssistantText{Text: "Hel", Delta: true}) {
+		t.Errorf("assistant chunk = %+v, want one Delta AssistantText", got)
+	}
+
+	got = p.ParseLine([]byte(`{"type":"message","role":"user","content":"hi"}`))
+	if len(got) != 1 || got[0] != (transcript.UserPrompt{Text: "hi"}) {
+		t.Errorf("user message = %+v, want one UserPrompt", got)
+	}
+
+	if got := p.ParseLine([]byte(`not json`)); got != nil {
+		t.Errorf("invalid line = %+v, want nil", got)
+	}
+}
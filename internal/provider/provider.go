@@ -0,0 +1,126 @@
+// Package provider defines the interface each spawnable CLI agent (Codex,
+// Gemini, ...) implements, and a registry of the built-in implementations.
+//
+// Adding a new agent CLI means adding one file to this package that
+// implements Provider and registers it in init().
+package provider
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+// Provider is a CLI agent june can spawn and follow.
+type Provider interface {
+	// Name is the agent type passed to june spawn and stored in the database.
+	Name() string
+
+	// ResolveOptions normalizes spawn flags (e.g. applies defaults) and rejects
+	// ones the CLI doesn't support.
+	ResolveOptions(opts db.SpawnOptions) (db.SpawnOptions, error)
+
+	// Command returns the command that runs task. Its stdout must be the
+	// CLI's JSON event stream.
+	Command(task string, opts db.SpawnOptions) (*exec.Cmd, error)
+
+	// ResumeCommand returns the command that continues an existing session
+	// with a follow-up prompt.
+	ResumeCommand(sessionID, prompt string, opts db.SpawnOptions) (*exec.Cmd, error)
+
+	// SessionID extracts the session ID from the first line of output.
+	// Returns "" if the line doesn't carry one.
+	SessionID(firstLine []byte) string
+
+	// FindSessionFile locates an existing session's transcript on disk.
+	FindSessionFile(sessionID string) (string, error)
+
+	// ReadTranscript reads events starting after fromLine and returns the
+	// new line count, so callers can read incrementally. Streamed message
+	// chunks are joined.
+	ReadTranscript(path string, fromLine int) ([]transcript.Event, int, error)
+
+	// ParseLine parses a single transcript line, for callers that tail the
+	// file themselves. Returns nil for lines without events. Streamed
+	// message chunks are returned as is; transcript.Append joins them.
+	ParseLine(line []byte) []transcript.Event
+
+	// ReadUsage returns the token usage recorded in a transcript.
+	ReadUsage(path string) ([]usage.Record, error)
+
+	// NormalizeTool maps a tool call to its Claude equivalent (e.g. "shell"
+	// to "Bash") so the TUI can format it richly.
+	NormalizeTool(name string, input map[string]interface{}) (string, map[string]interface{})
+}
+
+// OutputRecorder is implemented by providers whose CLI doesn't write a
+// transcript of its own. june writes the CLI's stdout to TranscriptPath.
+type OutputRecorder interface {
+	TranscriptPath(sessionID string) (string, error)
+}
+
+var registry = map[string]Provider{}
+
+// Register makes a provider available by name. It panics on duplicates.
+func Register(p Provider) {
+	if _, dup := registry[p.Name()]; dup {
+		panic("provider: Register called twice for " + p.Name())
+	}
+	registry[p.Name()] = p
+}
+
+// Get returns the provider registered under name.
+func Get(name string) (Provider, error) {
+	p, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported agent type: %s (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return p, nil
+}
+
+// Names returns the registered provider names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForAgent returns the provider for a spawned agent. Agents recorded before
+// the type column existed are Codex agents.
+func ForAgent(a *db.Agent) (Provider, error) {
+	if a.Type == "" {
+		return Get("codex")
+	}
+	return Get(a.Type)
+}
+
+// normalizeTool renames a tool using names and, for file tools, renames the
+// "path" parameter to Claude's "file_path". Unknown tools keep their name.
+// The input map is copied, never modified.
+func normalizeTool(names map[string]string, name string, input map[string]interface{}) (string, map[string]interface{}) {
+	normalized := make(map[string]interface{})
+	for k, v := range input {
+		normalized[k] = v
+	}
+
+	claudeName, ok := names[name]
+	if !ok {
+		return name, normalized
+	}
+	switch claudeName {
+	case "Read", "Write", "Edit":
+		if path, ok := normalized["path"]; ok {
+			delete(normalized, "path")
+			normalized["file_path"] = path
+		}
+	}
+	return claudeName, normalized
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sky-xo/june/internal/db"
+)
+
+func TestBuiltinProvidersRegistered(t *testing.T) {
+	want := []string{"claude", "codex", "gemini"}
+	if got := Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	for _, name := range want {
+		p, err := Get(name)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", name, err)
+		}
+		if p.Name() != name {
+			t.Errorf("Get(%q).Name() = %q", name, p.Name())
+		}
+	}
+}
+
+func TestGetUnknownProvider(t *testing.T) {
+	if _, err := Get("nope"); err == nil {
+		t.Error("Get(nope) should fail")
+	}
+}
+
+func TestForAgent_DefaultsToCodex(t *testing.T) {
+	p, err := ForAgent(&db.Agent{})
+	if err != nil {
+		t.Fatalf("ForAgent: %v", err)
+	}
+	if p.Name() != "codex" {
+		t.Errorf("ForAgent(untyped).Name() = %q, want codex", p.Name())
+	}
+}
+
+func TestNormalizeTool(t *testing.T) {
+	names := map[string]string{"shell": "Bash", "read_file": "Read"}
+	input := map[string]interface{}{"path": "main.go"}
+
+	name, got := normalizeTool(names, "read_file", input)
+	if name != "Read" {
+		t.Errorf("name = %q, want Read", name)
+	}
+	if got["file_path"] != "main.go" || got["path"] != nil {
+		t.Errorf("input = %v, want path renamed to file_path", got)
+	}
+	if input["path"] != "main.go" {
+		t.Error("normalizeTool must not modify the caller's map")
+	}
+
+	name, got = normalizeTool(names, "shell", map[string]interface{}{"path": "/tmp"})
+	if name != "Bash" || got["path"] != "/tmp" {
+		t.Errorf("shell: got (%q, %v), want path kept for non-file tools", name, got)
+	}
+
+	name, _ = normalizeTool(names, "custom", nil)
+	if name != "custom" {
+		t.Errorf("unknown tool name = %q, want unchanged", name)
+	}
+}
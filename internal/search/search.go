@@ -0,0 +1,204 @@
+// Package search indexes agent transcripts in june.db for full-text search:
+// message text, reasoning, tool names and tool inputs such as file paths and
+// commands, across Claude, Codex and Gemini agents.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/transcript"
+)
+
+// Query is a search for entries containing all of its terms.
+type Query struct {
+	Text     string    // Whitespace-separated terms; "term*" matches a prefix
+	RepoPath string    // Only agents in this repo, if set
+	Since    time.Time // Only entries written since, if set
+	Limit    int       // Maximum number of hits, 0 for all
+}
+
+// Hit is an entry matching a query.
+type Hit = db.SearchHit
+
+// Update indexes what was appended to the agents' transcripts since the last
+// update. Transcripts that can't be read are skipped.
+func Update(database *db.DB, agents []agent.Agent) error {
+	for _, a := range agents {
+		if a.TranscriptPath == "" {
+			continue
+		}
+		if err := indexAgent(database, a); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to index %s: %w", a.TranscriptPath, err)
+		}
+	}
+	return nil
+}
+
+// Search returns the indexed entries matching q, best matches first.
+func Search(database *db.DB, q Query) ([]Hit, error) {
+	match, err := MatchExpression(q.Text)
+	if err != nil {
+		return nil, err
+	}
+	return database.Search(db.SearchQuery{Match: match, RepoPath: q.RepoPath, Since: q.Since, Limit: q.Limit})
+}
+
+// Terms splits query text into its search terms.
+func Terms(text string) []string {
+	return strings.Fields(text)
+}
+
+// MatchExpression converts query text into an FTS5 query matching entries
+// that contain every term. Terms are quoted, so punctuation such as the
+// slashes in a path is matched literally rather than parsed as syntax.
+func MatchExpression(text string) (string, error) {
+	terms := Terms(text)
+	if len(terms) == 0 {
+		return "", fmt.Errorf("empty search query")
+	}
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		prefix := strings.HasSuffix(term, "*") && len(term) > 1
+		if prefix {
+			term = strings.TrimSuffix(term, "*")
+		}
+		phrases[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+		if prefix {
+			phrases[i] += "*"
+		}
+	}
+	return strings.Join(phrases, " "), nil
+}
+
+// indexAgent indexes the lines appended to a's transcript since it was last
+// indexed, or all of it if the file was truncated or replaced.
+func indexAgent(database *db.DB, a agent.Agent) error {
+	source := a.Source
+	if source == "" {
+		source = agent.SourceClaude
+	}
+	p, err := provider.Get(source)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(a.TranscriptPath)
+	if err != nil {
+		return err
+	}
+
+	state, found, err := database.GetSearchFile(a.TranscriptPath)
+	if err != nil {
+		return err
+	}
+	from := transcript.Position{Inode: state.Inode, Offset: state.Offset}
+	if !found {
+		from = transcript.Position{}
+	}
+
+	var entries []db.SearchEntry
+	streaming := false // Last entry is a streamed message that later deltas extend
+	to, reset, err := transcript.ReadAppended(a.TranscriptPath, from, func(line []byte) {
+		when := lineTime(line, info.ModTime())
+		parsed := p.ParseLine(line)
+		for _, e := range parsed {
+			kind, text := entryText(e)
+			delta := false
+			if t, ok := e.(transcript.AssistantText); ok {
+				delta = t.Delta
+			}
+			if delta && streaming && len(entries) > 0 {
+				entries[len(entries)-1].Text += text
+			} else if kind != "" && strings.TrimSpace(text) != "" {
+				entries = append(entries, db.SearchEntry{Kind: kind, Text: text, Time: when})
+			}
+			streaming = delta
+		}
+		if len(parsed) == 0 {
+			streaming = false
+		}
+	})
+	if err != nil {
+		return err
+	}
+	reset = reset || !found
+	if !reset && to == from && state.AgentName == a.Name {
+		return nil // Unchanged
+	}
+
+	return database.IndexSearchEntries(db.SearchFile{
+		Path:      a.TranscriptPath,
+		AgentID:   a.ID,
+		AgentName: a.Name,
+		Source:    source,
+		RepoPath:  a.RepoPath,
+		Branch:    a.Branch,
+		Inode:     to.Inode,
+		Offset:    to.Offset,
+	}, reset, entries)
+}
+
+// entryText returns the kind of entry an event is indexed as, and its
+// searchable text. Tool calls are indexed by name and the string values of
+// their input (commands, paths, patterns). Tool output isn't indexed: it
+// would copy most of every transcript into june.db. Events that aren't
+// indexed have no kind.
+func entryText(e transcript.Event) (kind, text string) {
+	switch e := e.(type) {
+	case transcript.UserPrompt:
+		return "user", e.Text
+	case transcript.AssistantText:
+		return "message", e.Text
+	case transcript.Reasoning:
+		return "reasoning", e.Text
+	case transcript.ToolCall:
+		return "tool", strings.Join(appendStrings([]string{e.Name}, e.Input), "\n")
+	}
+	return "", ""
+}
+
+// appendStrings appends the string values in v, which is decoded JSON, in a
+// stable order.
+func appendStrings(parts []string, v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		if v != "" {
+			parts = append(parts, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			parts = appendStrings(parts, item)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = appendStrings(parts, v[k])
+		}
+	}
+	return parts
+}
+
+// lineTime returns the timestamp of a transcript line. Claude, Codex and
+// Gemini all record one at the top level of most lines; fallback is used
+// for lines without.
+func lineTime(line []byte, fallback time.Time) time.Time {
+	var ts struct {
+		Timestamp time.Time `json:"timestamp"`
+	}
+	if err := json.Unmarshal(line, &ts); err != nil || ts.Timestamp.IsZero() {
+		return fallback
+	}
+	return ts.Timestamp
+}
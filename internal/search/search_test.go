@@ -0,0 +1,152 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/db"
+)
+
+func openTestDB(t *testing.T) *db.DB {
+	t.Helper()
+	database, err := db.Open(filepath.Join(t.TempDir(), "june.db"))
+	if err != nil {
+		t.Fatalf("db.Open: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"internal/db/db.go", `"internal/db/db.go"`},
+		{"connection  refused", `"connection" "refused"`},
+		{`say "hi"`, `"say" """hi"""`},
+		{"migrat*", `"migrat"*`},
+		{"*", `"*"`},
+	}
+	for _, tt := range tests {
+		got, err := MatchExpression(tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("MatchExpression(%q) = %q, %v; want %q", tt.text, got, err, tt.want)
+		}
+	}
+	if _, err := MatchExpression("   "); err == nil {
+		t.Error("MatchExpression of a blank query should fail")
+	}
+}
+
+func TestUpdateIndexesToolInputsAcrossSources(t *testing.T) {
+	dir := t.TempDir()
+	codexPath := filepath.Join(dir, "rollout.jsonl")
+	appendFile(t, codexPath, `{"timestamp":"2026-01-07T10:00:00Z","type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{\"command\":\"go test ./internal/db/...\"}","call_id":"c1"}}
+{"timestamp":"2026-01-07T10:00:05Z","type":"response_item","payload":{"type":"function_call_output","call_id":"c1","output":"FAIL: TestMigrate"}}
+`)
+	claudePath := filepath.Join(dir, "agent-a1.jsonl")
+	appendFile(t, claudePath, `{"type":"assistant","timestamp":"2026-01-07T11:00:00Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Edit","input":{"file_path":"/code/june/internal/db/db.go","old_string":"a","new_string":"b"}}]}}
+`)
+	geminiPath := filepath.Join(dir, "gemini.jsonl")
+	appendFile(t, geminiPath, `{"type":"message","timestamp":"2026-01-07T12:00:00Z","role":"assistant","content":"The migra","delta":true}
+{"type":"message","timestamp":"2026-01-07T12:00:01Z","role":"assistant","content":"tion looks fine","delta":true}
+`)
+
+	database := openTestDB(t)
+	agents := []agent.Agent{
+		{ID: "01J", Name: "impl-9c4f", Source: agent.SourceCodex, RepoPath: "/code/june", TranscriptPath: codexPath},
+		{ID: "a1", Source: agent.SourceClaude, RepoPath: "/code/june", TranscriptPath: claudePath},
+		{ID: "s1", Name: "research-3b7a", Source: agent.SourceGemini, RepoPath: "/code/other", TranscriptPath: geminiPath},
+		{ID: "gone", Source: agent.SourceClaude, TranscriptPath: filepath.Join(dir, "missing.jsonl")},
+	}
+	if err := Update(database, agents); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	hits, err := Search(database, Query{Text: "internal/db"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("got %d hits for internal/db, want the codex command and the claude edit: %+v", len(hits), hits)
+	}
+
+	hits, _ = Search(database, Query{Text: "shell_command"})
+	if len(hits) != 1 || hits[0].Kind != "tool" || hits[0].AgentName != "impl-9c4f" {
+		t.Errorf("tool name hits = %+v, want impl-9c4f's tool call", hits)
+	}
+	if want := time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC); len(hits) == 1 && !hits[0].Time.Equal(want) {
+		t.Errorf("hit time = %v, want the line's timestamp %v", hits[0].Time, want)
+	}
+
+	// Tool output isn't indexed, so the index doesn't copy every transcript
+	if hits, _ = Search(database, Query{Text: "TestMigrate"}); len(hits) != 0 {
+		t.Errorf("tool output hits = %+v, want none", hits)
+	}
+
+	// Streamed Gemini chunks are indexed as one message
+	hits, _ = Search(database, Query{Text: "migration looks"})
+	if len(hits) != 1 || hits[0].AgentID != "s1" {
+		t.Errorf("gemini hits = %+v, want the joined message", hits)
+	}
+
+	hits, _ = Search(database, Query{Text: "internal/db", RepoPath: "/code/other"})
+	if len(hits) != 0 {
+		t.Errorf("hits in /code/other = %+v, want none", hits)
+	}
+}
+
+func TestUpdateIndexesOnlyAppendedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-a1.jsonl")
+	line := func(text string) string {
+		return `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"` + text + `"}]}}` + "\n"
+	}
+	appendFile(t, path, line("first needle"))
+
+	database := openTestDB(t)
+	agents := []agent.Agent{{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path}}
+	if err := Update(database, agents); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	appendFile(t, path, line("second needle"))
+	if err := Update(database, agents); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := Update(database, agents); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	hits, _ := Search(database, Query{Text: "needle"})
+	if len(hits) != 2 {
+		t.Errorf("got %d hits, want 2 (each line indexed once)", len(hits))
+	}
+
+	// A replaced transcript is re-indexed from scratch
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path, line("rewritten needle"))
+	if err := Update(database, agents); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	hits, _ = Search(database, Query{Text: "needle"})
+	if len(hits) != 1 {
+		t.Errorf("after replacing the file: %d hits, want 1", len(hits))
+	}
+}
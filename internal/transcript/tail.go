@@ -0,0 +1,67 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"syscall"
+)
+
+// Position records how much of a transcript file has been read, so a
+// transcript that's still being written can be read incrementally.
+type Position struct {
+	Inode  uint64 // Identifies the file, to notice it being replaced
+	Offset int64  // Bytes consumed, always at the end of a complete line
+}
+
+// ReadAppended calls fn with each line appended to the file at path since
+// from, newline included, and returns the position after the last one. A
+// final line without a newline may still be being written; it is left for
+// the next read unless it's already complete JSON. If the file was
+// truncated or replaced (e.g. rotated), it is read from the start and reset
+// is true. The zero Position reads the whole file as a reset.
+func ReadAppended(path string, from Position, fn func(line []byte)) (to Position, reset bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return from, false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return from, false, err
+	}
+
+	to = from
+	if inode := fileInode(info); inode != from.Inode || info.Size() < from.Offset {
+		to = Position{Inode: inode}
+		reset = true
+	}
+	if _, err := f.Seek(to.Offset, io.SeekStart); err != nil {
+		return from, false, err
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr == io.EOF && !json.Valid(line) {
+			return to, reset, nil
+		}
+		to.Offset += int64(len(line))
+		fn(line)
+		if readErr == io.EOF {
+			return to, reset, nil
+		}
+		if readErr != nil {
+			return to, reset, readErr
+		}
+	}
+}
+
+// fileInode returns the inode number of the file described by info.
+func fileInode(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readAll reads the lines appended to path since from.
+func readAll(t *testing.T, path string, from Position) ([]string, Position, bool) {
+	t.Helper()
+	var lines []string
+	to, reset, err := ReadAppended(path, from, func(line []byte) {
+		lines = append(lines, string(line))
+	})
+	if err != nil {
+		t.Fatalf("ReadAppended: %v", err)
+	}
+	return lines, to, reset
+}
+
+func TestReadAppended(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	write := func(data string, flag int) {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|flag, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A partly written final line waits for the next read
+	write(`{"a":1}`+"\n"+`{"b":`, os.O_APPEND)
+	lines, pos, reset := readAll(t, path, Position{})
+	if strings.Join(lines, "") != `{"a":1}`+"\n" || !reset {
+		t.Fatalf("first read = %q, reset %v; want the complete line as a reset", lines, reset)
+	}
+
+	write(`2}`+"\n"+`{"c":3}`, os.O_APPEND)
+	lines, pos, reset = readAll(t, path, pos)
+	if strings.Join(lines, "") != `{"b":2}`+"\n"+`{"c":3}` || reset {
+		t.Fatalf("second read = %q, reset %v; want the rest, including complete JSON without a newline", lines, reset)
+	}
+
+	lines, pos, reset = readAll(t, path, pos)
+	if len(lines) != 0 || reset {
+		t.Fatalf("unchanged read = %q, reset %v; want nothing", lines, reset)
+	}
+
+	// Truncated: read again from the start
+	write(`{"d":4}`+"\n", os.O_TRUNC)
+	lines, pos, reset = readAll(t, path, pos)
+	if strings.Join(lines, "") != `{"d":4}`+"\n" || !reset {
+		t.Fatalf("after truncation = %q, reset %v; want the new file as a reset", lines, reset)
+	}
+
+	// Replaced by a longer file: the inode tells
+	replacement := path + ".new"
+	if err := os.WriteFile(replacement, []byte(`{"e":5}`+"\n"+`{"f":6}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(replacement, path); err != nil {
+		t.Fatal(err)
+	}
+	lines, _, reset = readAll(t, path, pos)
+	if len(lines) != 2 || !reset {
+		t.Fatalf("after replacement = %q, reset %v; want the new file as a reset", lines, reset)
+	}
+}
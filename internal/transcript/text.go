@@ -0,0 +1,58 @@
+package transcript
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxTextOutput is how many characters of tool output FormatText shows.
+const maxTextOutput = 200
+
+// FormatText formats events as plain text, for june peek and june logs.
+// Tool output is shortened; usage and lifecycle events are left out.
+func FormatText(events []Event) string {
+	var sb strings.Builder
+	for _, e := range events {
+		switch e := e.(type) {
+		case UserPrompt:
+			sb.WriteString("[user] ")
+			sb.WriteString(e.Text)
+			sb.WriteString("\n\n")
+		case AssistantText:
+			sb.WriteString(e.Text)
+			sb.WriteString("\n\n")
+		case Reasoning:
+			sb.WriteString("[thinking] ")
+			sb.WriteString(e.Text)
+			sb.WriteString("\n\n")
+		case ToolCall:
+			fmt.Fprintf(&sb, "[tool: %s]\n", e.Name)
+		case ToolResult:
+			sb.WriteString("  -> ")
+			sb.WriteString(Shorten(e.Output, maxTextOutput))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// Shorten cuts s to its first n characters, marking the cut with "...".
+func Shorten(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n]) + "..."
+	}
+	return s
+}
+
+// LastText returns the text of the agent's last reply, or "" if it hasn't
+// replied.
+func LastText(events []Event) string {
+	var last string
+	for _, e := range events {
+		if t, ok := e.(AssistantText); ok {
+			last = t.Text
+		}
+	}
+	return last
+}
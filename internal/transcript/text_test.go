@@ -0,0 +1,55 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFormatText(t *testing.T) {
+	events := []Event{
+		Lifecycle{Phase: LifecycleStart},
+		UserPrompt{Text: "fix it"},
+		Reasoning{Text: "looking"},
+		ToolCall{Name: "shell"},
+		ToolResult{Output: "ok"},
+		AssistantText{Text: "done"},
+		Usage{},
+	}
+	want := "[user] fix it\n\n[thinking] looking\n\n[tool: shell]\n  -> ok\ndone\n\n"
+	if got := FormatText(events); got != want {
+		t.Errorf("FormatText() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatTextShortensToolOutput(t *testing.T) {
+	got := FormatText([]Event{ToolResult{Output: strings.Repeat("x", 300)}})
+	if want := "  -> " + strings.Repeat("x", 200) + "...\n"; got != want {
+		t.Errorf("FormatText() = %q, want the first 200 characters", got)
+	}
+}
+
+func TestShortenUTF8(t *testing.T) {
+	// 250 emoji are 250 characters but 1000 bytes; cutting bytes would
+	// split one
+	got := Shorten(strings.Repeat("🎉", 250), 200)
+	if n := utf8.RuneCountInString(got); n != 203 {
+		t.Errorf("rune count = %d, want 203", n)
+	}
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "...") {
+		t.Errorf("Shorten() = %q, want valid UTF-8 ending in ...", got)
+	}
+	if got := Shorten("short", 200); got != "short" {
+		t.Errorf("Shorten(short) = %q, want it unchanged", got)
+	}
+}
+
+func TestLastText(t *testing.T) {
+	events := []Event{AssistantText{Text: "first"}, ToolCall{Name: "Bash"}, AssistantText{Text: "last"}, Lifecycle{Phase: LifecycleEnd}}
+	if got := LastText(events); got != "last" {
+		t.Errorf("LastText() = %q, want %q", got, "last")
+	}
+	if got := LastText(nil); got != "" {
+		t.Errorf("LastText(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,105 @@
+// Package transcript defines the events agent transcripts are made of,
+// independent of the CLI that wrote them. The claude, codex and gemini
+// packages parse their CLIs' transcripts into these events, and the TUI,
+// june peek and june logs render them.
+package transcript
+
+import (
+	"time"
+
+	"github.com/sky-xo/june/internal/usage"
+)
+
+// Event is a transcript event: a UserPrompt, AssistantText, Reasoning,
+// ToolCall, ToolResult, Usage or Lifecycle.
+type Event interface {
+	// When returns when the event was recorded, or the zero time if the
+	// transcript doesn't say.
+	When() time.Time
+}
+
+// UserPrompt is a message sent to the agent.
+type UserPrompt struct {
+	Time time.Time
+	Text string
+}
+
+// AssistantText is a reply from the agent.
+type AssistantText struct {
+	Time time.Time
+	Text string
+	// Delta marks a streamed chunk that continues a directly preceding Delta
+	// text. Append joins them.
+	Delta bool
+}
+
+// Reasoning is the agent thinking out loud, shown apart from its replies.
+type Reasoning struct {
+	Time time.Time
+	Text string
+}
+
+// ToolCall is the agent calling a tool.
+type ToolCall struct {
+	Time  time.Time
+	ID    string // Pairs the call with its ToolResult, empty if unknown
+	Name  string
+	Input map[string]interface{}
+}
+
+// ToolResult is the output of a tool call.
+type ToolResult struct {
+	Time    time.Time
+	ID      string // ID of the ToolCall this answers, empty if unknown
+	Output  string
+	IsError bool
+}
+
+// Usage is token usage the transcript reports.
+type Usage struct {
+	Time  time.Time
+	Model string // Empty if the line doesn't say; then that of the run applies
+	usage.Tokens
+	// MessageID identifies the response the usage is for. A later Usage
+	// with the same ID supersedes this one.
+	MessageID string
+	// Cumulative marks running totals for the session rather than the
+	// usage of one response.
+	Cumulative bool
+}
+
+// Phases of a Lifecycle event.
+const (
+	LifecycleStart = "start" // A session or run started
+	LifecycleEnd   = "end"   // A run finished
+)
+
+// Lifecycle marks a session or run starting or finishing.
+type Lifecycle struct {
+	Time    time.Time
+	Phase   string // LifecycleStart or LifecycleEnd
+	Detail  string // Such as the model at the start or the outcome at the end
+	Model   string // Model the run uses, for LifecycleStart, if the transcript says
+	IsError bool   // The run failed, for LifecycleEnd
+}
+
+func (e UserPrompt) When() time.Time    { return e.Time }
+func (e AssistantText) When() time.Time { return e.Time }
+func (e Reasoning) When() time.Time     { return e.Time }
+func (e ToolCall) When() time.Time      { return e.Time }
+func (e ToolResult) When() time.Time    { return e.Time }
+func (e Usage) When() time.Time         { return e.Time }
+func (e Lifecycle) When() time.Time     { return e.Time }
+
+// Append appends e to events, joining a Delta text onto the Delta text that
+// ends events. The joined text keeps the time of its first chunk.
+func Append(events []Event, e Event) []Event {
+	if t, ok := e.(AssistantText); ok && t.Delta && len(events) > 0 {
+		if last, ok := events[len(events)-1].(AssistantText); ok && last.Delta {
+			last.Text += t.Text
+			events[len(events)-1] = last
+			return events
+		}
+	}
+	return append(events, e)
+}
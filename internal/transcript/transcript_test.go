@@ -0,0 +1,45 @@
+package transcript
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAppendJoinsDeltas(t *testing.T) {
+	start := time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC)
+	var events []Event
+	for _, e := range []Event{
+		UserPrompt{Text: "hi"},
+		AssistantText{Time: start, Text: "Hel", Delta: true},
+		AssistantText{Time: start.Add(time.Second), Text: "lo", Delta: true},
+		ToolCall{Name: "Bash"},
+		AssistantText{Text: "Bye", Delta: true},
+		AssistantText{Text: "Whole"},
+	} {
+		events = Append(events, e)
+	}
+
+	want := []Event{
+		UserPrompt{Text: "hi"},
+		AssistantText{Time: start, Text: "Hello", Delta: true},
+		ToolCall{Name: "Bash"},
+		AssistantText{Text: "Bye", Delta: true},
+		AssistantText{Text: "Whole"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+}
+
+func TestWhen(t *testing.T) {
+	now := time.Now()
+	for _, e := range []Event{
+		UserPrompt{Time: now}, AssistantText{Time: now}, Reasoning{Time: now},
+		ToolCall{Time: now}, ToolResult{Time: now}, Usage{Time: now}, Lifecycle{Time: now},
+	} {
+		if !e.When().Equal(now) {
+			t.Errorf("%T.When() = %v, want %v", e, e.When(), now)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package transcript
+
+import "github.com/sky-xo/june/internal/usage"
+
+// UsageTally turns a transcript's Usage events into usage records as they
+// are read, so a transcript that's still being written can be tallied
+// incrementally. The zero UsageTally is ready to use.
+type UsageTally struct {
+	model     string // Of the current run
+	records   []usage.Record
+	byMessage map[string]int // Message ID -> index in records
+	total     usage.Tokens   // Last cumulative usage
+}
+
+// Add tallies e if it reports usage or the model of a run.
+func (t *UsageTally) Add(e Event) {
+	switch e := e.(type) {
+	case Lifecycle:
+		if e.Phase == LifecycleStart && e.Model != "" {
+			t.model = e.Model
+		}
+	case Usage:
+		r := usage.Record{Time: e.Time, Model: e.Model, Tokens: e.Tokens}
+		if r.Model == "" {
+			r.Model = t.model
+		}
+		switch {
+		case e.Cumulative:
+			// Running totals: record the increase, and nothing for a repeat
+			r.Tokens = usage.Tokens{
+				Input:      e.Input - t.total.Input,
+				Output:     e.Output - t.total.Output,
+				CacheRead:  e.CacheRead - t.total.CacheRead,
+				CacheWrite: e.CacheWrite - t.total.CacheWrite,
+			}
+			t.total = e.Tokens
+			if r.Input+r.CacheRead <= 0 && r.Output <= 0 {
+				return
+			}
+		case e.MessageID != "":
+			// A later Usage of the same message supersedes the earlier one,
+			// but the record keeps when the message started
+			if i, ok := t.byMessage[e.MessageID]; ok {
+				r.Time = t.records[i].Time
+				t.records[i] = r
+				return
+			}
+			if t.byMessage == nil {
+				t.byMessage = make(map[string]int)
+			}
+			t.byMessage[e.MessageID] = len(t.records)
+		}
+		t.records = append(t.records, r)
+	}
+}
+
+// Records returns the usage tallied so far, one record per response.
+func (t *UsageTally) Records() []usage.Record {
+	return t.records
+}
+
+// ReadUsage returns the usage recorded in the transcript at path, parsing
+// each line with parse.
+func ReadUsage(path string, parse func(line []byte) []Event) ([]usage.Record, error) {
+	var tally UsageTally
+	_, _, err := ReadAppended(path, Position{}, func(line []byte) {
+		for _, e := range parse(line) {
+			tally.Add(e)
+		}
+	})
+	return tally.Records(), err
+}
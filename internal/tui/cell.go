@@ -79,6 +79,29 @@ func (sl StyledLine) WithSelection(startCol, endCol int, bg Color) StyledLine {
 	return result
 }
 
+// WithHighlight returns a copy of the line with the colors of the cells from
+// startCol to endCol (exclusive) replaced by fg and bg. Bold and italic are
+// kept.
+func (sl StyledLine) WithHighlight(startCol, endCol int, fg, bg Color) StyledLine {
+	if startCol < 0 {
+		startCol = 0
+	}
+	if endCol > len(sl) {
+		endCol = len(sl)
+	}
+	if startCol >= endCol {
+		return sl
+	}
+
+	result := make(StyledLine, len(sl))
+	copy(result, sl)
+	for i := startCol; i < endCol; i++ {
+		result[i].Style.FG = fg
+		result[i].Style.BG = bg
+	}
+	return result
+}
+
 // ParseStyledLine parses a string with ANSI escape codes into a StyledLine
 func ParseStyledLine(s string) StyledLine {
 	var result StyledLine
@@ -2,13 +2,17 @@
 package tui
 
 import (
+	"bytes"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/sky-xo/june/internal/agent"
 	"github.com/sky-xo/june/internal/claude"
-	"github.com/sky-xo/june/internal/codex"
+	"github.com/sky-xo/june/internal/config"
 	"github.com/sky-xo/june/internal/db"
-	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/proc"
+	"github.com/sky-xo/june/internal/transcript"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -18,275 +22,104 @@ type (
 	tickMsg       time.Time
 	channelsMsg   []agent.Channel
 	transcriptMsg struct {
-		agentID string
-		entries []claude.Entry
+		agentID  string
+		from, to transcriptTail // Tail before and after the read
+		reset    bool           // Events replace the transcript instead of extending it
+		events   []transcript.Event
+	}
+	errMsg        error
+	killResultMsg struct {
+		name string
+		err  error
+	}
+	exportResultMsg struct {
+		path string
+		err  error
 	}
-	errMsg error
 )
 
-// tickCmd returns a command that ticks every second.
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+// killGrace is how long the TUI waits after SIGTERM before sending SIGKILL.
+const killGrace = 5 * time.Second
+
+// tickCmd returns a command that ticks after interval.
+func tickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
-// scanChannelsCmd scans for channels and their agents.
-// The codexDB parameter is reused across ticks for performance.
-func scanChannelsCmd(claudeProjectsDir, basePath, repoName string, codexDB *db.DB) tea.Cmd {
+// scanChannelsCmd scans for channels and their agents, with their usage,
+// leaving out the agents rules hide. With allProjects it scans every project
+// rather than the repo at basePath. The codexDB and usage cache parameters
+// are reused across ticks for performance.
+func scanChannelsCmd(claudeProjectsDir, basePath, repoName string, allProjects bool, codexDB *db.DB, rules config.Rules, usages *usageCache) tea.Cmd {
 	return func() tea.Msg {
-		channels, err := claude.ScanChannels(claudeProjectsDir, basePath, repoName, codexDB)
+		var (
+			channels []agent.Channel
+			err      error
+		)
+		if allProjects {
+			channels, err = claude.ScanAllChannels(claudeProjectsDir, codexDB, rules)
+		} else {
+			channels, err = claude.ScanChannels(claudeProjectsDir, basePath, repoName, codexDB, rules)
+		}
 		if err != nil {
 			return errMsg(err)
 		}
+		usages.fill(channels)
 		return channelsMsg(channels)
 	}
 }
 
-// loadTranscriptCmd loads a transcript from a file.
-func loadTranscriptCmd(a agent.Agent) tea.Cmd {
+// killAgentCmd stops a spawned agent's process group in the background.
+func killAgentCmd(database *db.DB, name string) tea.Cmd {
 	return func() tea.Msg {
-		var entries []claude.Entry
-		var err error
-
-		switch a.Source {
-		case agent.SourceGemini:
-			// Parse Gemini format and convert to claude.Entry for display
-			var geminiEntries []gemini.TranscriptEntry
-			geminiEntries, _, err = gemini.ReadTranscript(a.TranscriptPath, 0)
-			if err != nil {
-				return errMsg(err)
-			}
-			entries = convertGeminiEntries(geminiEntries)
-		case agent.SourceCodex:
-			// Parse Codex format and convert to claude.Entry for display
-			var codexEntries []codex.TranscriptEntry
-			codexEntries, _, err = codex.ReadTranscript(a.TranscriptPath, 0)
-			if err != nil {
-				return errMsg(err)
-			}
-			entries = convertCodexEntries(codexEntries)
-		default:
-			// Default to Claude format
-			entries, err = claude.ParseTranscript(a.TranscriptPath)
-			if err != nil {
-				return errMsg(err)
-			}
-		}
-
-		return transcriptMsg{
-			agentID: a.ID,
-			entries: entries,
-		}
-	}
-}
-
-// normalizeCodexTool converts Codex tool names/params to Claude equivalents
-func normalizeCodexTool(name string, input map[string]interface{}) (string, map[string]interface{}) {
-	normalized := make(map[string]interface{})
-	for k, v := range input {
-		normalized[k] = v
-	}
-
-	switch name {
-	case "shell_command":
-		return "Bash", normalized
-	case "read_file":
-		if path, ok := normalized["path"]; ok {
-			delete(normalized, "path")
-			normalized["file_path"] = path
+		if database == nil {
+			return killResultMsg{name: name, err: fmt.Errorf("agent database unavailable")}
 		}
-		return "Read", normalized
-	case "write_file":
-		if path, ok := normalized["path"]; ok {
-			delete(normalized, "path")
-			normalized["file_path"] = path
+		a, err := database.GetAgent(name)
+		if err != nil {
+			return killResultMsg{name: name, err: err}
 		}
-		return "Write", normalized
-	case "edit_file":
-		if path, ok := normalized["path"]; ok {
-			delete(normalized, "path")
-			normalized["file_path"] = path
+		sig, err := proc.Stop(a.PID, a.StartedAt, killGrace)
+		if err == nil {
+			err = database.MarkKilled(name, sig)
 		}
-		return "Edit", normalized
-	default:
-		return name, normalized
+		return killResultMsg{name: name, err: err}
 	}
 }
 
-// normalizeGeminiTool converts Gemini tool names/params to Claude equivalents
-func normalizeGeminiTool(name string, input map[string]interface{}) (string, map[string]interface{}) {
-	normalized := make(map[string]interface{})
-	for k, v := range input {
-		normalized[k] = v
-	}
-
-	switch name {
-	case "shell":
-		return "Bash", normalized
-	case "read_file":
-		if path, ok := normalized["path"]; ok {
-			delete(normalized, "path")
-			normalized["file_path"] = path
+// exportAgentCmd exports an agent's transcript as Markdown to a new file in
+// the current directory in the background.
+func exportAgentCmd(a agent.Agent) tea.Cmd {
+	return func() tea.Msg {
+		var buf bytes.Buffer
+		if err := ExportAgent(&buf, a, FormatMarkdown); err != nil {
+			return exportResultMsg{err: err}
 		}
-		return "Read", normalized
-	case "write_file":
-		if path, ok := normalized["path"]; ok {
-			delete(normalized, "path")
-			normalized["file_path"] = path
+		f, err := createExportFile(a)
+		if err != nil {
+			return exportResultMsg{err: err}
 		}
-		return "Write", normalized
-	case "edit_file":
-		if path, ok := normalized["path"]; ok {
-			delete(normalized, "path")
-			normalized["file_path"] = path
+		_, err = f.Write(buf.Bytes())
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
 		}
-		return "Edit", normalized
-	default:
-		return name, normalized
-	}
-}
-
-// convertCodexEntries converts Codex transcript entries to Claude entry format for TUI display.
-func convertCodexEntries(codexEntries []codex.TranscriptEntry) []claude.Entry {
-	entries := make([]claude.Entry, 0, len(codexEntries))
-	for _, ce := range codexEntries {
-		var entry claude.Entry
-		switch ce.Type {
-		case "message":
-			// Codex message -> Claude assistant with text content
-			entry = claude.Entry{
-				Type: "assistant",
-				Message: claude.Message{
-					Role: "assistant",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type": "text",
-							"text": ce.Content,
-						},
-					},
-				},
-			}
-		case "reasoning":
-			// Codex reasoning -> Claude assistant with text (prefixed with [thinking])
-			entry = claude.Entry{
-				Type: "assistant",
-				Message: claude.Message{
-					Role: "assistant",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type": "text",
-							"text": "[thinking] " + ce.Content,
-						},
-					},
-				},
-			}
-		case "tool":
-			// Normalize Codex tool name/params to Claude equivalents for rich formatting
-			normalizedName, normalizedInput := normalizeCodexTool(ce.ToolName, ce.ToolInput)
-			entry = claude.Entry{
-				Type: "assistant",
-				Message: claude.Message{
-					Role: "assistant",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type":  "tool_use",
-							"name":  normalizedName,
-							"input": normalizedInput,
-						},
-					},
-				},
-			}
-		case "tool_output":
-			// Codex tool output -> Claude user with tool_result style content
-			entry = claude.Entry{
-				Type: "user",
-				Message: claude.Message{
-					Role: "user",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type": "tool_result",
-							"text": "  -> " + ce.Content,
-						},
-					},
-				},
-			}
-		default:
-			continue
+		if err != nil {
+			os.Remove(f.Name())
 		}
-		entries = append(entries, entry)
+		return exportResultMsg{path: f.Name(), err: err}
 	}
-	return entries
 }
 
-// convertGeminiEntries converts Gemini transcript entries to Claude entry format for TUI display.
-func convertGeminiEntries(geminiEntries []gemini.TranscriptEntry) []claude.Entry {
-	entries := make([]claude.Entry, 0, len(geminiEntries))
-	for _, ge := range geminiEntries {
-		var entry claude.Entry
-		switch ge.Type {
-		case "user":
-			// Gemini user message -> Claude user
-			entry = claude.Entry{
-				Type: "user",
-				Message: claude.Message{
-					Role: "user",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type": "text",
-							"text": ge.Content,
-						},
-					},
-				},
-			}
-		case "message":
-			// Gemini assistant message -> Claude assistant
-			entry = claude.Entry{
-				Type: "assistant",
-				Message: claude.Message{
-					Role: "assistant",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type": "text",
-							"text": ge.Content,
-						},
-					},
-				},
-			}
-		case "tool":
-			// Normalize Gemini tool name/params to Claude equivalents for rich formatting
-			normalizedName, normalizedInput := normalizeGeminiTool(ge.ToolName, ge.ToolInput)
-			entry = claude.Entry{
-				Type: "assistant",
-				Message: claude.Message{
-					Role: "assistant",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type":  "tool_use",
-							"name":  normalizedName,
-							"input": normalizedInput,
-						},
-					},
-				},
-			}
-		case "tool_output":
-			// Gemini tool output -> Claude user with tool_result
-			entry = claude.Entry{
-				Type: "user",
-				Message: claude.Message{
-					Role: "user",
-					Content: []interface{}{
-						map[string]interface{}{
-							"type": "tool_result",
-							"text": "  -> " + ge.Content,
-						},
-					},
-				},
-			}
-		default:
-			continue
+// loadTranscriptCmd reads the entries appended to an agent's transcript
+// since tail.
+func loadTranscriptCmd(a agent.Agent, tail transcriptTail) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := tailTranscript(a, tail)
+		if err != nil {
+			return errMsg(err)
 		}
-		entries = append(entries, entry)
+		return msg
 	}
-	return entries
 }
@@ -1,287 +1,136 @@
 package tui
 
 import (
+	"path/filepath"
 	"testing"
 
-	"github.com/sky-xo/june/internal/codex"
-	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/transcript"
 )
 
-func TestConvertCodexEntriesToolUseNormalized(t *testing.T) {
-	// Test shell_command -> Bash normalization
-	codexEntries := []codex.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: shell_command]",
-			ToolName:  "shell_command",
-			ToolInput: map[string]interface{}{"command": "go test ./..."},
-		},
-	}
-
-	entries := convertCodexEntries(codexEntries)
-
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
-	}
+// tailToolCall tails a one-line transcript from source and returns the tool
+// call it holds, as the TUI shows it.
+func tailToolCall(t *testing.T, source, line string) transcript.ToolCall {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	appendFile(t, path, line+"\n")
 
-	// Check that ToolName() returns NORMALIZED name "Bash" (not "shell_command")
-	toolName := entries[0].ToolName()
-	if toolName != "Bash" {
-		t.Errorf("ToolName() = %q, want %q (normalized)", toolName, "Bash")
+	msg, err := tailTranscript(agent.Agent{ID: "a1", Source: source, TranscriptPath: path}, transcriptTail{})
+	if err != nil {
+		t.Fatalf("tailTranscript: %v", err)
 	}
-
-	// Check that ToolInput() returns the input map with command
-	toolInput := entries[0].ToolInput()
-	if toolInput == nil {
-		t.Fatal("ToolInput() = nil, want map")
+	if len(msg.events) != 1 {
+		t.Fatalf("len(events) = %d, want 1", len(msg.events))
 	}
-	if cmd, ok := toolInput["command"].(string); !ok || cmd != "go test ./..." {
-		t.Errorf("ToolInput()[command] = %v, want %q", toolInput["command"], "go test ./...")
+	call, ok := msg.events[0].(transcript.ToolCall)
+	if !ok {
+		t.Fatalf("events[0] = %T, want ToolCall", msg.events[0])
 	}
+	return call
 }
 
-func TestConvertCodexEntriesReadFileNormalized(t *testing.T) {
-	// Test read_file -> Read normalization with path -> file_path
-	codexEntries := []codex.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: read_file]",
-			ToolName:  "read_file",
-			ToolInput: map[string]interface{}{"path": "/tmp/main.go"},
-		},
-	}
-
-	entries := convertCodexEntries(codexEntries)
+func TestTailCodexToolUseNormalized(t *testing.T) {
+	// Test shell_command -> Bash normalization
+	call := tailToolCall(t, agent.SourceCodex,
+		`{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{\"command\":\"go test ./...\"}"}}`)
 
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	// Check that Name is the NORMALIZED name "Bash" (not "shell_command")
+	if call.Name != "Bash" {
+		t.Errorf("Name = %q, want %q (normalized)", call.Name, "Bash")
 	}
-
-	// Check normalized name
-	if entries[0].ToolName() != "Read" {
-		t.Errorf("ToolName() = %q, want %q", entries[0].ToolName(), "Read")
+	if cmd, ok := call.Input["command"].(string); !ok || cmd != "go test ./..." {
+		t.Errorf("Input[command] = %v, want %q", call.Input["command"], "go test ./...")
 	}
-
-	// Check normalized parameter key (path -> file_path)
-	toolInput := entries[0].ToolInput()
-	if fp, ok := toolInput["file_path"].(string); !ok || fp != "/tmp/main.go" {
-		t.Errorf("ToolInput()[file_path] = %v, want %q", toolInput["file_path"], "/tmp/main.go")
+	// Normalized calls get Claude's rich summary
+	if summary := claude.ToolSummary(call.Name, call.Input); summary != "Bash: go test ./..." {
+		t.Errorf("ToolSummary() = %q, want %q", summary, "Bash: go test ./...")
 	}
 }
 
-func TestConvertGeminiEntriesToolUseNormalized(t *testing.T) {
+func TestTailCodexReadFileNormalized(t *testing.T) {
 	// Test read_file -> Read normalization with path -> file_path
-	geminiEntries := []gemini.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: read_file]",
-			ToolName:  "read_file",
-			ToolInput: map[string]interface{}{"path": "main.go"},
-		},
-	}
-
-	entries := convertGeminiEntries(geminiEntries)
-
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
-	}
-
-	// Check that ToolName() returns NORMALIZED name "Read" (not "read_file")
-	toolName := entries[0].ToolName()
-	if toolName != "Read" {
-		t.Errorf("ToolName() = %q, want %q (normalized)", toolName, "Read")
-	}
+	call := tailToolCall(t, agent.SourceCodex,
+		`{"type":"response_item","payload":{"type":"function_call","name":"read_file","arguments":"{\"path\":\"/tmp/main.go\"}"}}`)
 
-	// Check normalized parameter key (path -> file_path)
-	toolInput := entries[0].ToolInput()
-	if toolInput == nil {
-		t.Fatal("ToolInput() = nil, want map")
+	if call.Name != "Read" {
+		t.Errorf("Name = %q, want %q", call.Name, "Read")
 	}
-	if fp, ok := toolInput["file_path"].(string); !ok || fp != "main.go" {
-		t.Errorf("ToolInput()[file_path] = %v, want %q", toolInput["file_path"], "main.go")
+	if fp, ok := call.Input["file_path"].(string); !ok || fp != "/tmp/main.go" {
+		t.Errorf("Input[file_path] = %v, want %q", call.Input["file_path"], "/tmp/main.go")
 	}
 }
 
-func TestConvertGeminiEntriesShellNormalized(t *testing.T) {
-	// Test shell -> Bash normalization
-	geminiEntries := []gemini.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: shell]",
-			ToolName:  "shell",
-			ToolInput: map[string]interface{}{"command": "ls -la"},
-		},
-	}
-
-	entries := convertGeminiEntries(geminiEntries)
-
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
-	}
+func TestTailCodexNilToolInput(t *testing.T) {
+	// Malformed arguments leave the input nil; it shouldn't panic
+	call := tailToolCall(t, agent.SourceCodex,
+		`{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{invalid"}}`)
 
-	// Check normalized name
-	if entries[0].ToolName() != "Bash" {
-		t.Errorf("ToolName() = %q, want %q", entries[0].ToolName(), "Bash")
+	// Should still normalize tool name
+	if call.Name != "Bash" {
+		t.Errorf("Name = %q, want %q", call.Name, "Bash")
 	}
-
-	// command key stays the same
-	toolInput := entries[0].ToolInput()
-	if cmd, ok := toolInput["command"].(string); !ok || cmd != "ls -la" {
-		t.Errorf("ToolInput()[command] = %v, want %q", toolInput["command"], "ls -la")
+	// Input should be empty map, not nil
+	if call.Input == nil {
+		t.Error("Input = nil, want empty map")
 	}
 }
 
-func TestConvertCodexEntriesRichFormatting(t *testing.T) {
-	// Codex shell_command should normalize to Bash and produce rich summary
-	codexEntries := []codex.TranscriptEntry{
-		{
-			Type:      "tool",
-			ToolName:  "shell_command",
-			ToolInput: map[string]interface{}{
-				"command": "go test ./...",
-			},
-		},
-	}
-
-	entries := convertCodexEntries(codexEntries)
+func TestTailGeminiReadFileNormalized(t *testing.T) {
+	// Test read_file -> Read normalization with path -> file_path
+	call := tailToolCall(t, agent.SourceGemini,
+		`{"type":"tool_use","tool_name":"read_file","tool_id":"t1","parameters":{"path":"/Users/test/code/project/main.go"}}`)
 
-	// After normalization: Bash with command
-	// ToolSummary should return "Bash: go test ./..."
-	summary := entries[0].ToolSummary()
-	if summary != "Bash: go test ./..." {
-		t.Errorf("ToolSummary() = %q, want %q", summary, "Bash: go test ./...")
+	if call.Name != "Read" {
+		t.Errorf("Name = %q, want %q (normalized)", call.Name, "Read")
 	}
-}
-
-func TestConvertGeminiEntriesRichFormatting(t *testing.T) {
-	// Gemini read_file should normalize to Read with file_path
-	geminiEntries := []gemini.TranscriptEntry{
-		{
-			Type:      "tool",
-			ToolName:  "read_file",
-			ToolInput: map[string]interface{}{
-				"path": "/Users/test/code/project/main.go",
-			},
-		},
+	if fp, ok := call.Input["file_path"].(string); !ok || fp != "/Users/test/code/project/main.go" {
+		t.Errorf("Input[file_path] = %v, want the path", call.Input["file_path"])
 	}
-
-	entries := convertGeminiEntries(geminiEntries)
-
-	// After normalization: Read with file_path
 	// ToolSummary should include shortened path
-	summary := entries[0].ToolSummary()
-	if summary == "" || summary == "Read" {
+	if summary := claude.ToolSummary(call.Name, call.Input); summary == "" || summary == "Read" {
 		t.Errorf("ToolSummary() = %q, want path info like 'Read: project/main.go'", summary)
 	}
-	if entries[0].ToolName() != "Read" {
-		t.Errorf("ToolName() = %q, want %q", entries[0].ToolName(), "Read")
-	}
-}
-
-func TestConvertCodexEntriesNilToolInput(t *testing.T) {
-	// Verify that nil ToolInput doesn't cause panic
-	codexEntries := []codex.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: shell_command]",
-			ToolName:  "shell_command",
-			ToolInput: nil, // nil input (can happen with malformed JSON)
-		},
-	}
-
-	// Should not panic
-	entries := convertCodexEntries(codexEntries)
-
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
-	}
-
-	// Should still normalize tool name
-	if entries[0].ToolName() != "Bash" {
-		t.Errorf("ToolName() = %q, want %q", entries[0].ToolName(), "Bash")
-	}
-
-	// ToolInput should be empty map, not nil
-	toolInput := entries[0].ToolInput()
-	if toolInput == nil {
-		t.Error("ToolInput() = nil, want empty map")
-	}
 }
 
-func TestConvertCodexEntriesEmptyToolInput(t *testing.T) {
-	// Verify that empty ToolInput works correctly
-	codexEntries := []codex.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: read_file]",
-			ToolName:  "read_file",
-			ToolInput: map[string]interface{}{}, // empty map
-		},
-	}
-
-	// Should not panic
-	entries := convertCodexEntries(codexEntries)
+func TestTailGeminiShellNormalized(t *testing.T) {
+	// Test shell -> Bash normalization
+	call := tailToolCall(t, agent.SourceGemini,
+		`{"type":"tool_use","tool_name":"shell","tool_id":"t1","parameters":{"command":"ls -la"}}`)
 
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	if call.Name != "Bash" {
+		t.Errorf("Name = %q, want %q", call.Name, "Bash")
 	}
-
-	// Should still normalize tool name
-	if entries[0].ToolName() != "Read" {
-		t.Errorf("ToolName() = %q, want %q", entries[0].ToolName(), "Read")
+	// command key stays the same
+	if cmd, ok := call.Input["command"].(string); !ok || cmd != "ls -la" {
+		t.Errorf("Input[command] = %v, want %q", call.Input["command"], "ls -la")
 	}
 }
 
-func TestConvertGeminiEntriesNilToolInput(t *testing.T) {
-	// Verify that nil ToolInput doesn't cause panic
-	geminiEntries := []gemini.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: shell]",
-			ToolName:  "shell",
-			ToolInput: nil, // nil input (can happen with malformed JSON)
-		},
-	}
-
-	// Should not panic
-	entries := convertGeminiEntries(geminiEntries)
+func TestTailGeminiNilToolInput(t *testing.T) {
+	// A call without parameters shouldn't panic
+	call := tailToolCall(t, agent.SourceGemini, `{"type":"tool_use","tool_name":"shell","tool_id":"t1"}`)
 
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	if call.Name != "Bash" {
+		t.Errorf("Name = %q, want %q", call.Name, "Bash")
 	}
-
-	// Should still normalize tool name
-	if entries[0].ToolName() != "Bash" {
-		t.Errorf("ToolName() = %q, want %q", entries[0].ToolName(), "Bash")
-	}
-
-	// ToolInput should be empty map, not nil
-	toolInput := entries[0].ToolInput()
-	if toolInput == nil {
-		t.Error("ToolInput() = nil, want empty map")
+	if call.Input == nil {
+		t.Error("Input = nil, want empty map")
 	}
 }
 
-func TestConvertGeminiEntriesEmptyToolInput(t *testing.T) {
-	// Verify that empty ToolInput works correctly
-	geminiEntries := []gemini.TranscriptEntry{
-		{
-			Type:      "tool",
-			Content:   "[tool: read_file]",
-			ToolName:  "read_file",
-			ToolInput: map[string]interface{}{}, // empty map
-		},
-	}
+func TestTailCodexToolOutputPairsWithCall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	appendFile(t, path, `{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{\"command\":\"false\"}","call_id":"call_1"}}
+{"type":"response_item","payload":{"type":"function_call_output","call_id":"call_1","output":"exit status 1"}}
+`)
 
-	// Should not panic
-	entries := convertGeminiEntries(geminiEntries)
-
-	if len(entries) != 1 {
-		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	msg, err := tailTranscript(agent.Agent{ID: "a1", Source: agent.SourceCodex, TranscriptPath: path}, transcriptTail{})
+	if err != nil {
+		t.Fatalf("tailTranscript: %v", err)
 	}
-
-	// Should still normalize tool name
-	if entries[0].ToolName() != "Read" {
-		t.Errorf("ToolName() = %q, want %q", entries[0].ToolName(), "Read")
+	results := matchToolResults(msg.events)
+	if r, ok := results[0]; !ok || r.ID != "call_1" || r.Output != "exit status 1" {
+		t.Errorf("matchToolResults()[0] = %+v, want the output of call_1", r)
 	}
 }
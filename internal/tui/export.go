@@ -0,0 +1,642 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Export formats, for june export.
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+// ExportFormats lists the formats ExportAgent writes.
+var ExportFormats = []string{FormatMarkdown, FormatHTML, FormatJSON}
+
+// exportHunkContext is how many unchanged lines surround each change in
+// exported diffs.
+const exportHunkContext = 3
+
+// ExportAgent writes a's transcript to w as Markdown, a self-contained HTML
+// page or JSON. Tool calls use Claude's tool names, as in the TUI.
+func ExportAgent(w io.Writer, a agent.Agent, format string) error {
+	events, err := readTranscript(a)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatMarkdown:
+		_, err = io.WriteString(w, exportMarkdown(a, events))
+	case FormatHTML:
+		_, err = io.WriteString(w, exportHTML(a, events))
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(exportJSON(a, events))
+	default:
+		err = fmt.Errorf("unknown format %q (use %s)", format, strings.Join(ExportFormats, ", "))
+	}
+	return err
+}
+
+// exportFileName returns the file the e key exports a to: its name, kept to
+// characters that are safe in file names, with a .md extension.
+func exportFileName(a agent.Agent) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, a.DisplayName())
+	name = strings.Trim(name, "-.")
+	if len(name) > 60 || name == "" {
+		name = a.ID
+	}
+	return name + ".md"
+}
+
+// createExportFile creates the file the e key exports a to in the current
+// directory. If exportFileName is taken it numbers the name (name-2.md, ...)
+// rather than overwrite the file: a Claude subagent described as "README"
+// mustn't replace README.md.
+func createExportFile(a agent.Agent) (*os.File, error) {
+	base := strings.TrimSuffix(exportFileName(a), ".md")
+	for i := 1; i <= 100; i++ {
+		path := base + ".md"
+		if i > 1 {
+			path = fmt.Sprintf("%s-%d.md", base, i)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, fmt.Errorf("%s.md and its numbered copies already exist", base)
+}
+
+// readTranscript reads all of a's transcript as the TUI shows it: tool calls
+// normalized and streamed chunks joined.
+func readTranscript(a agent.Agent) ([]transcript.Event, error) {
+	msg, err := tailTranscript(a, transcriptTail{})
+	if err != nil {
+		return nil, err
+	}
+	var events []transcript.Event
+	for _, e := range msg.events {
+		events = transcript.Append(events, e)
+	}
+	return events, nil
+}
+
+// exportSchemaVersion is the version of the JSON export schema. Fields may
+// be added without changing it; it changes if a field is removed or changes
+// meaning.
+const exportSchemaVersion = 1
+
+// exportDocument is the JSON export of an agent. The field names are part
+// of june's scripting interface, so keep them stable and update the README
+// when adding fields.
+type exportDocument struct {
+	Version int           `json:"version"`
+	Agent   exportAgent   `json:"agent"`
+	Events  []exportEvent `json:"events"`
+}
+
+// exportAgent describes the exported agent. Empty fields are omitted.
+type exportAgent struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Source   string `json:"source"` // claude, codex or gemini
+	RepoPath string `json:"repo_path,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Status   string `json:"status,omitempty"`  // Spawned agents only
+	Task     string `json:"task,omitempty"`    // Spawned agents only
+	Options  string `json:"options,omitempty"` // Spawned agents only
+}
+
+// exportEvent is one transcript event. Type says which of the other fields
+// apply; fields that don't apply or are empty are omitted.
+type exportEvent struct {
+	// user, assistant, reasoning, tool_call, tool_result, usage or lifecycle
+	Type string     `json:"type"`
+	Time *time.Time `json:"time,omitempty"`
+
+	Text string `json:"text,omitempty"` // user, assistant, reasoning
+
+	ID      string                 `json:"id,omitempty"`       // tool_call, tool_result: pairs a result with its call
+	Name    string                 `json:"name,omitempty"`     // tool_call
+	Input   map[string]interface{} `json:"input,omitempty"`    // tool_call
+	Output  string                 `json:"output,omitempty"`   // tool_result
+	IsError bool                   `json:"is_error,omitempty"` // tool_result, lifecycle
+
+	Model      string        `json:"model,omitempty"`      // usage
+	Tokens     *usage.Tokens `json:"tokens,omitempty"`     // usage
+	MessageID  string        `json:"message_id,omitempty"` // usage: a later usage with the same ID supersedes this one
+	Cumulative bool          `json:"cumulative,omitempty"` // usage: running totals for the session
+
+	Phase  string `json:"phase,omitempty"`  // lifecycle: start or end
+	Detail string `json:"detail,omitempty"` // lifecycle
+}
+
+// exportJSON builds the JSON export of a's transcript.
+func exportJSON(a agent.Agent, events []transcript.Event) exportDocument {
+	doc := exportDocument{
+		Version: exportSchemaVersion,
+		Agent: exportAgent{
+			ID: a.ID, Name: a.Name, Source: a.Source, RepoPath: a.RepoPath, Branch: a.Branch,
+			Status: a.Status, Task: a.Task, Options: a.Options,
+		},
+		Events: []exportEvent{},
+	}
+	if doc.Agent.Source == "" {
+		doc.Agent.Source = agent.SourceClaude
+	}
+
+	for _, e := range events {
+		var out exportEvent
+		switch e := e.(type) {
+		case transcript.UserPrompt:
+			out = exportEvent{Type: "user", Text: e.Text}
+		case transcript.AssistantText:
+			out = exportEvent{Type: "assistant", Text: e.Text}
+		case transcript.Reasoning:
+			out = exportEvent{Type: "reasoning", Text: e.Text}
+		case transcript.ToolCall:
+			out = exportEvent{Type: "tool_call", ID: e.ID, Name: e.Name, Input: e.Input}
+		case transcript.ToolResult:
+			out = exportEvent{Type: "tool_result", ID: e.ID, Output: e.Output, IsError: e.IsError}
+		case transcript.Usage:
+			tokens := e.Tokens
+			out = exportEvent{Type: "usage", Model: e.Model, Tokens: &tokens, MessageID: e.MessageID, Cumulative: e.Cumulative}
+		case transcript.Lifecycle:
+			out = exportEvent{Type: "lifecycle", Phase: e.Phase, Detail: e.Detail, IsError: e.IsError}
+		default:
+			continue
+		}
+		if t := e.When(); !t.IsZero() {
+			out.Time = &t
+		}
+		doc.Events = append(doc.Events, out)
+	}
+	return doc
+}
+
+// exportMarkdown renders a's transcript as Markdown: replies as written,
+// commands and file contents in fenced code blocks, edits as diffs, and
+// reasoning and tool output in collapsible sections.
+func exportMarkdown(a agent.Agent, events []transcript.Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", a.DisplayName())
+	for _, line := range exportDetails(a) {
+		fmt.Fprintf(&b, "- %s\n", line)
+	}
+	b.WriteString("\n")
+
+	results := matchToolResults(events)
+	for i, e := range events {
+		switch e := e.(type) {
+		case transcript.UserPrompt:
+			text := strings.TrimSpace(e.Text)
+			if text == "" {
+				continue
+			}
+			b.WriteString("**User:**\n\n")
+			for _, line := range strings.Split(text, "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+			b.WriteString("\n")
+		case transcript.AssistantText:
+			if text := strings.TrimSpace(e.Text); text != "" {
+				b.WriteString(text + "\n\n")
+			}
+		case transcript.Reasoning:
+			if text := strings.TrimSpace(e.Text); text != "" {
+				fmt.Fprintf(&b, "<details>\n<summary>Thinking</summary>\n\n%s\n\n</details>\n\n", text)
+			}
+		case transcript.ToolCall:
+			if e.Name == "" {
+				continue
+			}
+			writeMarkdownTool(&b, e)
+			if r, ok := results[i]; ok {
+				writeMarkdownToolResult(&b, r)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// writeMarkdownTool writes a tool call: its name and detail, then the full
+// command, diff, file content or todo list for the tools that have one.
+func writeMarkdownTool(b *strings.Builder, call transcript.ToolCall) {
+	name, detail := toolTitle(call)
+	if detail != "" {
+		fmt.Fprintf(b, "**%s** %s\n\n", name, markdownCode(detail))
+	} else {
+		fmt.Fprintf(b, "**%s**\n\n", name)
+	}
+
+	input := call.Input
+	switch call.Name {
+	case "Bash":
+		if cmd, _ := input["command"].(string); cmd != "" {
+			writeFenced(b, "sh", cmd)
+		}
+	case "Edit":
+		oldStr, _ := input["old_string"].(string)
+		newStr, _ := input["new_string"].(string)
+		if diff := unifiedDiff(oldStr, newStr); diff != "" {
+			writeFenced(b, "diff", diff)
+		} else {
+			fmt.Fprintf(b, "_%s_\n\n", diffSummary(oldStr, newStr))
+		}
+	case "Write":
+		filePath, _ := input["file_path"].(string)
+		content, _ := input["content"].(string)
+		writeFenced(b, fenceLanguage(filePath), strings.TrimRight(content, "\n"))
+	case "TodoWrite":
+		todos, _ := input["todos"].([]interface{})
+		for _, todo := range todos {
+			todoMap, _ := todo.(map[string]interface{})
+			content, _ := todoMap["content"].(string)
+			switch status, _ := todoMap["status"].(string); status {
+			case "completed":
+				fmt.Fprintf(b, "- [x] %s\n", content)
+			case "in_progress":
+				fmt.Fprintf(b, "- [ ] %s (in progress)\n", content)
+			default:
+				fmt.Fprintf(b, "- [ ] %s\n", content)
+			}
+		}
+		if len(todos) > 0 {
+			b.WriteString("\n")
+		}
+	}
+}
+
+// writeMarkdownToolResult writes a tool's output in a collapsed section.
+func writeMarkdownToolResult(b *strings.Builder, r transcript.ToolResult) {
+	content := strings.TrimRight(ansi.Strip(r.Output), " \t\r\n")
+	if content == "" {
+		b.WriteString("_(no output)_\n\n")
+		return
+	}
+	fmt.Fprintf(b, "<details>\n<summary>%s</summary>\n\n", resultSummary(r, content))
+	writeFenced(b, "", content)
+	b.WriteString("</details>\n\n")
+}
+
+// writeFenced writes content as a fenced code block, with a fence longer
+// than any run of backticks in it.
+func writeFenced(b *strings.Builder, lang, content string) {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n\n", fence, lang, content, fence)
+}
+
+// markdownCode formats text as inline code, one line long.
+func markdownCode(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+// fenceLanguage returns the code block language for a file, or "" if it
+// isn't known.
+func fenceLanguage(filePath string) string {
+	lexer := lexerFor(filePath)
+	if lexer == nil {
+		return ""
+	}
+	if aliases := lexer.Config().Aliases; len(aliases) > 0 {
+		return aliases[0]
+	}
+	return strings.ToLower(lexer.Config().Name)
+}
+
+// unifiedDiff renders an edit as unified diff hunks. Line numbers count
+// from the start of the replaced text, not the file.
+func unifiedDiff(oldStr, newStr string) string {
+	diff := computeDiff(strings.Split(oldStr, "\n"), strings.Split(newStr, "\n"))
+	var b strings.Builder
+	for _, hunk := range extractHunks(diff, exportHunkContext, 2*exportHunkContext) {
+		b.WriteString(hunkHeader(hunk) + "\n")
+		for _, d := range hunk.Lines {
+			switch d.Op {
+			case DiffDelete:
+				b.WriteString("-" + d.Content + "\n")
+			case DiffInsert:
+				b.WriteString("+" + d.Content + "\n")
+			default:
+				b.WriteString(" " + d.Content + "\n")
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// hunkHeader returns a hunk's "@@ -start,count +start,count @@" line.
+func hunkHeader(h Hunk) string {
+	var oldStart, newStart, oldCount, newCount int
+	for _, d := range h.Lines {
+		if d.Op != DiffInsert {
+			if oldStart == 0 {
+				oldStart = d.OldLineNum
+			}
+			oldCount++
+		}
+		if d.Op != DiffDelete {
+			if newStart == 0 {
+				newStart = d.NewLineNum
+			}
+			newCount++
+		}
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount)
+}
+
+// diffSummary is formatDiffSummary without the TUI's tree glyph.
+func diffSummary(oldStr, newStr string) string {
+	return strings.TrimPrefix(formatDiffSummary(oldStr, newStr), "└ ")
+}
+
+// resultSummary labels a tool's output by outcome and length.
+func resultSummary(r transcript.ToolResult, content string) string {
+	label := "Output"
+	if r.IsError {
+		label = "Error"
+	}
+	n := strings.Count(content, "\n") + 1
+	if n == 1 {
+		return label + " (1 line)"
+	}
+	return fmt.Sprintf("%s (%d lines)", label, n)
+}
+
+// toolTitle returns a tool call's name and a one-line detail: a Bash
+// command's description or command, the path of a file tool, or the detail
+// of claude.ToolSummary.
+func toolTitle(call transcript.ToolCall) (name, detail string) {
+	input := call.Input
+	switch call.Name {
+	case "Bash":
+		if desc, _ := input["description"].(string); desc != "" {
+			return call.Name, desc
+		}
+		cmd, _ := input["command"].(string)
+		if first, _, multiline := strings.Cut(cmd, "\n"); multiline {
+			cmd = first + "..."
+		}
+		return call.Name, cmd
+	case "Edit", "Write":
+		filePath, _ := input["file_path"].(string)
+		return call.Name, shortenPath(filePath)
+	case "TodoWrite":
+		return call.Name, ""
+	}
+	summary := claude.ToolSummary(call.Name, call.Input)
+	if name, detail, ok := strings.Cut(summary, ": "); ok {
+		return name, detail
+	}
+	return summary, ""
+}
+
+// exportDetails returns the lines describing a in an export's header.
+func exportDetails(a agent.Agent) []string {
+	source := a.Source
+	if source == "" {
+		source = agent.SourceClaude
+	}
+	lines := []string{"Agent: " + source}
+	if a.RepoPath != "" {
+		where := a.RepoPath
+		if a.Branch != "" {
+			where += " (" + a.Branch + ")"
+		}
+		lines = append(lines, "Repository: "+where)
+	}
+	if a.Options != "" {
+		lines = append(lines, "Options: "+a.Options)
+	}
+	if a.Status != "" {
+		lines = append(lines, "Status: "+a.Status)
+	}
+	if !a.LastActivity.IsZero() {
+		lines = append(lines, "Last activity: "+a.LastActivity.Format("2006-01-02 15:04"))
+	}
+	return lines
+}
+
+// exportCSS styles exported HTML pages like the TUI's dark theme.
+const exportCSS = `
+body { margin: 0; background: #272822; color: #f8f8f2; font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; }
+main, header { max-width: 960px; margin: 0 auto; padding: 0 24px; }
+header { padding-top: 24px; border-bottom: 1px solid #49483e; }
+header ul { list-style: none; padding: 0; color: #908f84; }
+pre, code, .diff { font: 13px/1.45 ui-monospace, SFMono-Regular, Menlo, monospace; }
+pre { margin: 8px 0; padding: 8px 12px; background: #1e1f1c; border-radius: 4px; overflow-x: auto; }
+.user { margin: 20px 0; padding: 4px 12px; border-left: 4px solid #66d9ef; color: #66d9ef; font-weight: bold; white-space: pre-wrap; }
+.assistant { margin: 12px 0; }
+.assistant pre code { font-size: inherit; }
+details.reasoning { margin: 12px 0; color: #908f84; font-style: italic; white-space: pre-wrap; }
+.tool { margin: 12px 0; }
+.tool-name { color: #c8fb9e; font-weight: bold; }
+.tool-detail { color: #908f84; }
+.todos { list-style: none; padding-left: 16px; }
+.todos .completed { color: #908f84; }
+.todos .in_progress { color: #a6e22e; }
+details.result summary { color: #908f84; cursor: pointer; }
+details.result.error summary, details.result.error pre { color: #f92672; }
+.diff { width: 100%; margin: 8px 0; border-collapse: collapse; background: #1e1f1c; }
+.diff td { padding: 0 8px; white-space: pre-wrap; vertical-align: top; }
+.diff .num { width: 1%; color: #75715e; text-align: right; user-select: none; }
+.diff .del { background: #3d1b1b; }
+.diff .ins { background: #1b3d1b; }
+.diff .gap td { color: #75715e; }
+`
+
+// exportHTML renders a's transcript as a self-contained HTML page, with
+// code highlighted and edits shown as diffs like in the TUI.
+func exportHTML(a agent.Agent, events []transcript.Event) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(a.DisplayName()), exportCSS)
+
+	b.WriteString("<header>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<ul>\n", html.EscapeString(a.DisplayName()))
+	for _, line := range exportDetails(a) {
+		fmt.Fprintf(&b, "<li>%s</li>\n", html.EscapeString(line))
+	}
+	b.WriteString("</ul>\n</header>\n<main>\n")
+
+	results := matchToolResults(events)
+	for i, e := range events {
+		switch e := e.(type) {
+		case transcript.UserPrompt:
+			if text := strings.TrimSpace(e.Text); text != "" {
+				fmt.Fprintf(&b, "<div class=\"user\">%s</div>\n", html.EscapeString(text))
+			}
+		case transcript.AssistantText:
+			if text := strings.TrimSpace(e.Text); text != "" {
+				fmt.Fprintf(&b, "<div class=\"assistant\">%s</div>\n", markdownToHTML(text))
+			}
+		case transcript.Reasoning:
+			if text := strings.TrimSpace(e.Text); text != "" {
+				fmt.Fprintf(&b, "<details class=\"reasoning\"><summary>Thinking…</summary>%s</details>\n", html.EscapeString(text))
+			}
+		case transcript.ToolCall:
+			if e.Name == "" {
+				continue
+			}
+			b.WriteString("<div class=\"tool\">\n")
+			writeHTMLTool(&b, e)
+			if r, ok := results[i]; ok {
+				writeHTMLToolResult(&b, r)
+			}
+			b.WriteString("</div>\n")
+		}
+	}
+	b.WriteString("</main>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// writeHTMLTool writes a tool call: its name and detail, then the full
+// command, diff, file content or todo list for the tools that have one.
+func writeHTMLTool(b *strings.Builder, call transcript.ToolCall) {
+	name, detail := toolTitle(call)
+	fmt.Fprintf(b, "<div><span class=\"tool-name\">%s</span>", html.EscapeString(name))
+	if detail != "" {
+		fmt.Fprintf(b, " <span class=\"tool-detail\">%s</span>", html.EscapeString(detail))
+	}
+	b.WriteString("</div>\n")
+
+	input := call.Input
+	switch call.Name {
+	case "Bash":
+		if cmd, _ := input["command"].(string); cmd != "" {
+			fmt.Fprintf(b, "<pre>%s</pre>\n", syntaxHighlightHTML(cmd, "command.sh"))
+		}
+	case "Edit":
+		filePath, _ := input["file_path"].(string)
+		oldStr, _ := input["old_string"].(string)
+		newStr, _ := input["new_string"].(string)
+		writeHTMLDiff(b, oldStr, newStr, filePath)
+	case "Write":
+		filePath, _ := input["file_path"].(string)
+		content, _ := input["content"].(string)
+		fmt.Fprintf(b, "<pre>%s</pre>\n", syntaxHighlightHTML(strings.TrimRight(content, "\n"), filePath))
+	case "TodoWrite":
+		todos, _ := input["todos"].([]interface{})
+		b.WriteString("<ul class=\"todos\">\n")
+		for _, todo := range todos {
+			todoMap, _ := todo.(map[string]interface{})
+			content, _ := todoMap["content"].(string)
+			status, _ := todoMap["status"].(string)
+			indicator := "☐" // empty box
+			switch status {
+			case "completed":
+				indicator = "✓" // checkmark
+			case "in_progress":
+				indicator = "◐" // half circle
+			}
+			fmt.Fprintf(b, "<li class=\"%s\">%s %s</li>\n", html.EscapeString(status), indicator, html.EscapeString(content))
+		}
+		b.WriteString("</ul>\n")
+	}
+}
+
+// writeHTMLDiff writes an edit as a table of diff hunks with line numbers,
+// highlighted by the edited file's language.
+func writeHTMLDiff(b *strings.Builder, oldStr, newStr, filePath string) {
+	diff := computeDiff(strings.Split(oldStr, "\n"), strings.Split(newStr, "\n"))
+	hunks := extractHunks(diff, exportHunkContext, 2*exportHunkContext)
+	if len(hunks) == 0 {
+		fmt.Fprintf(b, "<div class=\"tool-detail\">%s</div>\n", html.EscapeString(diffSummary(oldStr, newStr)))
+		return
+	}
+
+	b.WriteString("<table class=\"diff\">\n")
+	for i, hunk := range hunks {
+		if i > 0 {
+			b.WriteString("<tr class=\"gap\"><td class=\"num\"></td><td class=\"num\"></td><td>…</td></tr>\n")
+		}
+		for _, d := range hunk.Lines {
+			class, sign := "", " "
+			switch d.Op {
+			case DiffDelete:
+				class, sign = "del", "-"
+			case DiffInsert:
+				class, sign = "ins", "+"
+			}
+			fmt.Fprintf(b, "<tr class=\"%s\"><td class=\"num\">%s</td><td class=\"num\">%s</td><td>%s %s</td></tr>\n",
+				class, lineNum(d.OldLineNum), lineNum(d.NewLineNum), sign, syntaxHighlightHTML(d.Content, filePath))
+		}
+	}
+	b.WriteString("</table>\n")
+}
+
+// lineNum formats a diff line number, blank for 0.
+func lineNum(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+// writeHTMLToolResult writes a tool's output in a collapsed section; errors
+// start expanded.
+func writeHTMLToolResult(b *strings.Builder, r transcript.ToolResult) {
+	content := strings.TrimRight(ansi.Strip(r.Output), " \t\r\n")
+	if content == "" {
+		b.WriteString("<div class=\"tool-detail\">(no output)</div>\n")
+		return
+	}
+	class, open := "result", ""
+	if r.IsError {
+		class, open = "result error", " open"
+	}
+	fmt.Fprintf(b, "<details class=\"%s\"%s><summary>%s</summary><pre>%s</pre></details>\n",
+		class, open, html.EscapeString(resultSummary(r, content)), html.EscapeString(content))
+}
+
+// markdownToHTML renders an agent's Markdown reply as HTML. Raw HTML in the
+// reply is escaped, not passed through.
+func markdownToHTML(text string) string {
+	var buf bytes.Buffer
+	if err := exportMarkdownRenderer.Convert([]byte(text), &buf); err != nil {
+		return "<p>" + html.EscapeString(text) + "</p>"
+	}
+	return buf.String()
+}
+
+// exportMarkdownRenderer renders replies with GitHub's Markdown extensions.
+var exportMarkdownRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
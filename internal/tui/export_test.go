@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/transcript"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportTestEvents is a short session: a prompt, an edit and a failed test
+// run.
+func exportTestEvents() []transcript.Event {
+	return []transcript.Event{
+		transcript.UserPrompt{Text: "Fix the greeting"},
+		transcript.Reasoning{Text: "The typo is in main.go"},
+		transcript.AssistantText{Text: "Fixing the **typo**:"},
+		transcript.ToolCall{ID: "t1", Name: "Edit", Input: map[string]interface{}{
+			"file_path":  "/code/app/main.go",
+			"old_string": "func main() {\n\tfmt.Println(\"helo\")\n}",
+			"new_string": "func main() {\n\tfmt.Println(\"hello\")\n}",
+		}},
+		transcript.ToolResult{ID: "t1", Output: "ok"},
+		transcript.ToolCall{ID: "t2", Name: "Bash", Input: map[string]interface{}{"command": "go test ./..."}},
+		transcript.ToolResult{ID: "t2", Output: "FAIL: TestGreeting\nexit status 1", IsError: true},
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	a := agent.Agent{ID: "abc", Name: "fix-9c4f", Source: agent.SourceCodex, RepoPath: "/code/app", Branch: "main"}
+	got := exportMarkdown(a, exportTestEvents())
+
+	for _, want := range []string{
+		"# fix-9c4f\n",
+		"- Agent: codex\n- Repository: /code/app (main)\n",
+		"**User:**\n\n> Fix the greeting\n",
+		"<summary>Thinking</summary>\n\nThe typo is in main.go",
+		"Fixing the **typo**:\n",
+		"**Edit** `app/main.go`\n\n```diff\n@@ -1,3 +1,3 @@\n func main() {\n-\tfmt.Println(\"helo\")\n+\tfmt.Println(\"hello\")\n }\n```\n",
+		"**Bash** `go test ./...`\n\n```sh\ngo test ./...\n```\n",
+		"<summary>Error (2 lines)</summary>\n\n```\nFAIL: TestGreeting\nexit status 1\n```\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteFenced_LongerFenceForBackticks(t *testing.T) {
+	var b strings.Builder
+	writeFenced(&b, "md", "```go\nx := 1\n```")
+	if got, want := b.String(), "````md\n```go\nx := 1\n```\n````\n\n"; got != want {
+		t.Errorf("writeFenced = %q, want %q", got, want)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	a := agent.Agent{ID: "abc", Name: "fix-<9c4f>", Source: agent.SourceCodex}
+	events := append(exportTestEvents(), transcript.AssistantText{Text: "<script>alert(1)</script>"})
+	got := exportHTML(a, events)
+
+	if !strings.HasPrefix(got, "<!DOCTYPE html>") || !strings.Contains(got, "<style>") {
+		t.Errorf("page should be a complete, self-contained document:\n%s", got)
+	}
+	if strings.Contains(got, "<link") || strings.Contains(got, "<script>") {
+		t.Errorf("page should load nothing and escape raw HTML in replies:\n%s", got)
+	}
+	for _, want := range []string{
+		"<h1>fix-&lt;9c4f&gt;</h1>",
+		"<strong>typo</strong>",
+		`<tr class="del"><td class="num">2</td><td class="num"></td>`,
+		`<tr class="ins"><td class="num"></td><td class="num">2</td>`,
+		`<span style="color:#66d9ef">func</span>`, // Highlighted like the TUI
+		`<details class="result error" open>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("html missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	a := agent.Agent{ID: "abc", Name: "fix-9c4f", Source: agent.SourceCodex}
+	events := append(exportTestEvents(), transcript.Lifecycle{Phase: transcript.LifecycleEnd})
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(exportJSON(a, events)); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Version int `json:"version"`
+		Agent   struct {
+			ID     string `json:"id"`
+			Source string `json:"source"`
+		} `json:"agent"`
+		Events []map[string]interface{} `json:"events"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Version != 1 || doc.Agent.ID != "abc" || doc.Agent.Source != "codex" {
+		t.Errorf("header = %+v, want version 1 for codex agent abc", doc)
+	}
+	var types []string
+	for _, e := range doc.Events {
+		types = append(types, e["type"].(string))
+	}
+	want := "user reasoning assistant tool_call tool_result tool_call tool_result lifecycle"
+	if got := strings.Join(types, " "); got != want {
+		t.Errorf("event types = %q, want %q", got, want)
+	}
+	if call := doc.Events[5]; call["id"] != "t2" || call["name"] != "Bash" || call["input"].(map[string]interface{})["command"] != "go test ./..." {
+		t.Errorf("tool_call = %v", call)
+	}
+	if result := doc.Events[6]; result["id"] != "t2" || result["is_error"] != true {
+		t.Errorf("tool_result = %v", result)
+	}
+	if _, ok := doc.Events[0]["time"]; ok {
+		t.Errorf("events without a time should omit it: %v", doc.Events[0])
+	}
+}
+
+func TestExportAgent_ReadsTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "codex.jsonl")
+	appendFile(t, path, `{"type":"response_item","payload":{"type":"function_call","name":"shell_command","arguments":"{\"command\":\"ls\"}"}}
+`)
+	a := agent.Agent{ID: "abc", Source: agent.SourceCodex, TranscriptPath: path}
+
+	var buf bytes.Buffer
+	if err := ExportAgent(&buf, a, FormatMarkdown); err != nil {
+		t.Fatalf("ExportAgent: %v", err)
+	}
+	// Tool calls use Claude's names, as in the TUI
+	if !strings.Contains(buf.String(), "**Bash** `ls`") {
+		t.Errorf("export = %q, want the normalized Bash call", buf.String())
+	}
+	if err := ExportAgent(&buf, a, "pdf"); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
+
+func TestExportFileName(t *testing.T) {
+	tests := []struct {
+		agent agent.Agent
+		want  string
+	}{
+		{agent.Agent{ID: "01J", Name: "fix-9c4f"}, "fix-9c4f.md"},
+		{agent.Agent{ID: "a1b2", Name: "Fix auth: tokens/expiry"}, "Fix-auth--tokens-expiry.md"},
+		{agent.Agent{ID: "a1b2"}, "a1b2.md"},
+	}
+	for _, tt := range tests {
+		if got := exportFileName(tt.agent); got != tt.want {
+			t.Errorf("exportFileName(%+v) = %q, want %q", tt.agent, got, tt.want)
+		}
+	}
+}
+
+func TestUpdate_EExportsSelectedAgent(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, "agent.jsonl")
+	appendFile(t, path, claudeUserLine)
+
+	agents := createTestAgents(1)
+	agents[0].Name = "fix-9c4f"
+	agents[0].TranscriptPath = path
+	m := createModelWithAgents(agents, 80, 40)
+	m.selectedIdx = 1 // Index 0 is the channel header
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if cmd == nil {
+		t.Fatal("e should start an export")
+	}
+	newModel, _ = newModel.Update(cmd())
+	if got := newModel.(Model).statusMsg; got != "Exported to fix-9c4f.md" {
+		t.Errorf("status = %q, want the export path", got)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "fix-9c4f.md"))
+	if err != nil || !strings.Contains(string(data), "> Hello") {
+		t.Errorf("exported file = %q, %v; want the transcript", data, err)
+	}
+}
+
+func TestCreateExportFile_KeepsExistingFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("README.md", []byte("# Project\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := agent.Agent{ID: "a1b2", Name: "README"}
+
+	for _, want := range []string{"README-2.md", "README-3.md"} {
+		f, err := createExportFile(a)
+		if err != nil {
+			t.Fatalf("createExportFile: %v", err)
+		}
+		f.Close()
+		if f.Name() != want {
+			t.Errorf("export file = %q, want %q", f.Name(), want)
+		}
+	}
+	if data, _ := os.ReadFile("README.md"); string(data) != "# Project\n" {
+		t.Errorf("README.md = %q, want it untouched", data)
+	}
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/sky-xo/june/internal/agent"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Values the filter cycles through, "" meaning any.
+var (
+	filterSources  = []string{"", agent.SourceClaude, agent.SourceCodex, agent.SourceGemini}
+	filterStatuses = []string{"", filterActive, filterRecent, filterDone}
+)
+
+// Agent statuses the sidebar can be filtered by.
+const (
+	filterActive = "active" // Running
+	filterRecent = "recent" // Not running, but active in the last two hours
+	filterDone   = "done"   // Neither
+)
+
+// sidebarFilter narrows the agents shown in the sidebar. The zero value
+// shows all of them.
+type sidebarFilter struct {
+	text   string // Fuzzy match on the agent's display name
+	source string // Agent source, "" for any
+	status string // filterActive, filterRecent or filterDone, "" for any
+}
+
+// isSet reports whether the filter hides anything.
+func (f sidebarFilter) isSet() bool {
+	return f != sidebarFilter{}
+}
+
+// matches reports whether the filter shows a.
+func (f sidebarFilter) matches(a agent.Agent) bool {
+	if f.source != "" {
+		source := a.Source
+		if source == "" {
+			source = agent.SourceClaude
+		}
+		if source != f.source {
+			return false
+		}
+	}
+	if f.status != "" && agentStatus(a) != f.status {
+		return false
+	}
+	return fuzzyMatch(f.text, a.DisplayName())
+}
+
+// String describes the filter, e.g. `codex · active · "auth"`.
+func (f sidebarFilter) String() string {
+	var parts []string
+	if f.source != "" {
+		parts = append(parts, f.source)
+	}
+	if f.status != "" {
+		parts = append(parts, f.status)
+	}
+	if f.text != "" {
+		parts = append(parts, `"`+f.text+`"`)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// agentStatus returns whether a is active, recent or done.
+func agentStatus(a agent.Agent) string {
+	switch {
+	case a.IsActive():
+		return filterActive
+	case a.IsRecent():
+		return filterRecent
+	}
+	return filterDone
+}
+
+// fuzzyMatch reports whether the non-space characters of pattern appear in
+// s in order, ignoring case: "ea9" matches "explore-auth-9c4f".
+func fuzzyMatch(pattern, s string) bool {
+	target := []rune(strings.ToLower(s))
+	i := 0
+	for _, r := range strings.ToLower(pattern) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		for i < len(target) && target[i] != r {
+			i++
+		}
+		if i == len(target) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// nextValue returns the value after cur in values, wrapping around.
+func nextValue(values []string, cur string) string {
+	for i, v := range values {
+		if v == cur {
+			return values[(i+1)%len(values)]
+		}
+	}
+	return values[0]
+}
+
+// orAll returns v, or "all" if it's empty.
+func orAll(v string) string {
+	if v == "" {
+		return "all"
+	}
+	return v
+}
+
+// setFilter changes the sidebar filter, keeping the selected agent selected
+// if it still matches, and loads the transcript of the new selection if it
+// doesn't.
+func (m *Model) setFilter(f sidebarFilter) tea.Cmd {
+	m.filter = f
+	m.sidebarOffset = 0
+	m.preserveSelectionAfterRefresh()
+	m.lastNavWasKeyboard = true
+	m.ensureSelectedVisible()
+
+	a := m.SelectedAgent()
+	if a == nil || (m.lastViewedAgent != nil && m.lastViewedAgent.ID == a.ID) {
+		return nil
+	}
+	m.lastViewedAgent = a
+	return loadTranscriptCmd(*a, m.tails[a.ID])
+}
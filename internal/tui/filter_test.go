@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "anything", true},
+		{"auth", "explore-auth-9c4f", true},
+		{"ea9", "explore-auth-9c4f", true},
+		{"EA9", "explore-auth-9c4f", true},
+		{"ex 9c", "explore-auth-9c4f", true},
+		{"9ea", "explore-auth-9c4f", false},
+		{"authz", "explore-auth-9c4f", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSidebarFilter_Matches(t *testing.T) {
+	now := time.Now()
+	running := agent.Agent{ID: "a1", Name: "impl-9c4f", Source: agent.SourceCodex, Status: agent.StatusRunning, LastActivity: now}
+	recent := agent.Agent{ID: "a2", Source: "", LastActivity: now.Add(-time.Hour)} // Claude agent without a name
+	old := agent.Agent{ID: "a3", Name: "research-3b7a", Source: agent.SourceGemini, Status: agent.StatusSucceeded, LastActivity: now.Add(-48 * time.Hour)}
+
+	tests := []struct {
+		filter sidebarFilter
+		want   []bool
+	}{
+		{sidebarFilter{}, []bool{true, true, true}},
+		{sidebarFilter{source: agent.SourceClaude}, []bool{false, true, false}},
+		{sidebarFilter{source: agent.SourceGemini}, []bool{false, false, true}},
+		{sidebarFilter{status: filterActive}, []bool{true, false, false}},
+		{sidebarFilter{status: filterRecent}, []bool{false, true, false}},
+		{sidebarFilter{status: filterDone}, []bool{false, false, true}},
+		{sidebarFilter{text: "a2"}, []bool{false, true, false}}, // Matches the display name (ID)
+		{sidebarFilter{text: "9c", source: agent.SourceCodex}, []bool{true, false, false}},
+	}
+	for _, tt := range tests {
+		for i, a := range []agent.Agent{running, recent, old} {
+			if got := tt.filter.matches(a); got != tt.want[i] {
+				t.Errorf("%+v matches %s = %v, want %v", tt.filter, a.ID, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestSidebarItems_Filtered(t *testing.T) {
+	now := time.Now()
+	m := NewModel("/test/claude/projects", "/test/repo", "repo")
+	m.channels = []agent.Channel{
+		{Name: "repo:main", Agents: []agent.Agent{
+			{ID: "a1", Name: "explore-auth", Source: agent.SourceClaude, LastActivity: now},
+			{ID: "a2", Name: "old-auth-fix", Source: agent.SourceCodex, Status: agent.StatusSucceeded, LastActivity: now.Add(-48 * time.Hour)},
+		}},
+		{Name: "repo:feature", Agents: []agent.Agent{
+			{ID: "a3", Name: "research", Source: agent.SourceGemini, LastActivity: now},
+		}},
+	}
+
+	// Filtering shows old matches without the expander and drops channels
+	// with no matches
+	m.filter = sidebarFilter{text: "auth"}
+	items := m.sidebarItems()
+	var got []string
+	for _, item := range items {
+		switch {
+		case item.isHeader:
+			got = append(got, item.channelName)
+		case item.isExpander:
+			got = append(got, "expander")
+		default:
+			got = append(got, item.agent.ID)
+		}
+	}
+	if strings.Join(got, " ") != "repo:main a1 a2" {
+		t.Errorf("filtered items = %v, want [repo:main a1 a2]", got)
+	}
+
+	m.filter = sidebarFilter{source: agent.SourceClaude, status: filterDone}
+	if items := m.sidebarItems(); len(items) != 0 {
+		t.Errorf("got %d items, want none", len(items))
+	}
+	m.width, m.height = 80, 20
+	if content := m.renderSidebarContent(20, 10); content != "No matching agents" {
+		t.Errorf("empty filtered sidebar = %q", content)
+	}
+}
+
+func TestFilter_PersistsAcrossRefreshes(t *testing.T) {
+	now := time.Now()
+	channels := func() agent.Channel {
+		return agent.Channel{Name: "repo:main", Agents: []agent.Agent{
+			{ID: "a1", Name: "explore-auth", Source: agent.SourceClaude, LastActivity: now},
+			{ID: "a2", Name: "impl-db", Source: agent.SourceCodex, LastActivity: now},
+			{ID: "a3", Name: "impl-api", Source: agent.SourceCodex, LastActivity: now},
+		}}
+	}
+	m := createModelWithAgents(channels().Agents, 80, 20)
+	m.selectedIdx, m.selectedAgentID = 1, "a1"
+
+	// Typing in the filter prompt applies it live
+	m = typeKeys(m, runes("f"), runes("impl"))
+	if m.prompt != promptFilter || m.filter.text != "impl" {
+		t.Fatalf("prompt %v, filter %+v; want a live filter", m.prompt, m.filter)
+	}
+	if a := m.SelectedAgent(); a == nil || a.ID != "a2" {
+		t.Fatalf("selection should move to the first match, got %+v", a)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	if m.filter.source != agent.SourceCodex {
+		t.Errorf("two Tabs should cycle the source to codex, got %q", m.filter.source)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter}, runes("j"))
+	if a := m.SelectedAgent(); m.prompt != promptNone || a == nil || a.ID != "a3" {
+		t.Fatalf("after Enter, j should select a3; prompt %v, selected %+v", m.prompt, a)
+	}
+
+	// A rescan keeps the filter and the selection
+	newModel, _ := m.Update(channelsMsg([]agent.Channel{channels()}))
+	m = newModel.(Model)
+	if m.filter.text != "impl" || len(m.sidebarItems()) != 3 {
+		t.Errorf("filter %+v with %d items after refresh, want it kept", m.filter, len(m.sidebarItems()))
+	}
+	if a := m.SelectedAgent(); a == nil || a.ID != "a3" {
+		t.Errorf("selection after refresh = %+v, want a3", a)
+	}
+	if !strings.Contains(m.View(), `Filter: codex · "impl"`) {
+		t.Error("status bar should describe the filter")
+	}
+
+	m = typeKeys(m, runes("F"))
+	if m.filter.isSet() || len(m.sidebarItems()) != 4 {
+		t.Errorf("F should clear the filter, got %+v", m.filter)
+	}
+}
+
+func TestFilter_EscRestoresPreviousFilter(t *testing.T) {
+	m := createModelWithAgents(createTestAgents(3), 80, 20)
+	m.filter = sidebarFilter{status: filterActive}
+
+	m = typeKeys(m, runes("f"), runes("xyz"), tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.filter.text != "xyz" || m.filter.status != filterRecent {
+		t.Fatalf("filter while editing = %+v", m.filter)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filter != (sidebarFilter{status: filterActive}) {
+		t.Errorf("Esc should restore the previous filter, got %+v", m.filter)
+	}
+}
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Find highlight colors (256-color palette): matches get a dark yellow
+// background, the current match a bright one.
+var (
+	findMatchFG   = Color{Type: Color256, Value: 16} // Black
+	findMatchBG   = Color{Type: Color256, Value: 136}
+	findCurrentBG = Color{Type: Color256, Value: 220}
+)
+
+// findOptions are the find modes, toggled in the find prompt.
+type findOptions struct {
+	regex      bool // Pattern is a regular expression instead of literal text
+	ignoreCase bool
+}
+
+// findMatch is where a find pattern matches the transcript, in content line
+// cells.
+type findMatch struct {
+	row        int
+	start, end int // Columns, end exclusive
+}
+
+// findState is an active find in the displayed transcript, like less's
+// /pattern.
+type findState struct {
+	pattern string
+	re      *regexp.Regexp
+	matches []findMatch
+	current int // Index into matches of the current match, -1 if none
+}
+
+// compileFind compiles a find pattern with the given modes.
+func compileFind(pattern string, opts findOptions) (*regexp.Regexp, error) {
+	expr := pattern
+	if !opts.regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if opts.ignoreCase {
+		expr = "(?i)" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+// findMatches returns every non-empty match of re in lines.
+func findMatches(lines []StyledLine, re *regexp.Regexp) []findMatch {
+	var matches []findMatch
+	for row, line := range lines {
+		text := line.String()
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			// Cells are runes; convert the byte offsets
+			start := utf8.RuneCountInString(text[:loc[0]])
+			end := start + utf8.RuneCountInString(text[loc[0]:loc[1]])
+			matches = append(matches, findMatch{row: row, start: start, end: end})
+		}
+	}
+	return matches
+}
+
+// startFind finds pattern in the displayed transcript and centers the first
+// match at or below the top of the view.
+func (m *Model) startFind(pattern string) {
+	re, err := compileFind(pattern, m.findOpts)
+	if err != nil {
+		m.statusMsg = "Invalid pattern: " + err.Error()
+		return
+	}
+	m.search = nil
+	m.find = &findState{pattern: pattern, re: re, current: -1}
+	m.find.matches = findMatches(m.contentLines, re)
+	if len(m.find.matches) == 0 {
+		m.find = nil
+		m.statusMsg = "Pattern not found: " + pattern
+		m.renderViewportContent()
+		return
+	}
+
+	m.find.current = 0
+	for i, match := range m.find.matches {
+		if match.row >= m.viewport.YOffset {
+			m.find.current = i
+			break
+		}
+	}
+	m.renderViewportContent()
+	m.centerLine(m.find.matches[m.find.current].row)
+}
+
+// refreshFind re-finds the pattern after the transcript content changed,
+// keeping the current match on the same line where it still exists.
+func (m *Model) refreshFind() {
+	f := m.find
+	if f == nil {
+		return
+	}
+	row := -1
+	if f.current >= 0 {
+		row = f.matches[f.current].row
+	}
+	f.matches = findMatches(m.contentLines, f.re)
+	f.current = -1
+	for i, match := range f.matches {
+		if match.row == row {
+			f.current = i
+			break
+		}
+	}
+}
+
+// nextFindMatch makes the next (dir 1) or previous (dir -1) match current,
+// wrapping around, and centers it.
+func (m *Model) nextFindMatch(dir int) {
+	f := m.find
+	if len(f.matches) == 0 {
+		return
+	}
+	if f.current < 0 {
+		// Lost the current match (e.g. after switching agents): start from
+		// the top of the view
+		f.current = 0
+		for i, match := range f.matches {
+			if match.row >= m.viewport.YOffset {
+				f.current = i
+				break
+			}
+		}
+		if dir < 0 {
+			f.current = (f.current - 1 + len(f.matches)) % len(f.matches)
+		}
+	} else {
+		f.current = (f.current + dir + len(f.matches)) % len(f.matches)
+	}
+	m.renderViewportContent()
+	m.centerLine(f.matches[f.current].row)
+}
+
+// applyFindHighlight returns content lines with find matches highlighted.
+func (m *Model) applyFindHighlight() []StyledLine {
+	if m.find == nil || len(m.find.matches) == 0 {
+		return m.contentLines
+	}
+	result := make([]StyledLine, len(m.contentLines))
+	copy(result, m.contentLines)
+	for i, match := range m.find.matches {
+		if match.row >= len(result) {
+			continue
+		}
+		bg := findMatchBG
+		if i == m.find.current {
+			bg = findCurrentBG
+		}
+		result[match.row] = result[match.row].WithHighlight(match.start, match.end, findMatchFG, bg)
+	}
+	return result
+}
+
+// findStatus describes the active find for the status bar.
+func (m Model) findStatus() string {
+	f := m.find
+	if len(f.matches) == 0 {
+		return fmt.Sprintf("Find %q: no matches | Esc: clear", f.pattern)
+	}
+	current := "-"
+	if f.current >= 0 {
+		current = fmt.Sprint(f.current + 1)
+	}
+	return fmt.Sprintf("Find %q: %s/%d | n/N: next/prev | Esc: clear", f.pattern, current, len(f.matches))
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sky-xo/june/internal/transcript"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCompileFind_Modes(t *testing.T) {
+	tests := []struct {
+		pattern string
+		opts    findOptions
+		text    string
+		want    bool
+	}{
+		{"db.go", findOptions{}, "internal/db/db.go", true},
+		{"db.go", findOptions{}, "internal/db/dbxgo", false}, // Literal: . is not a wildcard
+		{"db.go", findOptions{regex: true}, "internal/db/dbxgo", true},
+		{"Error", findOptions{}, "error: boom", false},
+		{"Error", findOptions{ignoreCase: true}, "error: boom", true},
+		{`fail(ed|ing)`, findOptions{regex: true, ignoreCase: true}, "FAILING test", true},
+	}
+	for _, tt := range tests {
+		re, err := compileFind(tt.pattern, tt.opts)
+		if err != nil {
+			t.Fatalf("compileFind(%q, %+v): %v", tt.pattern, tt.opts, err)
+		}
+		if got := re.MatchString(tt.text); got != tt.want {
+			t.Errorf("compileFind(%q, %+v) matching %q = %v, want %v", tt.pattern, tt.opts, tt.text, got, tt.want)
+		}
+	}
+	if _, err := compileFind("(", findOptions{regex: true}); err == nil {
+		t.Error("an invalid regex should fail to compile")
+	}
+}
+
+func TestFindMatches_UsesCellColumns(t *testing.T) {
+	lines := []StyledLine{
+		ParseStyledLine("\x1b[1m⏺ Bash\x1b[0m go test"),
+		ParseStyledLine("no match"),
+		ParseStyledLine("test, test"),
+	}
+	re, _ := compileFind("test", findOptions{})
+	got := findMatches(lines, re)
+	want := []findMatch{{row: 0, start: 10, end: 14}, {row: 2, start: 0, end: 4}, {row: 2, start: 6, end: 10}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("findMatches = %v, want %v", got, want)
+	}
+
+	// Empty matches are skipped
+	re, _ = compileFind("x*", findOptions{regex: true})
+	if got := findMatches(lines, re); len(got) != 0 {
+		t.Errorf("findMatches of an empty match = %v, want none", got)
+	}
+}
+
+// findTestModel returns a model displaying a transcript of n user messages,
+// every tenth mentioning a needle, with the transcript panel focused.
+func findTestModel(n int) Model {
+	m := createModelWithAgents(createTestAgents(1), 80, 20)
+	m.focusedPanel = panelRight
+	var events []transcript.Event
+	for i := 0; i < n; i++ {
+		text := fmt.Sprintf("message %d", i)
+		if i%10 == 5 {
+			text += " Needle"
+		}
+		events = append(events, transcript.UserPrompt{Text: text})
+	}
+	m.transcripts[m.lastViewedAgent.ID] = events
+	m.updateViewportDimensions()
+	m.updateViewport()
+	return m
+}
+
+func typeKeys(m Model, keys ...tea.KeyMsg) Model {
+	var model tea.Model = m
+	for _, key := range keys {
+		model, _ = model.Update(key)
+	}
+	return model.(Model)
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestFind_HighlightsAndCentersMatches(t *testing.T) {
+	m := findTestModel(60)
+
+	m = typeKeys(m, runes("/"), runes("needle"))
+	if m.prompt != promptFind {
+		t.Fatalf("/ in the transcript panel should open find, got prompt %v", m.prompt)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.statusMsg != "Pattern not found: needle" || m.find != nil {
+		t.Fatalf("case-sensitive find: status %q, find %+v", m.statusMsg, m.find)
+	}
+
+	m = typeKeys(m, runes("/"), tea.KeyMsg{Type: tea.KeyCtrlT}, runes("needle"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.find == nil || len(m.find.matches) != 6 {
+		t.Fatalf("ignore-case find: %+v, want 6 matches", m.find)
+	}
+	first := m.find.matches[m.find.current]
+	if !strings.Contains(m.contentLines[first.row].String(), "message 5 Needle") {
+		t.Errorf("current match on %q, want the first one", m.contentLines[first.row].String())
+	}
+
+	m = typeKeys(m, runes("n"))
+	cur := m.find.matches[m.find.current]
+	if !strings.Contains(m.contentLines[cur.row].String(), "message 15 Needle") {
+		t.Errorf("n should move to the next match, got %q", m.contentLines[cur.row].String())
+	}
+	if got := cur.row - m.viewport.YOffset; got != m.viewport.Height/2 {
+		t.Errorf("current match is %d lines into the view, want centered at %d", got, m.viewport.Height/2)
+	}
+
+	// Every match is highlighted, the current one differently
+	lines := m.applyFindHighlight()
+	if bg := lines[cur.row][cur.start].Style.BG; bg != findCurrentBG {
+		t.Errorf("current match background = %+v, want %+v", bg, findCurrentBG)
+	}
+	if bg := lines[first.row][first.start].Style.BG; bg != findMatchBG {
+		t.Errorf("other match background = %+v, want %+v", bg, findMatchBG)
+	}
+	if bg := lines[first.row][first.start-1].Style.BG; bg != (Color{}) {
+		t.Errorf("text before a match should not be highlighted, got %+v", bg)
+	}
+
+	// N wraps from the first match to the last
+	m = typeKeys(m, runes("N"), runes("N"))
+	if m.find.current != len(m.find.matches)-1 {
+		t.Errorf("N from the first match should wrap to the last, got %d", m.find.current)
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.find != nil {
+		t.Error("Esc should clear the find")
+	}
+}
+
+func TestFind_RegexMode(t *testing.T) {
+	m := findTestModel(30)
+	m = typeKeys(m, runes("/"), tea.KeyMsg{Type: tea.KeyCtrlR}, runes(`message \d5 `), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.find == nil || len(m.find.matches) != 2 {
+		t.Fatalf("regex find: %+v, want matches in messages 15 and 25", m.find)
+	}
+
+	m = typeKeys(m, runes("/"), runes("("), tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.HasPrefix(m.statusMsg, "Invalid pattern") {
+		t.Errorf("status = %q, want an invalid pattern error", m.statusMsg)
+	}
+}
+
+func TestFind_FollowsTranscriptUpdates(t *testing.T) {
+	m := findTestModel(10)
+	m = typeKeys(m, runes("/"), runes("Needle"), tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.find.matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(m.find.matches))
+	}
+
+	id := m.lastViewedAgent.ID
+	m.transcripts[id] = append(m.transcripts[id], transcript.UserPrompt{Text: "another Needle"})
+	m.updateViewport()
+	if len(m.find.matches) != 2 || m.find.current != 0 {
+		t.Errorf("after new output: %d matches, current %d; want 2, still 0", len(m.find.matches), m.find.current)
+	}
+}
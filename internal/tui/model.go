@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/sky-xo/june/internal/agent"
 	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/config"
 	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+	"github.com/sky-xo/june/internal/watch"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,6 +27,21 @@ import (
 
 const sidebarWidth = 23
 
+// minSidebarNameLen is the shortest an agent name is cut to, to make room
+// for its usage in the sidebar.
+const minSidebarNameLen = 10
+
+// toolResultPreviewLines is how many lines of tool output are shown until
+// the output is expanded.
+const toolResultPreviewLines = 3
+
+// transcriptOptions controls which collapsible parts of a transcript are
+// shown in full.
+type transcriptOptions struct {
+	expandToolOutput bool // Tool results in full instead of a preview
+	showReasoning    bool // Reasoning in full instead of a one-line summary
+}
+
 // selectionHighlightColor is the background color for selected text (256-color palette gray)
 var selectionHighlightColor = Color{Type: Color256, Value: 238}
 
@@ -29,18 +49,21 @@ var (
 	// AdaptiveColor: Light = color on light bg, Dark = color on dark bg
 	activeStyle     = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "2", Dark: "10"})  // green
 	doneStyle       = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "243", Dark: "8"}) // gray
+	failedStyle     = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "1", Dark: "9"})   // red
 	selectedBgStyle = lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "254", Dark: "8"}) // highlighted background
 	promptStyle     = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "4", Dark: "6"}).Bold(true) // blue/cyan, bold
 	promptBarStyle  = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "4", Dark: "6"})            // blue/cyan for half-block
 	toolStyle       = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#2E7D32", Dark: "#C8FB9E"})        // lime green (matches focused border)
 	toolBoldStyle   = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#2E7D32", Dark: "#C8FB9E"}).Bold(true) // lime green bold for tool names
 	toolDimStyle    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "243", Dark: "240"})                   // dim gray for command details
+	reasoningStyle  = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "243", Dark: "240"}).Italic(true)      // dim gray italic for reasoning
 	diffAddStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.AdaptiveColor{Light: "#2E7D32", Dark: "#98FB98"}).
 			Background(lipgloss.AdaptiveColor{Light: "#E8F5E9", Dark: "#1B3D1B"}) // green fg + subtle green bg
 	diffDelStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.AdaptiveColor{Light: "#C62828", Dark: "#FF6B6B"}).
 			Background(lipgloss.AdaptiveColor{Light: "#FFEBEE", Dark: "#3D1B1B"}) // red fg + subtle red bg
+	projectHeaderStyle = lipgloss.NewStyle().Bold(true).Underline(true) // project headers in all-projects mode
 	statusBarStyle  = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "243", Dark: "8"})          // gray
 
 	focusedBorderColor   = lipgloss.AdaptiveColor{Light: "#2E7D32", Dark: "#C8FB9E"} // green (darker on light, pale on dark)
@@ -197,15 +220,16 @@ func (m *Model) updateSelectionHighlight() {
 	m.renderViewportContent()
 }
 
-// applySelectionHighlight returns content lines with selection highlighting applied
+// applySelectionHighlight returns content lines with find and selection highlighting applied
 func (m *Model) applySelectionHighlight() []StyledLine {
+	lines := m.applyFindHighlight()
 	if !m.selection.Active || m.selection.IsEmpty() || len(m.contentLines) == 0 {
-		return m.contentLines
+		return lines
 	}
 
 	start, end := m.selection.Normalize()
-	result := make([]StyledLine, len(m.contentLines))
-	copy(result, m.contentLines)
+	result := make([]StyledLine, len(lines))
+	copy(result, lines)
 
 	// Clamp to valid range
 	if start.Row >= len(m.contentLines) {
@@ -228,7 +252,7 @@ func (m *Model) applySelectionHighlight() []StyledLine {
 		}
 
 		if startCol < endCol {
-			result[row] = m.contentLines[row].WithSelection(startCol, endCol, selectionHighlightColor)
+			result[row] = result[row].WithSelection(startCol, endCol, selectionHighlightColor)
 		}
 	}
 
@@ -238,12 +262,14 @@ func (m *Model) applySelectionHighlight() []StyledLine {
 // sidebarItem represents either a channel header, an agent, or an expander in the sidebar.
 type sidebarItem struct {
 	isHeader    bool
+	isProject   bool          // Header of a project's channels, in all-projects mode
 	channelName string        // Only set for headers
 	channelIdx  int           // Index into m.channels
 	agent       *agent.Agent  // Only set for agents
 	agentIdx    int           // Index within channel's agents slice
 	isExpander  bool          // True for "show N more" items
 	hiddenCount int           // Only set for expanders: how many agents are hidden
+	isRetry     bool          // Agent is shown indented under the agent it retries
 }
 
 // Model is the TUI state.
@@ -251,9 +277,16 @@ type Model struct {
 	claudeProjectsDir string                    // Base Claude projects directory (~/.claude/projects)
 	basePath          string                    // Git repo base path
 	repoName          string                    // Repository name (e.g., "june")
+	allProjects       bool                      // Show the agents of every project, grouped by project
 	channels          []agent.Channel           // Channels with their agents
-	transcripts       map[string][]claude.Entry // Agent ID -> transcript entries
+	transcripts       map[string][]transcript.Event // Agent ID -> transcript events
+	tails             map[string]transcriptTail // Agent ID -> how far its transcript has been read
 	codexDB           *db.DB                    // Codex agent database connection (reused across ticks)
+	usageCache        *usageCache               // Transcript usage summaries (reused across ticks)
+	rules             config.Rules              // Rules hiding or dimming agents
+	watcher           *watch.Watcher            // File notifications, nil when polling
+	lastScan          time.Time                 // When channels were last rescanned
+	scanScheduled     bool                      // A throttled rescan is pending
 
 	selectedIdx        int           // Currently selected item index (across all channels + headers)
 	selectedAgentID    string        // ID of selected agent (for preserving selection across refreshes)
@@ -268,6 +301,16 @@ type Model struct {
 	viewport           viewport.Model
 	contentLines       []StyledLine // Lines of content for selection mapping
 	lineToItemIdx      []int        // Maps rendered sidebar line number to sidebarItems index (-1 for separators)
+	transcriptOpts     transcriptOptions // Which collapsible transcript parts are expanded
+	confirmKill        *agent.Agent // Agent awaiting kill confirmation (y/n), nil otherwise
+	statusMsg          string       // Transient message shown in the status bar until the next key
+	prompt             promptKind   // Which query is being typed after /, if any
+	promptInput        string       // Query typed so far
+	search             *searchState // Active search stepped through with n/N, nil if none
+	find               *findState   // Active find in the transcript, nil if none
+	findOpts           findOptions  // Find modes, kept across finds
+	filter             sidebarFilter // Which agents the sidebar shows, kept across refreshes
+	filterBefore       sidebarFilter // Filter to restore if editing it is cancelled
 	err                error
 }
 
@@ -285,51 +328,100 @@ func NewModel(claudeProjectsDir, basePath, repoName string) Model {
 		basePath:          basePath,
 		repoName:          repoName,
 		channels:          []agent.Channel{},
-		transcripts:       make(map[string][]claude.Entry),
+		transcripts:       make(map[string][]transcript.Event),
+		tails:             make(map[string]transcriptTail),
 		codexDB:           codexDB,
+		usageCache:        newUsageCache(usage.DefaultPrices()),
+		rules:             config.DefaultRules(),
 		expandedChannels:  make(map[int]bool),
 		viewport:          viewport.New(0, 0),
 	}
 }
 
+// SetPrices sets the model price table usage costs are estimated with.
+func (m *Model) SetPrices(prices usage.Prices) {
+	m.usageCache.setPrices(prices)
+}
+
+// SetRules sets the rules that hide or dim agents.
+func (m *Model) SetRules(rules config.Rules) {
+	m.rules = rules
+}
+
+// SetStatus shows msg in the status bar until the next key.
+func (m *Model) SetStatus(msg string) {
+	m.statusMsg = msg
+}
+
+// SetAllProjects shows the agents of every project rather than just the repo's.
+func (m *Model) SetAllProjects(all bool) {
+	m.allProjects = all
+}
+
 // Close cleans up resources held by the Model.
 func (m *Model) Close() {
 	if m.codexDB != nil {
 		m.codexDB.Close()
 		m.codexDB = nil
 	}
+	if m.watcher != nil {
+		m.watcher.Close()
+		m.watcher = nil
+	}
 }
 
 // sidebarItems returns a flat list of all items to display in the sidebar.
 // Filters agents to show only active/recent unless channel is expanded.
 func (m Model) sidebarItems() []sidebarItem {
 	var items []sidebarItem
+	filtering := m.filter.isSet()
+	lastProject := ""
 	for ci, ch := range m.channels {
-		// Add channel header
-		items = append(items, sidebarItem{
-			isHeader:    true,
-			channelName: ch.Name,
-			channelIdx:  ci,
-		})
-
-		expanded := m.expandedChannels[ci]
+		// A filter shows every matching agent, without an expander
+		expanded := m.expandedChannels[ci] || filtering
 		var visibleAgents, hiddenAgents []int
 
 		for ai, agent := range ch.Agents {
+			if !m.filter.matches(agent) {
+				continue
+			}
 			if expanded || agent.IsActive() || agent.IsRecent() {
 				visibleAgents = append(visibleAgents, ai)
 			} else {
 				hiddenAgents = append(hiddenAgents, ai)
 			}
 		}
+		if filtering && len(visibleAgents) == 0 {
+			continue // Nothing in this channel matches
+		}
 
-		// Add visible agents
-		for _, ai := range visibleAgents {
+		// In all-projects mode, head each project's channels
+		if m.allProjects && (len(items) == 0 || ch.Project != lastProject) {
+			items = append(items, sidebarItem{
+				isHeader:    true,
+				isProject:   true,
+				channelName: ch.Project,
+				channelIdx:  ci,
+			})
+		}
+		lastProject = ch.Project
+
+		// Add channel header
+		items = append(items, sidebarItem{
+			isHeader:    true,
+			channelName: ch.Name,
+			channelIdx:  ci,
+		})
+
+		// Add visible agents, with retries grouped under their original
+		for _, ai := range groupRetries(ch.Agents, visibleAgents) {
+			a := &m.channels[ci].Agents[ai]
 			items = append(items, sidebarItem{
 				isHeader:   false,
 				channelIdx: ci,
-				agent:      &m.channels[ci].Agents[ai],
+				agent:      a,
 				agentIdx:   ai,
+				isRetry:    a.RetryOf != "" && containsAgentNamed(ch.Agents, visibleAgents, a.RetryOf),
 			})
 		}
 
@@ -345,6 +437,43 @@ func (m Model) sidebarItems() []sidebarItem {
 	return items
 }
 
+// groupRetries reorders agent indices so that each retry directly follows the
+// agent it retries. Retries whose original isn't in indices keep their place.
+func groupRetries(agents []agent.Agent, indices []int) []int {
+	retries := make(map[string][]int) // original name -> retry indices
+	for _, ai := range indices {
+		if orig := agents[ai].RetryOf; orig != "" && containsAgentNamed(agents, indices, orig) {
+			retries[orig] = append(retries[orig], ai)
+		}
+	}
+	if len(retries) == 0 {
+		return indices
+	}
+
+	ordered := make([]int, 0, len(indices))
+	for _, ai := range indices {
+		a := agents[ai]
+		if a.RetryOf != "" && containsAgentNamed(agents, indices, a.RetryOf) {
+			continue // Emitted with its original
+		}
+		ordered = append(ordered, ai)
+		if a.Name != "" {
+			ordered = append(ordered, retries[a.Name]...)
+		}
+	}
+	return ordered
+}
+
+// containsAgentNamed reports whether any agent at the given indices has name.
+func containsAgentNamed(agents []agent.Agent, indices []int, name string) bool {
+	for _, ai := range indices {
+		if agents[ai].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // findAgentIndexByID returns the sidebar index of an agent by its ID.
 // Returns (-1, false) if not found or ID is empty.
 func (m Model) findAgentIndexByID(agentID string) (int, bool) {
@@ -421,13 +550,20 @@ func (m *Model) preserveSelectionAfterRefresh() {
 	}
 }
 
+// separatorBefore reports whether a blank line separates items[i] from the
+// item above: every header has one, except a channel header directly under
+// its project's header.
+func separatorBefore(items []sidebarItem, i int) bool {
+	return i > 0 && items[i].isHeader && !items[i-1].isProject
+}
+
 // countSeparatorsBefore returns the number of blank separator lines that would appear
 // before the given item index. Separators appear before each channel header except the first.
 func (m Model) countSeparatorsBefore(itemIdx int) int {
 	items := m.sidebarItems()
 	count := 0
 	for i := 0; i < itemIdx && i < len(items); i++ {
-		if items[i].isHeader && i > 0 {
+		if separatorBefore(items, i) {
 			count++
 		}
 	}
@@ -439,7 +575,7 @@ func (m Model) countSeparatorsInRange(start, end int) int {
 	items := m.sidebarItems()
 	count := 0
 	for i := start; i < end && i < len(items); i++ {
-		if items[i].isHeader && i > 0 {
+		if separatorBefore(items, i) {
 			count++
 		}
 	}
@@ -573,10 +709,14 @@ func (m *Model) ensureSelectedVisible() {
 
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
-		tickCmd(),
-		scanChannelsCmd(m.claudeProjectsDir, m.basePath, m.repoName, m.codexDB),
-	)
+	cmds := []tea.Cmd{
+		tickCmd(m.tickInterval()),
+		scanChannelsCmd(m.claudeProjectsDir, m.basePath, m.repoName, m.allProjects, m.codexDB, m.rules, m.usageCache),
+	}
+	if m.watcher != nil {
+		cmds = append(cmds, waitForChangesCmd(m.watcher))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages.
@@ -585,6 +725,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.statusMsg = ""
+
+		// Pending kill confirmation swallows the next key
+		if m.confirmKill != nil {
+			target := m.confirmKill
+			m.confirmKill = nil
+			if msg.String() == "y" {
+				m.statusMsg = "Killing " + target.Name + "..."
+				return m, killAgentCmd(m.codexDB, target.Name)
+			}
+			return m, nil
+		}
+
+		if m.prompt != promptNone {
+			return m.updatePrompt(msg)
+		}
+
 		// Handle selection mode keys first
 		if m.selection.Active {
 			switch msg.String() {
@@ -626,7 +783,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.ensureSelectedVisible()
 					if agent := m.SelectedAgent(); agent != nil {
 						m.lastViewedAgent = agent
-						cmds = append(cmds, loadTranscriptCmd(*agent))
+						cmds = append(cmds, loadTranscriptCmd(*agent, m.tails[agent.ID]))
 					}
 				}
 				// Return early to prevent viewport from also handling this key
@@ -647,7 +804,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.ensureSelectedVisible()
 					if agent := m.SelectedAgent(); agent != nil {
 						m.lastViewedAgent = agent
-						cmds = append(cmds, loadTranscriptCmd(*agent))
+						cmds = append(cmds, loadTranscriptCmd(*agent, m.tails[agent.ID]))
 					}
 				}
 				// Return early to prevent viewport from also handling this key
@@ -686,7 +843,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.lastNavWasKeyboard = true
 				if agent := m.SelectedAgent(); agent != nil {
 					m.lastViewedAgent = agent
-					cmds = append(cmds, loadTranscriptCmd(*agent))
+					cmds = append(cmds, loadTranscriptCmd(*agent, m.tails[agent.ID]))
 				}
 				return m, tea.Batch(cmds...)
 			} else {
@@ -726,12 +883,77 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.lastNavWasKeyboard = true
 				if agent := m.SelectedAgent(); agent != nil {
 					m.lastViewedAgent = agent
-					cmds = append(cmds, loadTranscriptCmd(*agent))
+					cmds = append(cmds, loadTranscriptCmd(*agent, m.tails[agent.ID]))
 				}
 				return m, tea.Batch(cmds...)
 			} else {
 				m.viewport.HalfViewDown()
 			}
+		case "K":
+			// Kill the selected spawned agent (asks for confirmation)
+			a := m.SelectedAgent()
+			switch {
+			case a == nil:
+			case a.PID == 0:
+				m.statusMsg = "Only agents started with june spawn can be killed"
+			case !a.IsActive():
+				m.statusMsg = a.Name + " is not running"
+			default:
+				m.confirmKill = a
+			}
+			return m, nil
+		case "o":
+			// Expand or collapse tool output
+			m.transcriptOpts.expandToolOutput = !m.transcriptOpts.expandToolOutput
+			m.updateViewport()
+		case "t":
+			// Show or hide reasoning
+			m.transcriptOpts.showReasoning = !m.transcriptOpts.showReasoning
+			m.updateViewport()
+		case "/":
+			// Find in the transcript, or search all transcripts from the sidebar
+			m.prompt = promptSearch
+			if m.focusedPanel == panelRight {
+				m.prompt = promptFind
+			}
+			m.promptInput = ""
+			return m, nil
+		case "n", "N":
+			// Step through find or search matches
+			dir := 1
+			if msg.String() == "N" {
+				dir = -1
+			}
+			if m.find != nil {
+				m.nextFindMatch(dir)
+				return m, nil
+			}
+			return m, m.nextSearchMatch(dir)
+		case "f":
+			// Edit the sidebar filter
+			m.prompt = promptFilter
+			m.promptInput = m.filter.text
+			m.filterBefore = m.filter
+			return m, nil
+		case "F":
+			// Clear the sidebar filter
+			return m, m.setFilter(sidebarFilter{})
+		case "A":
+			// Switch between this repo's agents and every project's
+			return m, m.toggleAllProjects()
+		case "e":
+			// Export the selected agent's transcript as Markdown
+			if a := m.SelectedAgent(); a != nil {
+				m.statusMsg = "Exporting " + a.DisplayName() + "..."
+				return m, exportAgentCmd(*a)
+			}
+			return m, nil
+		case "esc":
+			if m.find != nil {
+				m.find = nil
+				m.renderViewportContent()
+			}
+			m.search = nil
 		case "g":
 			m.viewport.GotoTop()
 		case "G":
@@ -837,7 +1059,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				if agent := m.SelectedAgent(); agent != nil {
 					m.lastViewedAgent = agent
-					cmds = append(cmds, loadTranscriptCmd(*agent))
+					cmds = append(cmds, loadTranscriptCmd(*agent, m.tails[agent.ID]))
 				}
 			}
 		}
@@ -924,25 +1146,59 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateViewport()
 
 	case tickMsg:
-		cmds = append(cmds, tickCmd(), scanChannelsCmd(m.claudeProjectsDir, m.basePath, m.repoName, m.codexDB))
+		cmds = append(cmds, tickCmd(m.tickInterval()), m.scanCmd())
+
+	case fsChangeMsg:
+		cmds = append(cmds, waitForChangesCmd(m.watcher))
+		// Show new output in the viewed transcript right away; tailing is cheap
+		if a := m.lastViewedAgent; a != nil && slices.Contains(msg.Paths, a.TranscriptPath) {
+			cmds = append(cmds, loadTranscriptCmd(*a, m.tails[a.ID]))
+		}
+		if cmd := m.scheduleScan(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case scanDueMsg:
+		m.scanScheduled = false
+		cmds = append(cmds, m.scanCmd())
 
 	case channelsMsg:
 		m.channels = msg
 		m.preserveSelectionAfterRefresh()
 		if agent := m.SelectedAgent(); agent != nil {
 			m.lastViewedAgent = agent
-			cmds = append(cmds, loadTranscriptCmd(*agent))
+			cmds = append(cmds, loadTranscriptCmd(*agent, m.tails[agent.ID]))
 		}
 
 	case transcriptMsg:
 		// Check if we were at the bottom BEFORE updating content
 		wasAtBottom := m.viewport.AtBottom()
 		_, hadTranscript := m.transcripts[msg.agentID]
-		m.transcripts[msg.agentID] = msg.entries
-		m.updateViewport()
-		if !hadTranscript || wasAtBottom {
-			// First time loading OR was following at bottom - keep at bottom
-			m.viewport.GotoBottom()
+		if m.applyTranscript(msg) {
+			m.updateViewport()
+			if !hadTranscript || wasAtBottom {
+				// First time loading OR was following at bottom - keep at bottom
+				m.viewport.GotoBottom()
+			}
+			m.scrollToPendingSearchMatch(msg.agentID)
+		}
+
+	case searchResultMsg:
+		cmds = append(cmds, m.applySearchResult(msg))
+
+	case killResultMsg:
+		if msg.err != nil {
+			m.statusMsg = "Kill failed: " + msg.err.Error()
+		} else {
+			m.statusMsg = "Killed " + msg.name
+		}
+		cmds = append(cmds, m.scanCmd())
+
+	case exportResultMsg:
+		if msg.err != nil {
+			m.statusMsg = "Export failed: " + msg.err.Error()
+		} else {
+			m.statusMsg = "Exported to " + msg.path
 		}
 
 	case errMsg:
@@ -971,10 +1227,14 @@ func (m *Model) updateViewport() {
 	if agent == nil {
 		m.viewport.SetContent("")
 		m.contentLines = nil
+		m.refreshFind()
 		return
 	}
-	entries := m.transcripts[agent.ID]
-	content := formatTranscript(entries, m.viewport.Width)
+	events := m.transcripts[agent.ID]
+	content := formatTranscript(events, m.viewport.Width, m.transcriptOpts)
+	if header := formatSpawnHeader(agent, m.viewport.Width); header != "" {
+		content = header + "\n" + content
+	}
 
 	// Parse ANSI content into StyledLines
 	lines := strings.Split(content, "\n")
@@ -982,6 +1242,7 @@ func (m *Model) updateViewport() {
 	for i, line := range lines {
 		m.contentLines[i] = ParseStyledLine(line)
 	}
+	m.refreshFind()
 
 	// Render for viewport (apply selection if active)
 	m.renderViewportContent()
@@ -989,7 +1250,7 @@ func (m *Model) updateViewport() {
 
 // renderViewportContent renders contentLines to viewport, applying selection if active
 func (m *Model) renderViewportContent() {
-	lines := m.contentLines
+	lines := m.applyFindHighlight()
 	if m.selection.Active && !m.selection.IsEmpty() {
 		lines = m.applySelectionHighlight()
 	}
@@ -1048,7 +1309,14 @@ func (m Model) View() string {
 
 	// Left panel: agent list
 	leftContent := m.renderSidebarContent(leftWidth-2, contentHeight)
-	leftPanel := renderPanelWithTitle("Subagents", leftContent, leftWidth, panelHeight, leftBorderColor)
+	leftTitle := "Subagents"
+	if m.allProjects {
+		leftTitle = "All projects"
+	}
+	if m.filter.isSet() {
+		leftTitle = "Filtered"
+	}
+	leftPanel := renderPanelWithTitle(leftTitle, leftContent, leftWidth, panelHeight, leftBorderColor)
 
 	// Right panel: transcript (uses lastViewedAgent to persist when header is selected)
 	var rightTitle string
@@ -1058,6 +1326,9 @@ func (m Model) View() string {
 		} else {
 			rightTitle = fmt.Sprintf("%s | %s", a.ID, formatTimestamp(a.LastActivity))
 		}
+		if u := formatUsage(a.Usage); u != "" {
+			rightTitle += " | " + u
+		}
 	}
 
 	// Add selection indicator to title
@@ -1077,7 +1348,25 @@ func (m Model) View() string {
 	panels := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
 
 	// Status bar
-	status := statusBarStyle.Render("Tab: switch | j/k: navigate | u/d: page | g/G: top/bottom | q: quit")
+	var status string
+	switch {
+	case m.confirmKill != nil:
+		status = failedStyle.Render(fmt.Sprintf("Kill %s? (y/n)", m.confirmKill.Name))
+	case m.prompt != promptNone:
+		status = statusBarStyle.Render(m.promptStatus())
+	case m.statusMsg != "":
+		status = statusBarStyle.Render(m.statusMsg)
+	case m.find != nil:
+		status = statusBarStyle.Render(m.findStatus())
+	case m.search != nil:
+		status = statusBarStyle.Render(m.searchStatus())
+	case m.filter.isSet() && m.focusedPanel == panelLeft:
+		status = statusBarStyle.Render("Filter: " + m.filter.String() + " | f: edit | F: clear")
+	case m.focusedPanel == panelRight:
+		status = statusBarStyle.Render("Tab: switch | j/k: scroll | u/d: page | g/G: top/bottom | /: find | o: output | t: thinking | e: export | K: kill | q: quit")
+	default:
+		status = statusBarStyle.Render("Tab: switch | j/k: navigate | u/d: page | g/G: top/bottom | /: search | o: output | t: thinking | e: export | A: all projects | K: kill | q: quit")
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, panels, status)
 }
@@ -1087,6 +1376,9 @@ func (m *Model) renderSidebarContent(width, height int) string {
 
 	items := m.sidebarItems()
 	if len(items) == 0 || height <= 0 {
+		if m.filter.isSet() {
+			return "No matching agents"
+		}
 		return "No agents found"
 	}
 
@@ -1131,16 +1423,32 @@ func (m *Model) renderSidebarContent(width, height int) string {
 
 		if item.isHeader {
 			// Add blank separator line before channel headers (except the first visible one)
-			if i > m.sidebarOffset && i > 0 {
+			if i > m.sidebarOffset && separatorBefore(items, i) {
 				lines = append(lines, "")
 				m.lineToItemIdx = append(m.lineToItemIdx, -1) // Separator line
 			}
 
-			// Render channel header
+			if item.isProject {
+				// Render project header: its path, keeping the end if it's too long
+				header := truncateLeft(shortenHome(item.channelName), width)
+				if i == m.selectedIdx {
+					header += strings.Repeat(" ", width-lipgloss.Width(header))
+					lines = append(lines, projectHeaderStyle.Background(lipgloss.AdaptiveColor{Light: "254", Dark: "8"}).Render(header))
+				} else {
+					lines = append(lines, projectHeaderStyle.Render(header))
+				}
+				m.lineToItemIdx = append(m.lineToItemIdx, i)
+				continue
+			}
+
+			// Render channel header, with the channel's usage on the right
 			header := item.channelName
 			if len(header) > width {
 				header = header[:width]
 			}
+			if u := formatSidebarUsage(m.channels[item.channelIdx].Usage()); u != "" && len(header)+1+len(u) <= width {
+				header += strings.Repeat(" ", width-len(header)-len(u)) + u
+			}
 			if i == m.selectedIdx {
 				// Selected header
 				selectedBg := lipgloss.AdaptiveColor{Light: "254", Dark: "8"}
@@ -1173,32 +1481,66 @@ func (m *Model) renderSidebarContent(width, height int) string {
 		} else {
 			// Render agent
 			// Layout: "● Name" for active (dot + space + name)
+			//         "✗ Name" for failed or killed spawned agents
 			//         "  Name" for inactive (2 spaces + name)
 			// Text position stays the same whether active or not
 			a := item.agent
 
 			name := a.DisplayName()
 			maxNameLen := width - 2 // 2 chars for prefix (dot+space or 2 spaces)
+			if item.isRetry {
+				maxNameLen -= 2 // Room for the retry marker
+			}
+			// Usage goes on the right, if it leaves room for a useful name
+			usageText := formatSidebarUsage(a.Usage)
+			if usageText != "" && maxNameLen-len(usageText)-1 >= minSidebarNameLen {
+				maxNameLen -= len(usageText) + 1
+			} else {
+				usageText = ""
+			}
 			if len(name) > maxNameLen {
 				name = name[:maxNameLen]
 			}
+			nameWidth := len(name)
+			if item.isRetry {
+				name = "\u21b3 " + name
+				nameWidth += 2
+			}
+			if usageText != "" {
+				name += strings.Repeat(" ", width-2-nameWidth-len(usageText))
+				nameWidth = width - 2 - len(usageText)
+			}
 
 			if i == m.selectedIdx {
 				selectedBg := lipgloss.AdaptiveColor{Light: "254", Dark: "8"}
 				var prefix string
 				if a.IsActive() {
 					prefix = activeStyle.Background(selectedBg).Render("\u25cf") + selectedBgStyle.Render(" ")
+				} else if a.IsFailed() {
+					prefix = failedStyle.Background(selectedBg).Render("\u2717") + selectedBgStyle.Render(" ")
 				} else {
 					prefix = selectedBgStyle.Render("  ")
 				}
 				rest := name
-				if 2+len(rest) < width {
-					rest = rest + strings.Repeat(" ", width-2-len(rest))
+				if 2+nameWidth+len(usageText) < width {
+					rest = rest + strings.Repeat(" ", width-2-nameWidth)
 				}
-				lines = append(lines, prefix+selectedBgStyle.Render(rest))
+				line := prefix + selectedBgStyle.Render(rest)
+				if usageText != "" {
+					line += doneStyle.Background(selectedBg).Render(usageText)
+				}
+				lines = append(lines, line)
 			} else {
+				if a.Dimmed {
+					name = doneStyle.Render(name)
+				}
+				if usageText != "" {
+					name += doneStyle.Render(usageText)
+				}
 				if a.IsActive() {
 					lines = append(lines, activeStyle.Render("\u25cf")+" "+name)
+				} else if a.IsFailed() {
+					lines = append(lines, failedStyle.Render("\u2717")+" "+name)
 				} else {
 					lines = append(lines, "  "+name)
 				}
@@ -1330,19 +1672,17 @@ func renderPanelWithTitle(title, content string, width, height int, borderColor
 	return strings.Join(allLines, "\n")
 }
 
-func formatTranscript(entries []claude.Entry, width int) string {
+func formatTranscript(events []transcript.Event, width int, opts transcriptOptions) string {
 	var lines []string
 	lines = append(lines, "") // top padding
 
 	lastWasText := false // track if previous entry was text (for spacing before tools)
+	results := matchToolResults(events)
 
-	for _, e := range entries {
-		switch e.Type {
-		case "user":
-			if e.IsToolResult() {
-				continue
-			}
-			content := strings.TrimSpace(e.TextContent())
+	for i, e := range events {
+		switch e := e.(type) {
+		case transcript.UserPrompt:
+			content := strings.TrimSpace(e.Text)
 			if content != "" {
 				// Add blank line before user prompts (when following assistant content)
 				if len(lines) > 1 {
@@ -1356,17 +1696,28 @@ func formatTranscript(entries []claude.Entry, width int) string {
 				}
 				lastWasText = true
 			}
-		case "assistant":
-			if tool := e.ToolName(); tool != "" {
-				// Add blank line before tools if previous was text
-				if lastWasText {
-					lines = append(lines, "")
-				}
-				toolLines := formatToolUse(e, tool, width)
-				lines = append(lines, toolLines...)
-				lastWasText = false
-			} else if text := e.TextContent(); text != "" {
-				rendered := renderMarkdown(text, width)
+		case transcript.ToolCall:
+			if e.Name == "" {
+				continue
+			}
+			// Add blank line before tools if previous was text
+			if lastWasText {
+				lines = append(lines, "")
+			}
+			toolLines := formatToolUse(e, width)
+			lines = append(lines, toolLines...)
+			if r, ok := results[i]; ok {
+				lines = append(lines, formatToolResult(r, width, opts.expandToolOutput)...)
+			}
+			lastWasText = false
+		case transcript.Reasoning:
+			if thinking := strings.TrimSpace(e.Text); thinking != "" {
+				lines = append(lines, formatReasoning(thinking, width, opts.showReasoning)...)
+				lastWasText = true
+			}
+		case transcript.AssistantText:
+			if e.Text != "" {
+				rendered := renderMarkdown(e.Text, width)
 				lines = append(lines, rendered)
 				lastWasText = true
 			}
@@ -1375,14 +1726,127 @@ func formatTranscript(entries []claude.Entry, width int) string {
 	return strings.Join(lines, "\n")
 }
 
-// formatToolUse formats a tool use entry, with special handling for Bash commands.
-func formatToolUse(e claude.Entry, toolName string, width int) []string {
+// formatReasoning renders an agent's reasoning, dimmed and in italics so it
+// reads apart from its replies. Collapsed, it is a one-line summary.
+func formatReasoning(text string, width int, expanded bool) []string {
+	textLines := strings.Split(text, "\n")
+	if !expanded {
+		noun := "lines"
+		if len(textLines) == 1 {
+			noun = "line"
+		}
+		return []string{reasoningStyle.Render(fmt.Sprintf("✻ Thinking… (%d %s, t to show)", len(textLines), noun))}
+	}
+
+	result := []string{reasoningStyle.Render("✻ Thinking…")}
+	wrapped := ansi.Strip(text)
+	if width > 2 {
+		wrapped = ansi.Wrap(wrapped, width-2, "")
+	}
+	for _, line := range strings.Split(wrapped, "\n") {
+		result = append(result, reasoningStyle.Render("  "+strings.TrimRight(line, " ")))
+	}
+	return result
+}
+
+// matchToolResults pairs tool results with the tool calls they answer, keyed
+// by the index of the call's event. Results are matched by ID; ones without
+// a known ID go to the latest call still waiting for a result.
+func matchToolResults(events []transcript.Event) map[int]transcript.ToolResult {
+	results := make(map[int]transcript.ToolResult)
+	calls := make(map[string]int) // Call ID -> event index
+	var waiting []int             // Calls without a result yet
+	for i, e := range events {
+		switch e := e.(type) {
+		case transcript.ToolCall:
+			if e.Name == "" {
+				continue
+			}
+			if e.ID != "" {
+				calls[e.ID] = i
+			}
+			waiting = append(waiting, i)
+		case transcript.ToolResult:
+			idx, ok := calls[e.ID]
+			if !ok {
+				if len(waiting) == 0 {
+					continue
+				}
+				idx = waiting[len(waiting)-1]
+			}
+			results[idx] = e
+			waiting = slices.DeleteFunc(waiting, func(w int) bool { return w == idx })
+		}
+	}
+	return results
+}
+
+// formatToolResult renders a tool's output under its call: the first
+// toolResultPreviewLines lines, or all of them when expanded. Errors are red.
+func formatToolResult(r transcript.ToolResult, width int, expanded bool) []string {
+	style := toolDimStyle
+	if r.IsError {
+		style = failedStyle
+	}
+
+	content := strings.TrimRight(ansi.Strip(r.Output), " \t\r\n")
+	if content == "" {
+		return []string{style.Render("    ⎿  (no output)")}
+	}
+
+	outputLines := strings.Split(content, "\n")
+	hidden := 0
+	if !expanded && len(outputLines) > toolResultPreviewLines {
+		hidden = len(outputLines) - toolResultPreviewLines
+		outputLines = outputLines[:toolResultPreviewLines]
+	}
+
+	maxLen := width - 7 // "    ⎿  " prefix
+	var result []string
+	for i, line := range outputLines {
+		prefix := "       "
+		if i == 0 {
+			prefix = "    ⎿  "
+		}
+		line = strings.ReplaceAll(strings.TrimRight(line, "\r"), "\t", "    ")
+		if maxLen > 0 {
+			line = ansi.Truncate(line, maxLen, "…")
+		}
+		result = append(result, style.Render(prefix+line))
+	}
+	if hidden > 0 {
+		result = append(result, toolDimStyle.Render(fmt.Sprintf("       … +%d lines (o to expand)", hidden)))
+	}
+	return result
+}
+
+// formatSpawnHeader renders the task and spawn options of a spawned agent,
+// shown above its transcript. Returns "" for agents without spawn settings.
+func formatSpawnHeader(a *agent.Agent, width int) string {
+	if a.Task == "" && a.Options == "" {
+		return ""
+	}
+	lines := []string{""} // top padding
+	if a.Task != "" {
+		// Collapse the prompt to one line; the full text is in june logs
+		task := strings.Join(strings.Fields(a.Task), " ")
+		lines = append(lines, toolDimStyle.Render(ansi.Truncate("Task: "+task, width, "…")))
+	}
+	if a.Options != "" {
+		lines = append(lines, toolDimStyle.Render(ansi.Truncate("Options: "+a.Options, width, "…")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatToolUse formats a tool call, with special handling for Bash commands.
+func formatToolUse(call transcript.ToolCall, width int) []string {
+	toolName := call.Name
 	var result []string
 	maxLen := width - 4 // leave room for "  " prefix and some padding
 
 	// Special handling for Bash: show description + dimmed command
 	if toolName == "Bash" {
-		input := e.ToolInput()
+		input := call.Input
 		desc, _ := input["description"].(string)
 		cmd, _ := input["command"].(string)
 
@@ -1426,7 +1890,7 @@ func formatToolUse(e claude.Entry, toolName string, width int) []string {
 
 	// Special handling for Edit: show file path + diff
 	if toolName == "Edit" {
-		input := e.ToolInput()
+		input := call.Input
 		filePath, _ := input["file_path"].(string)
 		oldStr, _ := input["old_string"].(string)
 		newStr, _ := input["new_string"].(string)
@@ -1452,7 +1916,7 @@ func formatToolUse(e claude.Entry, toolName string, width int) []string {
 
 	// Special handling for Write: show file path + content with syntax highlighting
 	if toolName == "Write" {
-		input := e.ToolInput()
+		input := call.Input
 		filePath, _ := input["file_path"].(string)
 		content, _ := input["content"].(string)
 
@@ -1477,7 +1941,7 @@ func formatToolUse(e claude.Entry, toolName string, width int) []string {
 
 	// Special handling for TodoWrite: show todo list with status indicators
 	if toolName == "TodoWrite" {
-		input := e.ToolInput()
+		input := call.Input
 		todos, _ := input["todos"].([]interface{})
 
 		// Show TodoWrite header
@@ -1520,7 +1984,7 @@ func formatToolUse(e claude.Entry, toolName string, width int) []string {
 
 	// Default: use ToolSummary for other tools
 	// ToolSummary returns "Tool: detail" format, convert to "Tool(detail)" with split styling
-	summary := e.ToolSummary()
+	summary := claude.ToolSummary(call.Name, call.Input)
 
 	// Parse "Tool: detail" format
 	if idx := strings.Index(summary, ": "); idx != -1 {
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -8,8 +9,7 @@ import (
 	"time"
 
 	"github.com/sky-xo/june/internal/agent"
-	"github.com/sky-xo/june/internal/claude"
-	"github.com/sky-xo/june/internal/codex"
+	"github.com/sky-xo/june/internal/transcript"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -431,13 +431,11 @@ func TestRenderMarkdown_ZeroWidth(t *testing.T) {
 
 func TestFormatTranscript_UserPromptStyle(t *testing.T) {
 	// Test that user prompts contain the content
-	entries := []claude.Entry{
-		{Type: "user", Message: claude.Message{
-			Content: "Hello there",
-		}},
+	events := []transcript.Event{
+		transcript.UserPrompt{Text: "Hello there"},
 	}
 
-	result := formatTranscript(entries, 80)
+	result := formatTranscript(events, 80, transcriptOptions{})
 
 	// User prompts should contain the content
 	if !strings.Contains(result, "Hello there") {
@@ -447,18 +445,11 @@ func TestFormatTranscript_UserPromptStyle(t *testing.T) {
 
 func TestFormatTranscript_AssistantMarkdownRendered(t *testing.T) {
 	// Test that assistant markdown content is processed
-	entries := []claude.Entry{
-		{Type: "assistant", Message: claude.Message{
-			Content: []interface{}{
-				map[string]interface{}{
-					"type": "text",
-					"text": "Here is **bold** text",
-				},
-			},
-		}},
+	events := []transcript.Event{
+		transcript.AssistantText{Text: "Here is **bold** text"},
 	}
 
-	result := formatTranscript(entries, 80)
+	result := formatTranscript(events, 80, transcriptOptions{})
 
 	// Should not contain literal asterisks
 	if strings.Contains(result, "**bold**") {
@@ -472,6 +463,135 @@ func TestFormatTranscript_AssistantMarkdownRendered(t *testing.T) {
 	}
 }
 
+// toolCall builds a call to Bash with the given ID.
+func toolCall(id, command string) transcript.ToolCall {
+	return transcript.ToolCall{ID: id, Name: "Bash", Input: map[string]interface{}{"command": command}}
+}
+
+// toolResult builds the result of the call with the given ID.
+func toolResult(id, output string, isError bool) transcript.ToolResult {
+	return transcript.ToolResult{ID: id, Output: output, IsError: isError}
+}
+
+func TestFormatTranscript_ToolResultsUnderTheirCalls(t *testing.T) {
+	// Parallel calls: results arrive after both calls, in reverse order
+	events := []transcript.Event{
+		toolCall("t1", "go build"),
+		toolCall("t2", "go test"),
+		toolResult("t2", "FAIL: TestX", true),
+		toolResult("t1", "ok", false),
+	}
+
+	stripped := stripANSI(formatTranscript(events, 80, transcriptOptions{}))
+
+	build := strings.Index(stripped, "go build")
+	ok := strings.Index(stripped, "⎿  ok")
+	test := strings.Index(stripped, "go test")
+	fail := strings.Index(stripped, "⎿  FAIL: TestX")
+	if build < 0 || ok < 0 || test < 0 || fail < 0 {
+		t.Fatalf("missing tool calls or results:\n%s", stripped)
+	}
+	if !(build < ok && ok < test && test < fail) {
+		t.Errorf("results should follow their own calls:\n%s", stripped)
+	}
+}
+
+func TestFormatToolResult_ErrorIsRed(t *testing.T) {
+	lines := formatToolResult(transcript.ToolResult{Output: "exit status 1", IsError: true}, 80, false)
+	if len(lines) != 1 || lines[0] != failedStyle.Render("    ⎿  exit status 1") {
+		t.Errorf("error result = %q, want it rendered in failedStyle", lines)
+	}
+}
+
+func TestFormatToolResult_CollapsesLongOutput(t *testing.T) {
+	r := transcript.ToolResult{Output: "1\n2\n3\n4\n5\n"}
+
+	collapsed := formatToolResult(r, 80, false)
+	if len(collapsed) != toolResultPreviewLines+1 {
+		t.Fatalf("collapsed = %d lines, want %d preview lines plus a summary", len(collapsed), toolResultPreviewLines)
+	}
+	if got := stripANSI(collapsed[len(collapsed)-1]); !strings.Contains(got, "+2 lines") {
+		t.Errorf("summary = %q, want it to count the 2 hidden lines", got)
+	}
+
+	expanded := formatToolResult(r, 80, true)
+	if len(expanded) != 5 {
+		t.Errorf("expanded = %d lines, want all 5", len(expanded))
+	}
+}
+
+func TestMatchToolResults_FallsBackToLatestCall(t *testing.T) {
+	// Results without IDs (older Codex/Gemini transcripts) follow their call
+	events := []transcript.Event{
+		toolCall("", "ls"),
+		toolResult("", "a.go", false),
+		toolCall("", "pwd"),
+		toolResult("", "/code", false),
+	}
+
+	results := matchToolResults(events)
+	if results[0].Output != "a.go" || results[2].Output != "/code" {
+		t.Errorf("results = %+v, want each result paired with the call before it", results)
+	}
+}
+
+func TestUpdate_OTogglesToolOutput(t *testing.T) {
+	m := createModelWithAgents(createTestAgents(1), 80, 40)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if !newModel.(Model).transcriptOpts.expandToolOutput {
+		t.Error("o should expand tool output")
+	}
+	newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if newModel.(Model).transcriptOpts.expandToolOutput {
+		t.Error("pressing o again should collapse tool output")
+	}
+}
+
+func TestFormatTranscript_ReasoningCollapsedByDefault(t *testing.T) {
+	events := []transcript.Event{
+		transcript.Reasoning{Text: "The test fails because\nthe fixture is stale."},
+		transcript.AssistantText{Text: "Updating the fixture."},
+	}
+
+	collapsed := stripANSI(formatTranscript(events, 80, transcriptOptions{}))
+	if !strings.Contains(collapsed, "Thinking… (2 lines, t to show)") {
+		t.Errorf("collapsed transcript should summarize reasoning, got:\n%s", collapsed)
+	}
+	if strings.Contains(collapsed, "fixture is stale") {
+		t.Errorf("collapsed transcript should hide reasoning text, got:\n%s", collapsed)
+	}
+	if !strings.Contains(collapsed, "Updating the fixture.") {
+		t.Errorf("reply text should still be shown, got:\n%s", collapsed)
+	}
+
+	expanded := stripANSI(formatTranscript(events, 80, transcriptOptions{showReasoning: true}))
+	if !strings.Contains(expanded, "  The test fails because") || !strings.Contains(expanded, "  the fixture is stale.") {
+		t.Errorf("expanded transcript should show reasoning, got:\n%s", expanded)
+	}
+}
+
+func TestFormatReasoning_UsesReasoningStyle(t *testing.T) {
+	lines := formatReasoning("hmm", 80, true)
+	want := []string{reasoningStyle.Render("✻ Thinking…"), reasoningStyle.Render("  hmm")}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("formatReasoning = %q, want %q", lines, want)
+	}
+}
+
+func TestUpdate_TTogglesReasoning(t *testing.T) {
+	m := createModelWithAgents(createTestAgents(1), 80, 40)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if !newModel.(Model).transcriptOpts.showReasoning {
+		t.Error("t should show reasoning")
+	}
+	newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if newModel.(Model).transcriptOpts.showReasoning {
+		t.Error("pressing t again should hide reasoning")
+	}
+}
+
 func TestFormatDiffSummary_AddedOnly(t *testing.T) {
 	// When old_string is empty, should show "Added N lines"
 	result := formatDiffSummary("", "line1\nline2\nline3")
@@ -593,39 +713,31 @@ func TestFormatDiff_SkipsEmptyLinesButCountsThem(t *testing.T) {
 }
 
 func TestFormatToolUse_TodoWrite(t *testing.T) {
-	// Create a mock entry with TodoWrite tool use
-	entry := claude.Entry{
-		Type: "assistant",
-		Message: claude.Message{
-			Content: []interface{}{
+	// Create a mock TodoWrite tool call
+	call := transcript.ToolCall{
+		Name: "TodoWrite",
+		Input: map[string]interface{}{
+			"todos": []interface{}{
+				map[string]interface{}{
+					"content":    "First task",
+					"status":     "completed",
+					"activeForm": "Doing first task",
+				},
 				map[string]interface{}{
-					"type": "tool_use",
-					"name": "TodoWrite",
-					"input": map[string]interface{}{
-						"todos": []interface{}{
-							map[string]interface{}{
-								"content":    "First task",
-								"status":     "completed",
-								"activeForm": "Doing first task",
-							},
-							map[string]interface{}{
-								"content":    "Second task",
-								"status":     "in_progress",
-								"activeForm": "Doing second task",
-							},
-							map[string]interface{}{
-								"content":    "Third task",
-								"status":     "pending",
-								"activeForm": "Doing third task",
-							},
-						},
-					},
+					"content":    "Second task",
+					"status":     "in_progress",
+					"activeForm": "Doing second task",
+				},
+				map[string]interface{}{
+					"content":    "Third task",
+					"status":     "pending",
+					"activeForm": "Doing third task",
 				},
 			},
 		},
 	}
 
-	result := formatToolUse(entry, "TodoWrite", 80)
+	result := formatToolUse(call, 80)
 	output := strings.Join(result, "\n")
 	stripped := stripANSI(output)
 
@@ -659,22 +771,14 @@ func TestFormatToolUse_TodoWrite(t *testing.T) {
 
 func TestFormatToolUse_TodoWriteEmpty(t *testing.T) {
 	// Test TodoWrite with no todos
-	entry := claude.Entry{
-		Type: "assistant",
-		Message: claude.Message{
-			Content: []interface{}{
-				map[string]interface{}{
-					"type": "tool_use",
-					"name": "TodoWrite",
-					"input": map[string]interface{}{
-						"todos": []interface{}{},
-					},
-				},
-			},
+	call := transcript.ToolCall{
+		Name: "TodoWrite",
+		Input: map[string]interface{}{
+			"todos": []interface{}{},
 		},
 	}
 
-	result := formatToolUse(entry, "TodoWrite", 80)
+	result := formatToolUse(call, 80)
 	output := strings.Join(result, "\n")
 	stripped := stripANSI(output)
 
@@ -803,6 +907,24 @@ func TestRenderSidebarShowsDescription(t *testing.T) {
 	}
 }
 
+func TestRenderSidebarShowsFailedIndicator(t *testing.T) {
+	now := time.Now()
+	agents := []agent.Agent{
+		{ID: "abc123", Name: "broken-9c4f", Source: agent.SourceCodex, Status: agent.StatusFailed, LastActivity: now.Add(-1 * time.Minute)},
+		{ID: "def456", Name: "fine-3b7a", Source: agent.SourceCodex, Status: agent.StatusSucceeded, LastActivity: now.Add(-2 * time.Minute)},
+	}
+	m := createModelWithAgents(agents, 80, 24)
+
+	content := m.renderSidebarContent(20, 10)
+
+	if !strings.Contains(content, "\u2717") {
+		t.Errorf("expected failed agent to show \u2717 indicator, got: %s", content)
+	}
+	if strings.Count(content, "\u2717") != 1 {
+		t.Errorf("expected only the failed agent to show \u2717, got: %s", content)
+	}
+}
+
 func TestViewShowsDescriptionAndIDInRightPanel(t *testing.T) {
 	agents := []agent.Agent{
 		{ID: "abc12345", Name: "Fix login bug", TranscriptPath: "/tmp/test.jsonl", LastActivity: time.Now()},
@@ -920,7 +1042,7 @@ func TestNavigationSkipsHeaders(t *testing.T) {
 
 	m := Model{
 		channels:         channels,
-		transcripts:      make(map[string][]claude.Entry),
+		transcripts:      make(map[string][]transcript.Event),
 		expandedChannels: make(map[int]bool),
 		selectedIdx:      1, // Start on first agent (after first header)
 		focusedPanel:     panelLeft,
@@ -1025,26 +1147,19 @@ func TestLoadTranscriptCmd_UsesCodexParserForCodexAgent(t *testing.T) {
 	}
 
 	// Execute the command
-	cmd := loadTranscriptCmd(codexAgent)
+	cmd := loadTranscriptCmd(codexAgent, transcriptTail{})
 	msg := cmd()
 
 	// Should succeed and return a transcriptMsg, not an error
 	switch m := msg.(type) {
 	case transcriptMsg:
-		// For Codex agents, we should get entries (not be empty)
-		if len(m.entries) == 0 {
+		// For Codex agents, we should get events (not be empty)
+		if len(m.events) == 0 {
 			t.Error("Codex agent transcript should not be empty - parser may be wrong")
 		}
-		// Verify we got the expected content by checking if any entry has content
-		hasContent := false
-		for _, e := range m.entries {
-			if e.TextContent() != "" {
-				hasContent = true
-				break
-			}
-		}
-		if !hasContent {
-			t.Error("Codex agent transcript entries have no text content - parser may be wrong")
+		// Verify we got the expected content
+		if transcript.LastText(m.events) != "Here is my response" {
+			t.Error("Codex agent transcript has no reply text - parser may be wrong")
 		}
 	case errMsg:
 		t.Errorf("loadTranscriptCmd returned error: %v", m)
@@ -1074,18 +1189,18 @@ func TestLoadTranscriptCmd_UsesClaudeParserForClaudeAgent(t *testing.T) {
 	}
 
 	// Execute the command
-	cmd := loadTranscriptCmd(claudeAgent)
+	cmd := loadTranscriptCmd(claudeAgent, transcriptTail{})
 	msg := cmd()
 
 	// Should succeed and return a transcriptMsg
 	switch m := msg.(type) {
 	case transcriptMsg:
-		if len(m.entries) == 0 {
-			t.Error("Claude agent transcript should not be empty")
+		if len(m.events) == 0 {
+			t.Fatal("Claude agent transcript should not be empty")
 		}
-		// First entry should be user type
-		if m.entries[0].Type != "user" {
-			t.Errorf("first entry type = %q, want 'user'", m.entries[0].Type)
+		// First event should be the user prompt
+		if _, ok := m.events[0].(transcript.UserPrompt); !ok {
+			t.Errorf("first event = %T, want UserPrompt", m.events[0])
 		}
 	case errMsg:
 		t.Errorf("loadTranscriptCmd returned error: %v", m)
@@ -1094,36 +1209,6 @@ func TestLoadTranscriptCmd_UsesClaudeParserForClaudeAgent(t *testing.T) {
 	}
 }
 
-func TestConvertCodexEntries_ConsistentContentType(t *testing.T) {
-	// All entry types from convertCodexEntries should have []interface{} Content type
-	// This ensures consistent handling in formatTranscript and Entry methods
-	entries := []codex.TranscriptEntry{
-		{Type: "message", Content: "Hello world"},
-		{Type: "reasoning", Content: "Thinking..."},
-		{Type: "tool", Content: "[tool: Bash]"},
-		{Type: "tool_output", Content: "command output here"},
-	}
-
-	converted := convertCodexEntries(entries)
-
-	if len(converted) != 4 {
-		t.Fatalf("expected 4 entries, got %d", len(converted))
-	}
-
-	for i, entry := range converted {
-		// All Content should be []interface{}, not string
-		content := entry.Message.Content
-		switch content.(type) {
-		case []interface{}:
-			// This is correct
-		case string:
-			t.Errorf("entry %d (%s) has string Content, expected []interface{}", i, entries[i].Type)
-		default:
-			t.Errorf("entry %d (%s) has unexpected Content type: %T", i, entries[i].Type, content)
-		}
-	}
-}
-
 func TestModel_FindAgentIndexByID(t *testing.T) {
 	now := time.Now()
 	m := Model{
@@ -1266,7 +1351,7 @@ func TestModel_PreservesSelectionOnRefresh(t *testing.T) {
 		},
 		selectedIdx:     2, // agent-b is at index 2 (after header and agent-a)
 		selectedAgentID: "agent-b",
-		transcripts:     make(map[string][]claude.Entry),
+		transcripts:     make(map[string][]transcript.Event),
 	}
 
 	// Simulate refresh where agents swap positions
@@ -1308,7 +1393,7 @@ func TestModel_SelectsNearestWhenAgentDisappears(t *testing.T) {
 		},
 		selectedIdx:     2, // agent-b
 		selectedAgentID: "agent-b",
-		transcripts:     make(map[string][]claude.Entry),
+		transcripts:     make(map[string][]transcript.Event),
 	}
 
 	// Refresh with agent-b gone
@@ -1338,3 +1423,140 @@ func TestModel_SelectsNearestWhenAgentDisappears(t *testing.T) {
 		t.Error("selectedAgentID should have been updated from agent-b")
 	}
 }
+
+func TestUpdate_KillKeyAsksForConfirmation(t *testing.T) {
+	agents := []agent.Agent{{
+		ID:           "impl-1",
+		Name:         "impl-1",
+		Source:       agent.SourceCodex,
+		PID:          4242,
+		Status:       agent.StatusRunning,
+		LastActivity: time.Now(),
+	}}
+	m := createModelWithAgents(agents, 80, 40)
+	m.focusedPanel = panelLeft
+	m.selectedIdx = 1 // Index 0 is the channel header
+
+	press := func(m Model, r rune) (Model, tea.Cmd) {
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		return newModel.(Model), cmd
+	}
+
+	m, _ = press(m, 'K')
+	if m.confirmKill == nil || m.confirmKill.Name != "impl-1" {
+		t.Fatalf("confirmKill = %v, want impl-1", m.confirmKill)
+	}
+	if !strings.Contains(m.View(), "Kill impl-1? (y/n)") {
+		t.Error("status bar should show the kill confirmation prompt")
+	}
+
+	// Any key other than y cancels
+	m, cmd := press(m, 'n')
+	if m.confirmKill != nil || cmd != nil {
+		t.Error("pressing n should cancel the kill without a command")
+	}
+
+	m, _ = press(m, 'K')
+	m, cmd = press(m, 'y')
+	if m.confirmKill != nil {
+		t.Error("confirmKill should be cleared after confirming")
+	}
+	if cmd == nil {
+		t.Error("confirming should return a kill command")
+	}
+}
+
+func TestUpdate_KillKeyIgnoresClaudeAgents(t *testing.T) {
+	agents := createTestAgents(1)
+	m := createModelWithAgents(agents, 80, 40)
+	m.focusedPanel = panelLeft
+	m.selectedIdx = 1
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	updated := newModel.(Model)
+
+	if updated.confirmKill != nil {
+		t.Error("Claude subagents june didn't spawn should not be offered for killing")
+	}
+	if updated.statusMsg == "" {
+		t.Error("expected a status message explaining why the kill was refused")
+	}
+}
+
+func TestUpdate_KillResultShowsStatus(t *testing.T) {
+	m := createModelWithAgents(createTestAgents(1), 80, 40)
+
+	newModel, _ := m.Update(killResultMsg{name: "impl-1"})
+	if got := newModel.(Model).statusMsg; got != "Killed impl-1" {
+		t.Errorf("statusMsg = %q, want %q", got, "Killed impl-1")
+	}
+
+	newModel, _ = m.Update(killResultMsg{name: "impl-1", err: fmt.Errorf("not running")})
+	if got := newModel.(Model).statusMsg; !strings.Contains(got, "not running") {
+		t.Errorf("statusMsg = %q, want it to contain the error", got)
+	}
+}
+
+func TestFormatSpawnHeader(t *testing.T) {
+	if got := formatSpawnHeader(&agent.Agent{}, 80); got != "" {
+		t.Errorf("formatSpawnHeader() for Claude agent = %q, want empty", got)
+	}
+
+	a := &agent.Agent{Task: "fix\nthe tests", Options: "model=o3"}
+	got := formatSpawnHeader(a, 80)
+	if !strings.Contains(got, "Task: fix the tests") {
+		t.Errorf("header should contain the task on one line, got %q", got)
+	}
+	if !strings.Contains(got, "Options: model=o3") {
+		t.Errorf("header should contain the options, got %q", got)
+	}
+}
+
+func TestSidebarItems_GroupsRetriesUnderOriginal(t *testing.T) {
+	now := time.Now()
+	// Sorted most recent first, so the retries come before their original
+	agents := []agent.Agent{
+		{ID: "r2", Name: "impl-cccc", RetryOf: "impl-aaaa", LastActivity: now},
+		{ID: "other", Name: "other-dddd", LastActivity: now.Add(-1 * time.Minute)},
+		{ID: "r1", Name: "impl-bbbb", RetryOf: "impl-aaaa", LastActivity: now.Add(-2 * time.Minute)},
+		{ID: "orig", Name: "impl-aaaa", LastActivity: now.Add(-3 * time.Minute)},
+	}
+	m := createModelWithAgents(agents, 80, 24)
+
+	var got []string
+	var retries []bool
+	for _, item := range m.sidebarItems() {
+		if item.agent != nil {
+			got = append(got, item.agent.ID)
+			retries = append(retries, item.isRetry)
+		}
+	}
+
+	want := []string{"other", "orig", "r2", "r1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	wantRetries := []bool{false, false, true, true}
+	for i := range wantRetries {
+		if retries[i] != wantRetries[i] {
+			t.Errorf("item %d isRetry = %v, want %v", i, retries[i], wantRetries[i])
+		}
+	}
+
+	content := m.renderSidebarContent(20, 10)
+	if strings.Count(content, "↳") != 2 {
+		t.Errorf("expected two retry markers, got: %s", content)
+	}
+}
+
+func TestSidebarItems_RetryWithoutVisibleOriginal(t *testing.T) {
+	agents := []agent.Agent{
+		{ID: "r1", Name: "impl-bbbb", RetryOf: "gone-aaaa", LastActivity: time.Now()},
+	}
+	m := createModelWithAgents(agents, 80, 24)
+
+	items := m.sidebarItems()
+	if len(items) != 2 || items[1].isRetry {
+		t.Errorf("retry of a missing agent should be listed normally, got %+v", items)
+	}
+}
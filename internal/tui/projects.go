@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleAllProjects switches the sidebar between the repo's agents and the
+// agents of every project, rescanning and watching the matching dirs.
+func (m *Model) toggleAllProjects() tea.Cmd {
+	if m.allProjects && m.basePath == "" {
+		m.statusMsg = "Not in a git repository: showing all projects"
+		return nil
+	}
+	m.allProjects = !m.allProjects
+	m.expandedChannels = make(map[int]bool) // Channel indexes change
+	m.sidebarOffset = 0
+
+	var cmds []tea.Cmd
+	if m.watcher != nil {
+		m.watcher.Close()
+		m.watcher = nil
+		// Without file notifications the TUI falls back to polling
+		if m.StartWatching() == nil {
+			cmds = append(cmds, waitForChangesCmd(m.watcher))
+		}
+	}
+	cmds = append(cmds, m.scanCmd())
+	return tea.Batch(cmds...)
+}
+
+// shortenHome replaces the home directory at the start of path with "~".
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(path, home+"/"); ok {
+		return "~/" + rest
+	}
+	return path
+}
+
+// truncateLeft shortens s to maxWidth by cutting its start, which for paths
+// keeps the most specific part.
+func truncateLeft(s string, maxWidth int) string {
+	width := lipgloss.Width(s)
+	if width <= maxWidth {
+		return s
+	}
+	if maxWidth <= 1 {
+		return ansi.Truncate(s, maxWidth, "")
+	}
+	return "…" + ansi.TruncateLeft(s, width-maxWidth+1, "")
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+)
+
+// allProjectsModel returns a model in all-projects mode with two projects,
+// the first with two channels.
+func allProjectsModel() Model {
+	now := time.Now()
+	m := createModelWithAgents(nil, 80, 30)
+	m.allProjects = true
+	m.channels = []agent.Channel{
+		{Name: "api:main", Project: "/code/api", Agents: []agent.Agent{{ID: "a1", LastActivity: now}}},
+		{Name: "api:fix", Project: "/code/api", Agents: []agent.Agent{{ID: "a2", LastActivity: now}}},
+		{Name: "web:main", Project: "/code/web", Agents: []agent.Agent{{ID: "w1", LastActivity: now}}},
+	}
+	return m
+}
+
+func TestSidebarItems_ProjectHeaders(t *testing.T) {
+	m := allProjectsModel()
+
+	var got []string
+	for _, item := range m.sidebarItems() {
+		switch {
+		case item.isProject:
+			got = append(got, "project "+item.channelName)
+		case item.isHeader:
+			got = append(got, "channel "+item.channelName)
+		case item.agent != nil:
+			got = append(got, "agent "+item.agent.ID)
+		}
+	}
+	want := []string{
+		"project /code/api", "channel api:main", "agent a1", "channel api:fix", "agent a2",
+		"project /code/web", "channel web:main", "agent w1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("sidebar items:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	m.allProjects = false
+	for _, item := range m.sidebarItems() {
+		if item.isProject {
+			t.Fatal("project headers should only be shown in all-projects mode")
+		}
+	}
+}
+
+func TestSidebarItems_ProjectHeadersSkipFilteredProjects(t *testing.T) {
+	m := allProjectsModel()
+	m.filter = sidebarFilter{text: "w1"}
+
+	var projects []string
+	for _, item := range m.sidebarItems() {
+		if item.isProject {
+			projects = append(projects, item.channelName)
+		}
+	}
+	if len(projects) != 1 || projects[0] != "/code/web" {
+		t.Errorf("project headers = %v, want only /code/web", projects)
+	}
+}
+
+func TestRenderSidebarContent_ProjectHeaders(t *testing.T) {
+	m := allProjectsModel()
+	lines := strings.Split(stripANSI(m.renderSidebarContent(30, 20)), "\n")
+
+	// The channel under a project header follows it directly; the next
+	// project is separated from the previous channel's agents
+	if !strings.Contains(lines[0], "/code/api") || !strings.Contains(lines[1], "api:main") {
+		t.Errorf("expected the project header then its first channel, got %q, %q", lines[0], lines[1])
+	}
+	for i, line := range lines {
+		if strings.Contains(line, "/code/web") {
+			if strings.TrimSpace(lines[i-1]) != "" {
+				t.Errorf("expected a blank line before the second project, got %q", lines[i-1])
+			}
+			return
+		}
+	}
+	t.Errorf("second project header not rendered:\n%s", strings.Join(lines, "\n"))
+}
+
+func TestToggleAllProjects(t *testing.T) {
+	m := createModelWithAgents(createTestAgents(2), 80, 30)
+	m.expandedChannels[0] = true
+
+	if cmd := m.toggleAllProjects(); cmd == nil {
+		t.Error("switching modes should rescan")
+	}
+	if !m.allProjects {
+		t.Fatal("expected all-projects mode")
+	}
+	if len(m.expandedChannels) != 0 {
+		t.Error("switching modes should collapse channels, whose indexes change")
+	}
+
+	m.toggleAllProjects()
+	if m.allProjects {
+		t.Error("expected the repo's agents again")
+	}
+}
+
+func TestToggleAllProjects_OutsideRepo(t *testing.T) {
+	m := NewModel("/test/claude/projects", "", "")
+	m.allProjects = true
+
+	if cmd := m.toggleAllProjects(); cmd != nil {
+		t.Error("expected no rescan without a repo to switch to")
+	}
+	if !m.allProjects || m.statusMsg == "" {
+		t.Errorf("expected to stay in all-projects mode with a message, got %v, %q", m.allProjects, m.statusMsg)
+	}
+}
+
+func TestShortenHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	for path, want := range map[string]string{
+		filepath.Join(home, "code", "june"): "~/code/june",
+		home:                                "~",
+		"/elsewhere/june":                   "/elsewhere/june",
+	} {
+		if got := shortenHome(path); got != want {
+			t.Errorf("shortenHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestTruncateLeft(t *testing.T) {
+	if got := truncateLeft("~/code/june", 20); got != "~/code/june" {
+		t.Errorf("short strings should be unchanged, got %q", got)
+	}
+	if got := truncateLeft("~/code/june", 6); got != "…/june" {
+		t.Errorf("truncateLeft() = %q, want %q", got, "…/june")
+	}
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind is what a query typed into the status bar prompt is for.
+type promptKind int
+
+const (
+	promptNone   promptKind = iota
+	promptSearch            // Search all transcripts
+	promptFind              // Find in the displayed transcript
+	promptFilter            // Filter the sidebar
+)
+
+// updatePrompt handles a key typed into the prompt.
+func (m Model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		if m.prompt == promptFilter {
+			m.prompt = promptNone
+			return m, m.setFilter(m.filterBefore)
+		}
+		m.prompt = promptNone
+	case tea.KeyEnter:
+		kind := m.prompt
+		m.prompt = promptNone
+		if kind == promptFilter {
+			return m, nil // Already applied while typing
+		}
+		if strings.TrimSpace(m.promptInput) == "" {
+			return m, nil
+		}
+		if kind == promptFind {
+			m.startFind(m.promptInput)
+			return m, nil
+		}
+		query := strings.TrimSpace(m.promptInput)
+		m.find = nil
+		m.renderViewportContent()
+		m.statusMsg = "Searching for " + query + "..."
+		return m, searchCmd(m.codexDB, m.allAgents(), query)
+	case tea.KeyTab:
+		if m.prompt == promptFilter {
+			f := m.filter
+			f.source = nextValue(filterSources, f.source)
+			return m, m.setFilter(f)
+		}
+	case tea.KeyShiftTab:
+		if m.prompt == promptFilter {
+			f := m.filter
+			f.status = nextValue(filterStatuses, f.status)
+			return m, m.setFilter(f)
+		}
+	case tea.KeyCtrlR:
+		if m.prompt == promptFind {
+			m.findOpts.regex = !m.findOpts.regex
+		}
+	case tea.KeyCtrlT:
+		if m.prompt == promptFind {
+			m.findOpts.ignoreCase = !m.findOpts.ignoreCase
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.promptInput); len(r) > 0 {
+			m.promptInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.promptInput += " "
+	case tea.KeyRunes:
+		m.promptInput += string(msg.Runes)
+	}
+	if m.prompt == promptFilter && m.promptInput != m.filter.text {
+		f := m.filter
+		f.text = m.promptInput
+		return m, m.setFilter(f)
+	}
+	return m, nil
+}
+
+// promptStatus renders the prompt for the status bar.
+func (m Model) promptStatus() string {
+	switch m.prompt {
+	case promptSearch:
+		return "Search all transcripts: /" + m.promptInput + "█"
+	case promptFilter:
+		return fmt.Sprintf("Filter: %s█ | Tab: source (%s) | Shift+Tab: status (%s) | Enter: done | Esc: cancel",
+			m.promptInput, orAll(m.filter.source), orAll(m.filter.status))
+	}
+	var modes []string
+	if m.findOpts.regex {
+		modes = append(modes, "regex")
+	}
+	if m.findOpts.ignoreCase {
+		modes = append(modes, "ignore case")
+	}
+	label := "Find"
+	if len(modes) > 0 {
+		label += " (" + strings.Join(modes, ", ") + ")"
+	}
+	return label + ": /" + m.promptInput + "█ | ^R: regex | ^T: ignore case"
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/db"
+	"github.com/sky-xo/june/internal/search"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchResultMsg carries the agents whose transcripts match a search.
+type searchResultMsg struct {
+	query    string
+	agentIDs []string // Best match first, each agent once
+	err      error
+}
+
+// searchState is an active search: the agents it matched and which match
+// is shown.
+type searchState struct {
+	query    string
+	agentIDs []string // Matching agents, best match first
+	hit      int      // Index into agentIDs of the agent shown
+	line     int      // Content line of the match shown, -1 if none yet
+	pending  int      // Scroll to the first (1) or last (-1) match once the transcript loads, 0 if not waiting
+}
+
+// searchCmd indexes the agents' transcripts and searches them in the
+// background.
+func searchCmd(database *db.DB, agents []agent.Agent, query string) tea.Cmd {
+	return func() tea.Msg {
+		msg := searchResultMsg{query: query}
+		if database == nil {
+			msg.err = fmt.Errorf("agent database unavailable")
+			return msg
+		}
+		if msg.err = search.Update(database, agents); msg.err != nil {
+			return msg
+		}
+		hits, err := search.Search(database, search.Query{Text: query})
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+
+		shown := make(map[string]bool, len(agents))
+		for _, a := range agents {
+			shown[a.ID] = true
+		}
+		seen := make(map[string]bool)
+		for _, h := range hits {
+			// The index also holds agents of other repos
+			if shown[h.AgentID] && !seen[h.AgentID] {
+				seen[h.AgentID] = true
+				msg.agentIDs = append(msg.agentIDs, h.AgentID)
+			}
+		}
+		return msg
+	}
+}
+
+// allAgents returns the agents of every channel.
+func (m Model) allAgents() []agent.Agent {
+	var agents []agent.Agent
+	for _, ch := range m.channels {
+		agents = append(agents, ch.Agents...)
+	}
+	return agents
+}
+
+// applySearchResult starts showing the matches of a finished search.
+func (m *Model) applySearchResult(msg searchResultMsg) tea.Cmd {
+	m.statusMsg = ""
+	switch {
+	case msg.err != nil:
+		m.statusMsg = "Search failed: " + msg.err.Error()
+		return nil
+	case len(msg.agentIDs) == 0:
+		m.search = nil
+		m.statusMsg = "No matches for " + msg.query
+		return nil
+	}
+	m.search = &searchState{query: msg.query, agentIDs: msg.agentIDs, line: -1}
+	m.find = nil
+	return m.showSearchHit(0, 1)
+}
+
+// showSearchHit selects the agent of the hit'th search result, expanding its
+// channel if the agent is hidden, and scrolls its transcript to its first
+// (dir 1) or last (dir -1) match once loaded.
+func (m *Model) showSearchHit(hit, dir int) tea.Cmd {
+	s := m.search
+	s.hit = hit
+	s.line = -1
+	agentID := s.agentIDs[hit]
+
+	idx, found := m.findAgentIndexByID(agentID)
+	if !found {
+		for ci, ch := range m.channels {
+			for _, a := range ch.Agents {
+				if a.ID == agentID {
+					m.expandedChannels[ci] = true
+				}
+			}
+		}
+		if idx, found = m.findAgentIndexByID(agentID); !found {
+			return nil // Gone since the search ran
+		}
+	}
+
+	m.selectedIdx = idx
+	m.selectedAgentID = agentID
+	m.lastNavWasKeyboard = true
+	m.ensureSelectedVisible()
+	a := m.SelectedAgent()
+	m.lastViewedAgent = a
+	s.pending = dir
+	return loadTranscriptCmd(*a, m.tails[a.ID])
+}
+
+// scrollToPendingSearchMatch scrolls to the match a search hit is waiting to
+// show, if agentID's transcript is the one it waits for.
+func (m *Model) scrollToPendingSearchMatch(agentID string) {
+	s := m.search
+	if s == nil || s.pending == 0 || s.agentIDs[s.hit] != agentID {
+		return
+	}
+	lines := m.searchMatchLines()
+	dir := s.pending
+	s.pending = 0
+	if len(lines) == 0 {
+		return // Matched text that isn't displayed, such as a tool input
+	}
+	if dir > 0 {
+		m.scrollToSearchLine(lines[0])
+	} else {
+		m.scrollToSearchLine(lines[len(lines)-1])
+	}
+}
+
+// nextSearchMatch moves to the next (dir 1) or previous (dir -1) match,
+// continuing in the next or previous matching agent's transcript.
+func (m *Model) nextSearchMatch(dir int) tea.Cmd {
+	s := m.search
+	if s == nil {
+		return nil
+	}
+	if m.lastViewedAgent == nil || m.lastViewedAgent.ID != s.agentIDs[s.hit] {
+		return m.showSearchHit(s.hit, 1) // Navigated away; go back
+	}
+
+	lines := m.searchMatchLines()
+	if dir > 0 {
+		for _, line := range lines {
+			if line > s.line {
+				m.scrollToSearchLine(line)
+				return nil
+			}
+		}
+	} else {
+		for i := len(lines) - 1; i >= 0; i-- {
+			if lines[i] < s.line || s.line < 0 {
+				m.scrollToSearchLine(lines[i])
+				return nil
+			}
+		}
+	}
+
+	hit := (s.hit + dir + len(s.agentIDs)) % len(s.agentIDs)
+	return m.showSearchHit(hit, dir)
+}
+
+// scrollToSearchLine shows content line in the middle of the transcript.
+func (m *Model) scrollToSearchLine(line int) {
+	m.search.line = line
+	m.centerLine(line)
+}
+
+// centerLine scrolls the transcript so content line is in the middle, as
+// far as the content allows.
+func (m *Model) centerLine(line int) {
+	m.viewport.SetYOffset(line - m.viewport.Height/2)
+}
+
+// searchMatchLines returns the transcript lines containing every search
+// term, ignoring case, or failing that any of them.
+func (m Model) searchMatchLines() []int {
+	var terms []string
+	for _, term := range search.Terms(m.search.query) {
+		if t := strings.TrimSuffix(term, "*"); t != "" {
+			terms = append(terms, strings.ToLower(t))
+		}
+	}
+
+	var all, some []int
+	for i, line := range m.contentLines {
+		text := strings.ToLower(line.String())
+		matched := 0
+		for _, term := range terms {
+			if strings.Contains(text, term) {
+				matched++
+			}
+		}
+		if matched > 0 {
+			some = append(some, i)
+			if matched == len(terms) {
+				all = append(all, i)
+			}
+		}
+	}
+	if len(all) > 0 {
+		return all
+	}
+	return some
+}
+
+// searchStatus describes the active search for the status bar.
+func (m Model) searchStatus() string {
+	s := m.search
+	return fmt.Sprintf("Search %q: agent %d/%d | n/N: next/prev | Esc: clear", s.query, s.hit+1, len(s.agentIDs))
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// userLines returns Claude user lines with the given texts.
+func userLines(texts ...string) string {
+	var b strings.Builder
+	for _, text := range texts {
+		fmt.Fprintf(&b, `{"type":"user","message":{"role":"user","content":%q}}`+"\n", text)
+	}
+	return b.String()
+}
+
+// runCmd runs cmd and feeds the resulting message back into m.
+func runCmd(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	newModel, _ := m.Update(cmd())
+	return newModel.(Model)
+}
+
+func TestUpdate_SlashTypesSearchQuery(t *testing.T) {
+	m := createModelWithAgents(createTestAgents(1), 80, 40)
+
+	var model tea.Model = m
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'/'}},
+		{Type: tea.KeyRunes, Runes: []rune("db.go")},
+		{Type: tea.KeySpace},
+		{Type: tea.KeyRunes, Runes: []rune("jx")},
+		{Type: tea.KeyBackspace},
+	} {
+		model, _ = model.Update(key)
+	}
+	m = model.(Model)
+	if m.prompt != promptSearch || m.promptInput != "db.go j" {
+		t.Fatalf("prompt = %v, input = %q; want the typed query", m.prompt, m.promptInput)
+	}
+	// Keys go to the prompt, not the panels
+	if !strings.Contains(m.View(), "/db.go j") {
+		t.Error("status bar should show the query being typed")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(Model).prompt != promptNone {
+		t.Error("Esc should close the prompt")
+	}
+}
+
+func TestSearch_JumpsToHitsAndSteps(t *testing.T) {
+	dir := t.TempDir()
+	filler := make([]string, 40)
+	for i := range filler {
+		filler[i] = fmt.Sprintf("filler %d", i)
+	}
+
+	// first-agent's only match is deep in its transcript; old-agent is
+	// hidden under the expander until the search jumps to it
+	first := agent.Agent{ID: "a1", Source: agent.SourceClaude, LastActivity: time.Now(), TranscriptPath: filepath.Join(dir, "agent-a1.jsonl")}
+	old := agent.Agent{ID: "a2", Source: agent.SourceClaude, LastActivity: time.Now().Add(-48 * time.Hour), TranscriptPath: filepath.Join(dir, "agent-a2.jsonl")}
+	appendFile(t, first.TranscriptPath, userLines(append(filler, "fix the flaky Needle test")...))
+	appendFile(t, old.TranscriptPath, userLines("needle one", "needle two"))
+
+	m := createModelWithAgents([]agent.Agent{first, old}, 80, 20)
+	m.updateViewportDimensions()
+
+	m.applySearchResult(searchResultMsg{query: "needle", agentIDs: []string{"a1", "a2"}})
+	m = runCmd(t, m, loadTranscriptCmd(m.channels[0].Agents[0], transcriptTail{}))
+	if m.lastViewedAgent.ID != "a1" {
+		t.Fatalf("viewing %s, want the best hit a1", m.lastViewedAgent.ID)
+	}
+	if line := m.contentLines[m.search.line].String(); !strings.Contains(line, "Needle") {
+		t.Fatalf("search line %d is %q, want the match", m.search.line, line)
+	}
+	if m.search.line < m.viewport.YOffset || m.search.line >= m.viewport.YOffset+m.viewport.Height {
+		t.Errorf("match on line %d not visible at offset %d", m.search.line, m.viewport.YOffset)
+	}
+
+	// n moves on to the next agent, expanding its channel
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = runCmd(t, newModel.(Model), cmd)
+	if a := m.SelectedAgent(); a == nil || a.ID != "a2" {
+		t.Fatalf("n should select a2, got %+v", a)
+	}
+	if !strings.Contains(m.contentLines[m.search.line].String(), "needle one") {
+		t.Errorf("n should show a2's first match, got line %d", m.search.line)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = newModel.(Model)
+	if !strings.Contains(m.contentLines[m.search.line].String(), "needle two") {
+		t.Errorf("n should step to a2's next match, got line %d", m.search.line)
+	}
+
+	// N steps back, then wraps to the previous agent's last match
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	newModel, cmd = newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	m = runCmd(t, newModel.(Model), cmd)
+	if m.lastViewedAgent.ID != "a1" || !strings.Contains(m.contentLines[m.search.line].String(), "Needle") {
+		t.Errorf("N should wrap back to a1's match, viewing %s line %d", m.lastViewedAgent.ID, m.search.line)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if newModel.(Model).search != nil {
+		t.Error("Esc should clear the search")
+	}
+}
+
+func TestApplySearchResult_NoMatches(t *testing.T) {
+	m := createModelWithAgents(createTestAgents(1), 80, 40)
+	if cmd := m.applySearchResult(searchResultMsg{query: "nothing"}); cmd != nil {
+		t.Error("no matches should not load a transcript")
+	}
+	if m.search != nil || m.statusMsg != "No matches for nothing" {
+		t.Errorf("search = %+v, status = %q", m.search, m.statusMsg)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"testing"
 
 	"github.com/sky-xo/june/internal/agent"
-	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/transcript"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -99,11 +99,8 @@ func TestModel_ContentLines(t *testing.T) {
 	m.lastViewedAgent = &m.channels[0].Agents[0]
 
 	// Add transcript entries for the selected agent
-	m.transcripts["test-agent"] = []claude.Entry{
-		{
-			Type:    "user",
-			Message: claude.Message{Content: "Hello world"},
-		},
+	m.transcripts["test-agent"] = []transcript.Event{
+		transcript.UserPrompt{Text: "Hello world"},
 	}
 
 	// Call updateViewport to populate contentLines
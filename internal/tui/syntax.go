@@ -3,12 +3,14 @@ package tui
 
 import (
 	"bytes"
+	"html"
 	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
 	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/alecthomas/chroma/v2/styles"
 )
@@ -80,20 +82,12 @@ func syntaxHighlight(content, filePath string) string {
 		return content
 	}
 
-	// Get lexer from file extension
-	lexer := lexers.Match(filePath)
-	if lexer == nil {
-		// Try to detect from filename
-		lexer = lexers.Match(filepath.Base(filePath))
-	}
+	lexer := lexerFor(filePath)
 	if lexer == nil {
 		// No lexer found, return plain content
 		return content
 	}
 
-	// Coalesce runs of identical token types for cleaner output
-	lexer = chroma.Coalesce(lexer)
-
 	// Use custom style based on monokai with white text for regular code
 	// This changes lime/green function/class names to white for better readability
 	style := getCustomStyle()
@@ -123,6 +117,45 @@ func syntaxHighlight(content, filePath string) string {
 	return result
 }
 
+// lexerFor returns the lexer for a file, or nil if its language isn't known.
+func lexerFor(filePath string) chroma.Lexer {
+	if filePath == "" {
+		return nil
+	}
+	// Get lexer from file extension
+	lexer := lexers.Match(filePath)
+	if lexer == nil {
+		// Try to detect from filename
+		lexer = lexers.Match(filepath.Base(filePath))
+	}
+	if lexer == nil {
+		return nil
+	}
+	// Coalesce runs of identical token types for cleaner output
+	return chroma.Coalesce(lexer)
+}
+
+// syntaxHighlightHTML is syntaxHighlight for HTML: it returns content as
+// HTML with inline colors, or just escaped if its language isn't known.
+func syntaxHighlightHTML(content, filePath string) string {
+	lexer := lexerFor(filePath)
+	if content == "" || lexer == nil {
+		return html.EscapeString(content)
+	}
+
+	iterator, err := lexer.Tokenise(nil, content)
+	if err != nil {
+		return html.EscapeString(content)
+	}
+	// Inline styles keep exported pages self-contained
+	formatter := chromahtml.New(chromahtml.PreventSurroundingPre(true))
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, getCustomStyle(), iterator); err != nil {
+		return html.EscapeString(content)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
 // highlightLine applies syntax highlighting to a single line of code.
 // This is a convenience wrapper that handles the common case of highlighting diff lines.
 func highlightLine(line, filePath string) string {
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/transcript"
+)
+
+// transcriptTail records how much of an agent's transcript has been read, so
+// each refresh parses only the lines appended since the previous one.
+type transcriptTail struct {
+	path string
+	pos  transcript.Position
+}
+
+// tailTranscript reads the entries appended to a's transcript since from.
+// If the file was truncated or replaced (e.g. rotated), or the agent's
+// transcript path changed, it is re-read from the start and the result is
+// marked as a reset.
+func tailTranscript(a agent.Agent, from transcriptTail) (transcriptMsg, error) {
+	msg := transcriptMsg{agentID: a.ID, from: from}
+
+	source := a.Source
+	if source == "" {
+		source = agent.SourceClaude
+	}
+	p, err := provider.Get(source)
+	if err != nil {
+		return msg, err
+	}
+
+	pos := from.pos
+	if from.path != a.TranscriptPath {
+		pos = transcript.Position{}
+	}
+	pos, reset, err := transcript.ReadAppended(a.TranscriptPath, pos, func(line []byte) {
+		for _, e := range p.ParseLine(line) {
+			if call, ok := e.(transcript.ToolCall); ok {
+				// Use Claude's tool names, which get rich formatting
+				call.Name, call.Input = p.NormalizeTool(call.Name, call.Input)
+				e = call
+			}
+			msg.events = append(msg.events, e)
+		}
+	})
+	if err != nil {
+		return msg, err
+	}
+
+	msg.reset = reset || from.path != a.TranscriptPath
+	msg.to = transcriptTail{path: a.TranscriptPath, pos: pos}
+	return msg, nil
+}
+
+// applyTranscript merges a tail read into m.transcripts. It returns false,
+// changing nothing, if the read is stale: another read of the same agent
+// was applied after it started.
+func (m *Model) applyTranscript(msg transcriptMsg) bool {
+	if m.tails[msg.agentID] != msg.from {
+		return false
+	}
+	m.tails[msg.agentID] = msg.to
+
+	events := m.transcripts[msg.agentID]
+	if msg.reset {
+		events = nil
+	}
+	// Streamed chunks may continue a message from an earlier read
+	for _, e := range msg.events {
+		events = transcript.Append(events, e)
+	}
+	m.transcripts[msg.agentID] = events
+	return true
+}
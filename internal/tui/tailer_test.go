@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/transcript"
+)
+
+const (
+	claudeUserLine      = `{"type":"user","message":{"role":"user","content":"Hello"}}` + "\n"
+	claudeAssistantLine = `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Hi"}]}}` + "\n"
+)
+
+// tailOnce reads a's transcript from the model's current tail and applies it.
+func tailOnce(t *testing.T, m *Model, a agent.Agent) transcriptMsg {
+	t.Helper()
+	msg, err := tailTranscript(a, m.tails[a.ID])
+	if err != nil {
+		t.Fatalf("tailTranscript: %v", err)
+	}
+	if !m.applyTranscript(msg) {
+		t.Fatal("applyTranscript rejected a fresh read")
+	}
+	return msg
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTailTranscript_ReadsOnlyAppendedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	appendFile(t, path, claudeUserLine)
+	a := agent.Agent{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path}
+	m := NewModel("", "", "")
+
+	msg := tailOnce(t, &m, a)
+	if !msg.reset || len(msg.events) != 1 {
+		t.Fatalf("first read: reset=%v events=%d, want a reset with 1 event", msg.reset, len(msg.events))
+	}
+
+	// A partially written line is left for the next read
+	appendFile(t, path, claudeAssistantLine[:20])
+	msg = tailOnce(t, &m, a)
+	if msg.reset || len(msg.events) != 0 {
+		t.Fatalf("partial line: reset=%v events=%d, want nothing new", msg.reset, len(msg.events))
+	}
+
+	appendFile(t, path, claudeAssistantLine[20:])
+	msg = tailOnce(t, &m, a)
+	if msg.reset || len(msg.events) != 1 {
+		t.Fatalf("completed line: reset=%v events=%d, want 1 appended event", msg.reset, len(msg.events))
+	}
+	if got := len(m.transcripts["a1"]); got != 2 {
+		t.Errorf("transcript has %d events, want 2", got)
+	}
+	if want := int64(len(claudeUserLine) + len(claudeAssistantLine)); m.tails["a1"].pos.Offset != want {
+		t.Errorf("offset = %d, want %d", m.tails["a1"].pos.Offset, want)
+	}
+}
+
+func TestTailTranscript_FinalLineWithoutNewline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	appendFile(t, path, claudeUserLine+claudeAssistantLine[:len(claudeAssistantLine)-1])
+	a := agent.Agent{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path}
+	m := NewModel("", "", "")
+
+	tailOnce(t, &m, a)
+	if got := len(m.transcripts["a1"]); got != 2 {
+		t.Errorf("transcript has %d entries, want 2 (complete JSON without newline)", got)
+	}
+}
+
+func TestTailTranscript_TruncationResets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	appendFile(t, path, claudeUserLine+claudeAssistantLine)
+	a := agent.Agent{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path}
+	m := NewModel("", "", "")
+	tailOnce(t, &m, a)
+
+	if err := os.WriteFile(path, []byte(claudeAssistantLine), 0644); err != nil {
+		t.Fatal(err)
+	}
+	msg := tailOnce(t, &m, a)
+	if !msg.reset {
+		t.Error("truncated file should reset the transcript")
+	}
+	if got := m.transcripts["a1"]; len(got) != 1 {
+		t.Errorf("transcript = %v, want only the assistant reply", got)
+	} else if _, ok := got[0].(transcript.AssistantText); !ok {
+		t.Errorf("transcript = %v, want only the assistant reply", got)
+	}
+}
+
+func TestTailTranscript_RotationResets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent.jsonl")
+	appendFile(t, path, claudeUserLine)
+	a := agent.Agent{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path}
+	m := NewModel("", "", "")
+	tailOnce(t, &m, a)
+
+	// Replace the file with a new, larger one
+	if err := os.Rename(path, filepath.Join(dir, "agent.jsonl.1")); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path, claudeAssistantLine+claudeAssistantLine)
+
+	msg := tailOnce(t, &m, a)
+	if !msg.reset {
+		t.Error("replaced file should reset the transcript")
+	}
+	if got := len(m.transcripts["a1"]); got != 2 {
+		t.Errorf("transcript has %d entries, want 2 from the new file", got)
+	}
+}
+
+func TestApplyTranscript_DropsStaleReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	appendFile(t, path, claudeUserLine)
+	a := agent.Agent{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path}
+	m := NewModel("", "", "")
+
+	// Two reads start from the same tail, e.g. a tick and a navigation
+	first, _ := tailTranscript(a, m.tails["a1"])
+	second, _ := tailTranscript(a, m.tails["a1"])
+
+	if !m.applyTranscript(first) {
+		t.Fatal("first read should apply")
+	}
+	if m.applyTranscript(second) {
+		t.Error("second read started from an outdated tail and should be dropped")
+	}
+	if got := len(m.transcripts["a1"]); got != 1 {
+		t.Errorf("transcript has %d entries, want 1 (no duplicates)", got)
+	}
+}
+
+func TestTailTranscript_JoinsGeminiDeltasAcrossReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gemini.jsonl")
+	appendFile(t, path, `{"type":"init","session_id":"s1"}
+{"type":"message","role":"user","content":"hi"}
+{"type":"message","role":"assistant","content":"Hel","delta":true}
+`)
+	a := agent.Agent{ID: "g1", Source: agent.SourceGemini, TranscriptPath: path}
+	m := NewModel("", "", "")
+	tailOnce(t, &m, a)
+
+	appendFile(t, path, `{"type":"message","role":"assistant","content":"lo","delta":true}
+{"type":"result","status":"success"}
+`)
+	tailOnce(t, &m, a)
+
+	// init, user prompt, the joined reply and the end of the run
+	events := m.transcripts["g1"]
+	if len(events) != 4 {
+		t.Fatalf("transcript has %d events, want 4", len(events))
+	}
+	if got, ok := events[2].(transcript.AssistantText); !ok || got.Text != "Hello" {
+		t.Errorf("assistant message = %+v, want %q", events[2], "Hello")
+	}
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/provider"
+	"github.com/sky-xo/june/internal/transcript"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+// usageCache remembers the usage tallied from each transcript so scans only
+// read what was appended since the last one. Scans run as commands, so it is
+// safe for concurrent use.
+type usageCache struct {
+	mu      sync.Mutex
+	prices  usage.Prices
+	entries map[string]*cachedUsage // By transcript path
+}
+
+// cachedUsage is a transcript's usage as of when it had size and modTime,
+// read up to pos.
+type cachedUsage struct {
+	size    int64
+	modTime time.Time
+	pos     transcript.Position
+	tally   transcript.UsageTally
+	summary usage.Summary
+}
+
+func newUsageCache(prices usage.Prices) *usageCache {
+	return &usageCache{prices: prices, entries: make(map[string]*cachedUsage)}
+}
+
+// setPrices replaces the price table, discarding summaries priced with the
+// old one.
+func (c *usageCache) setPrices(prices usage.Prices) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prices = prices
+	c.entries = make(map[string]*cachedUsage)
+}
+
+// fill sets the usage of every agent in channels. Agents whose transcript
+// can't be read are left at zero. Transcripts no longer in channels are
+// forgotten.
+func (c *usageCache) fill(channels []agent.Channel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seen := make(map[string]bool)
+	for i := range channels {
+		for j := range channels[i].Agents {
+			a := &channels[i].Agents[j]
+			a.Usage = c.summary(*a)
+			seen[a.TranscriptPath] = true
+		}
+	}
+	for path := range c.entries {
+		if !seen[path] {
+			delete(c.entries, path)
+		}
+	}
+}
+
+// summary returns the usage in a's transcript, first reading what was
+// appended to it since it was last read.
+func (c *usageCache) summary(a agent.Agent) usage.Summary {
+	info, err := os.Stat(a.TranscriptPath)
+	if err != nil {
+		return usage.Summary{}
+	}
+	cached, ok := c.entries[a.TranscriptPath]
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.summary
+	}
+	if !ok {
+		cached = &cachedUsage{}
+		c.entries[a.TranscriptPath] = cached
+	}
+
+	source := a.Source
+	if source == "" {
+		source = agent.SourceClaude
+	}
+	p, err := provider.Get(source)
+	if err != nil {
+		return usage.Summary{}
+	}
+	var events []transcript.Event
+	pos, reset, err := transcript.ReadAppended(a.TranscriptPath, cached.pos, func(line []byte) {
+		events = append(events, p.ParseLine(line)...)
+	})
+	if err != nil {
+		return cached.summary
+	}
+	if reset {
+		cached.tally = transcript.UsageTally{}
+	}
+	for _, e := range events {
+		cached.tally.Add(e)
+	}
+	cached.size, cached.modTime, cached.pos = info.Size(), info.ModTime(), pos
+	cached.summary = c.prices.Summarize(cached.tally.Records())
+	return cached.summary
+}
+
+// formatUsage renders usage for the transcript title, e.g. "45.2k tokens ·
+// $0.42". Returns "" if no usage was recorded.
+func formatUsage(s usage.Summary) string {
+	if s.Total() == 0 {
+		return ""
+	}
+	return usage.FormatTokens(s.Total()) + " tokens · " + usage.FormatCost(s)
+}
+
+// formatSidebarUsage renders usage compactly for the sidebar: the cost, or
+// the token count if no model has a price. Returns "" if none was recorded.
+func formatSidebarUsage(s usage.Summary) string {
+	switch {
+	case s.Total() == 0:
+		return ""
+	case s.Unpriced && s.Cost == 0:
+		return usage.FormatTokens(s.Total())
+	}
+	return usage.FormatCost(s)
+}
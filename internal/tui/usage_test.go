@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+	"github.com/sky-xo/june/internal/usage"
+)
+
+func TestUsageCacheFill(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-a1.jsonl")
+	writeUsageLine := func(id string, output int) {
+		appendFile(t, path, fmt.Sprintf(`{"type":"assistant","message":{"id":%q,"model":"claude-sonnet-4-5","role":"assistant","content":[],"usage":{"input_tokens":1000000,"output_tokens":%d}}}`+"\n", id, output))
+	}
+	writeUsageLine("msg_1", 0)
+
+	cache := newUsageCache(usage.Prices{"claude-sonnet-4": {Input: 3, Output: 15}})
+	channels := []agent.Channel{{Agents: []agent.Agent{
+		{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path},
+		{ID: "gone", Source: agent.SourceClaude, TranscriptPath: filepath.Join(t.TempDir(), "missing.jsonl")},
+	}}}
+
+	cache.fill(channels)
+	if got := channels[0].Agents[0].Usage; got.Input != 1_000_000 || got.Cost != 3 {
+		t.Errorf("usage = %+v, want 1M input costing $3", got)
+	}
+	if got := channels[0].Agents[1].Usage; got.Total() != 0 {
+		t.Errorf("missing transcript usage = %+v, want zero", got)
+	}
+
+	// Appending to the transcript is picked up on the next fill
+	time.Sleep(10 * time.Millisecond) // Ensure a new mtime
+	writeUsageLine("msg_2", 1000000)
+	cache.fill(channels)
+	if got := channels[0].Agents[0].Usage; got.Input != 2_000_000 || got.Cost != 21 {
+		t.Errorf("usage after append = %+v, want 2M input, 1M output costing $21", got)
+	}
+	if got := channels[0].Usage(); got.Cost != 21 {
+		t.Errorf("channel usage = %+v, want the agents' total", got)
+	}
+}
+
+func TestUsageCacheFill_ReadsOnlyAppendedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-a1.jsonl")
+	writeUsageLine := func(id string, output int) {
+		appendFile(t, path, fmt.Sprintf(`{"type":"assistant","message":{"id":%q,"model":"claude-sonnet-4-5","role":"assistant","content":[],"usage":{"input_tokens":1000,"output_tokens":%d}}}`+"\n", id, output))
+	}
+	writeUsageLine("msg_1", 10)
+
+	cache := newUsageCache(usage.DefaultPrices())
+	channels := []agent.Channel{{Agents: []agent.Agent{{ID: "a1", Source: agent.SourceClaude, TranscriptPath: path}}}}
+	cache.fill(channels)
+
+	// A later entry of the same message replaces its usage, even when it's
+	// read in a later fill
+	time.Sleep(10 * time.Millisecond)
+	writeUsageLine("msg_1", 50)
+	cache.fill(channels)
+	if got := channels[0].Agents[0].Usage; got.Input != 1000 || got.Output != 50 {
+		t.Errorf("usage = %+v, want msg_1 counted once with its last output", got.Tokens)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.entries[path].pos.Offset; got != info.Size() {
+		t.Errorf("read up to %d, want the end of the transcript at %d", got, info.Size())
+	}
+
+	// Agents that are gone are forgotten
+	cache.fill(nil)
+	if len(cache.entries) != 0 {
+		t.Errorf("cache still holds %d transcripts, want none", len(cache.entries))
+	}
+}
+
+func TestFormatSidebarUsage(t *testing.T) {
+	tests := []struct {
+		s    usage.Summary
+		want string
+	}{
+		{usage.Summary{}, ""},
+		{usage.Summary{Tokens: usage.Tokens{Input: 100}, Cost: 0.42}, "$0.42"},
+		{usage.Summary{Tokens: usage.Tokens{Input: 12_300}, Unpriced: true}, "12.3k"},
+	}
+	for _, tt := range tests {
+		if got := formatSidebarUsage(tt.s); got != tt.want {
+			t.Errorf("formatSidebarUsage(%+v) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestRenderSidebarContent_ShowsUsage(t *testing.T) {
+	agents := createTestAgents(2)
+	agents[0].Name = "explore-auth-flow"
+	agents[0].Usage = usage.Summary{Tokens: usage.Tokens{Input: 1000}, Cost: 1.5}
+	m := createModelWithAgents(agents, 80, 20)
+
+	lines := strings.Split(stripANSI(m.renderSidebarContent(21, 10)), "\n")
+	if !strings.HasSuffix(lines[0], "$1.50") {
+		t.Errorf("channel header = %q, want the channel's cost on the right", lines[0])
+	}
+	if lines[1] != "● explore-auth- $1.50" {
+		t.Errorf("agent line = %q, want a shortened name and the cost on the right", lines[1])
+	}
+	if strings.Contains(lines[2], "$") {
+		t.Errorf("agent without usage = %q, want no cost", lines[2])
+	}
+}
+
+func TestView_TitleShowsUsage(t *testing.T) {
+	agents := createTestAgents(1)
+	agents[0].Usage = usage.Summary{Tokens: usage.Tokens{Input: 40_000, Output: 5_200}, Cost: 0.2}
+	m := createModelWithAgents(agents, 120, 20)
+
+	if view := stripANSI(m.View()); !strings.Contains(view, "45.2k tokens · $0.20") {
+		t.Errorf("view title should show usage, got:\n%s", view)
+	}
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sky-xo/june/internal/claude"
+	"github.com/sky-xo/june/internal/codex"
+	"github.com/sky-xo/june/internal/gemini"
+	"github.com/sky-xo/june/internal/watch"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// pollInterval is how often the TUI refreshes without file notifications.
+	pollInterval = time.Second
+	// watchedPollInterval is the refresh interval with file notifications. It
+	// catches anything they miss and lets activity indicators age out.
+	watchedPollInterval = 5 * time.Second
+	// minScanInterval throttles rescans triggered by file changes, since
+	// active agents write to their transcripts constantly.
+	minScanInterval = time.Second
+)
+
+type (
+	fsChangeMsg watch.Batch
+	scanDueMsg  struct{}
+)
+
+// StartWatching refreshes the TUI on file notifications instead of polling
+// every second. If notifications are unavailable the TUI keeps polling and
+// the error is returned for information only.
+func (m *Model) StartWatching() error {
+	w, err := watch.New(watchRoots(m.claudeProjectsDir, m.basePath, m.allProjects))
+	if err != nil {
+		return err
+	}
+	m.watcher = w
+	return nil
+}
+
+// watchRoots returns the directories agent transcripts and state live in.
+// With allProjects every Claude project dir is watched, not just basePath's.
+func watchRoots(claudeProjectsDir, basePath string, allProjects bool) []watch.Root {
+	roots := []watch.Root{{
+		// Project dirs of the repo and its worktrees, including nested
+		// {session}/subagents dirs
+		Path:      claudeProjectsDir,
+		Recursive: true,
+	}}
+	if !allProjects {
+		roots[0].Match = func(name string) bool {
+			return claude.IsRelatedProjectDir(name, basePath)
+		}
+	}
+	if dir, err := codex.SessionsDir(); err == nil {
+		roots = append(roots, watch.Root{Path: dir, Recursive: true}) // YYYY/MM/DD
+	}
+	if dir, err := gemini.SessionsDir(); err == nil {
+		roots = append(roots, watch.Root{Path: dir})
+	}
+	if dir, err := claude.SessionsDir(); err == nil {
+		roots = append(roots, watch.Root{Path: dir})
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		// june.db: spawned agents starting and finishing
+		roots = append(roots, watch.Root{Path: filepath.Join(home, ".june")})
+	}
+	return roots
+}
+
+// waitForChangesCmd waits for the next batch of file changes.
+func waitForChangesCmd(w *watch.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		batch, ok := <-w.Batches()
+		if !ok {
+			return nil // Watcher closed
+		}
+		return fsChangeMsg(batch)
+	}
+}
+
+// tickInterval returns how often the TUI polls for changes.
+func (m Model) tickInterval() time.Duration {
+	if m.watcher != nil {
+		return watchedPollInterval
+	}
+	return pollInterval
+}
+
+// scanCmd rescans channels and records when, for throttling.
+func (m *Model) scanCmd() tea.Cmd {
+	m.lastScan = time.Now()
+	return scanChannelsCmd(m.claudeProjectsDir, m.basePath, m.repoName, m.allProjects, m.codexDB, m.rules, m.usageCache)
+}
+
+// scheduleScan arranges a rescan no sooner than minScanInterval after the
+// previous one. Returns nil if one is already scheduled.
+func (m *Model) scheduleScan() tea.Cmd {
+	if m.scanScheduled {
+		return nil
+	}
+	m.scanScheduled = true
+	delay := max(minScanInterval-time.Since(m.lastScan), 0)
+	return tea.Tick(delay, func(time.Time) tea.Msg { return scanDueMsg{} })
+}
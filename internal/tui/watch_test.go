@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sky-xo/june/internal/agent"
+)
+
+func TestWatchRoots_MatchesRepoProjectDirs(t *testing.T) {
+	roots := watchRoots("/home/u/.claude/projects", "/code/june", false)
+	projects := roots[0]
+	if projects.Path != "/home/u/.claude/projects" || !projects.Recursive {
+		t.Fatalf("first root = %+v, want the recursive Claude projects dir", projects)
+	}
+	for name, want := range map[string]bool{
+		"-code-june":                   true,
+		"-code-june--worktrees-select": true,
+		"-code-junebug":                false,
+		"-code-other":                  false,
+	} {
+		if got := projects.Match(name); got != want {
+			t.Errorf("Match(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestWatchRoots_AllProjectsWatchesEveryProjectDir(t *testing.T) {
+	roots := watchRoots("/home/u/.claude/projects", "/code/june", true)
+	if roots[0].Match != nil {
+		t.Error("all-projects mode should watch every project dir")
+	}
+}
+
+func TestTickInterval(t *testing.T) {
+	m := NewModel("", "", "")
+	if got := m.tickInterval(); got != pollInterval {
+		t.Errorf("without a watcher tickInterval = %v, want %v", got, pollInterval)
+	}
+}
+
+func TestUpdate_FileChangeThrottlesRescans(t *testing.T) {
+	agents := []agent.Agent{{ID: "a1", Source: agent.SourceClaude, TranscriptPath: "/p/a1.jsonl", LastActivity: time.Now()}}
+	m := createModelWithAgents(agents, 80, 40)
+	m.lastScan = time.Now()
+
+	newModel, cmd := m.Update(fsChangeMsg{Paths: []string{"/p/a1.jsonl"}})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("expected commands for a change to the viewed transcript")
+	}
+	if !m.scanScheduled {
+		t.Fatal("a change should schedule a rescan")
+	}
+
+	// Further changes before the rescan don't schedule another one
+	if cmd := m.scheduleScan(); cmd != nil {
+		t.Error("scheduleScan should return nil while a rescan is pending")
+	}
+
+	newModel, _ = m.Update(scanDueMsg{})
+	m = newModel.(Model)
+	if m.scanScheduled {
+		t.Error("scanScheduled should be cleared once the rescan runs")
+	}
+	if time.Since(m.lastScan) > time.Second {
+		t.Error("lastScan should be updated by the rescan")
+	}
+}
@@ -0,0 +1,175 @@
+// Package usage totals the tokens agents used and estimates what they cost.
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tokens counts tokens by kind.
+type Tokens struct {
+	Input      int64 `json:"input"`       // Uncached input
+	Output     int64 `json:"output"`      // Including reasoning
+	CacheRead  int64 `json:"cache_read"`  // Input read from the prompt cache
+	CacheWrite int64 `json:"cache_write"` // Input written to the prompt cache
+}
+
+// Add returns the sum of t and o.
+func (t Tokens) Add(o Tokens) Tokens {
+	return Tokens{
+		Input:      t.Input + o.Input,
+		Output:     t.Output + o.Output,
+		CacheRead:  t.CacheRead + o.CacheRead,
+		CacheWrite: t.CacheWrite + o.CacheWrite,
+	}
+}
+
+// Total returns the number of tokens of all kinds.
+func (t Tokens) Total() int64 {
+	return t.Input + t.Output + t.CacheRead + t.CacheWrite
+}
+
+// Record is the usage a transcript reports for one model response or turn.
+type Record struct {
+	Time  time.Time // Zero if the transcript doesn't say
+	Model string    // Empty if the transcript doesn't say
+	Tokens
+}
+
+// Summary totals a set of records.
+type Summary struct {
+	Tokens
+	Cost     float64 // Estimated USD cost of the tokens whose model has a price
+	Unpriced bool    // Some tokens are from models without a price
+}
+
+// Add returns the combined totals of s and o.
+func (s Summary) Add(o Summary) Summary {
+	return Summary{
+		Tokens:   s.Tokens.Add(o.Tokens),
+		Cost:     s.Cost + o.Cost,
+		Unpriced: s.Unpriced || o.Unpriced,
+	}
+}
+
+// Price is the USD cost per million tokens of each kind.
+type Price struct {
+	Input      float64 `toml:"input"`
+	Output     float64 `toml:"output"`
+	CacheRead  float64 `toml:"cache_read"`
+	CacheWrite float64 `toml:"cache_write"`
+}
+
+// Cost returns the USD cost of t at price p.
+func (p Price) Cost(t Tokens) float64 {
+	return (float64(t.Input)*p.Input +
+		float64(t.Output)*p.Output +
+		float64(t.CacheRead)*p.CacheRead +
+		float64(t.CacheWrite)*p.CacheWrite) / 1e6
+}
+
+// Prices maps model names to prices. A key also matches the models it is a
+// prefix of, e.g. "claude-sonnet-4" matches "claude-sonnet-4-5-20250929"; the
+// longest matching key wins.
+type Prices map[string]Price
+
+// DefaultPrices returns list prices for the models june agents commonly
+// run. They are estimates and go stale; override them in the config file.
+func DefaultPrices() Prices {
+	return Prices{
+		"claude-opus-4":     {Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75},
+		"claude-opus-4-5":   {Input: 5, Output: 25, CacheRead: 0.5, CacheWrite: 6.25},
+		"claude-sonnet-4":   {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+		"claude-3-7-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+		"claude-haiku-4-5":  {Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25},
+		"claude-3-5-haiku":  {Input: 0.8, Output: 4, CacheRead: 0.08, CacheWrite: 1},
+		"gpt-5":             {Input: 1.25, Output: 10, CacheRead: 0.125},
+		"gpt-5-mini":        {Input: 0.25, Output: 2, CacheRead: 0.025},
+		"gpt-5-nano":        {Input: 0.05, Output: 0.4, CacheRead: 0.005},
+		"gpt-4.1":           {Input: 2, Output: 8, CacheRead: 0.5},
+		"o3":                {Input: 2, Output: 8, CacheRead: 0.5},
+		"o4-mini":           {Input: 1.1, Output: 4.4, CacheRead: 0.275},
+		"codex-mini":        {Input: 1.5, Output: 6, CacheRead: 0.375},
+		"gemini-2.5-pro":    {Input: 1.25, Output: 10, CacheRead: 0.31},
+		"gemini-2.5-flash":  {Input: 0.3, Output: 2.5, CacheRead: 0.075},
+	}
+}
+
+// With returns a copy of p with overrides added or replacing its entries.
+func (p Prices) With(overrides Prices) Prices {
+	merged := make(Prices, len(p)+len(overrides))
+	for model, price := range p {
+		merged[model] = price
+	}
+	for model, price := range overrides {
+		merged[model] = price
+	}
+	return merged
+}
+
+// Lookup returns the price of model.
+func (p Prices) Lookup(model string) (Price, bool) {
+	best, found := "", false
+	for key := range p {
+		if strings.HasPrefix(model, key) && (!found || len(key) > len(best)) {
+			best, found = key, true
+		}
+	}
+	return p[best], found
+}
+
+// Summarize totals records and estimates their cost.
+func (p Prices) Summarize(records []Record) Summary {
+	var s Summary
+	for _, r := range records {
+		s.Tokens = s.Tokens.Add(r.Tokens)
+		if price, ok := p.Lookup(r.Model); ok {
+			s.Cost += price.Cost(r.Tokens)
+		} else if r.Tokens.Total() > 0 {
+			s.Unpriced = true
+		}
+	}
+	return s
+}
+
+// ByDay groups records by the local calendar day they were made, as
+// "2006-01-02". The days are returned sorted.
+func ByDay(records []Record) ([]string, map[string][]Record) {
+	groups := make(map[string][]Record)
+	for _, r := range records {
+		day := r.Time.Local().Format(time.DateOnly)
+		groups[day] = append(groups[day], r)
+	}
+	days := make([]string, 0, len(groups))
+	for day := range groups {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	return days, groups
+}
+
+// FormatTokens renders a token count compactly, e.g. "950", "12.3k", "4.1M".
+func FormatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// FormatCost renders the estimated cost, e.g. "$1.24". A trailing "+" marks
+// a lower bound because some tokens have no price; "?" means none do.
+func FormatCost(s Summary) string {
+	if s.Unpriced && s.Cost == 0 {
+		return "?"
+	}
+	cost := fmt.Sprintf("$%.2f", s.Cost)
+	if s.Unpriced {
+		cost += "+"
+	}
+	return cost
+}
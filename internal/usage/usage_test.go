@@ -0,0 +1,109 @@
+package usage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPricesLookupLongestPrefix(t *testing.T) {
+	prices := Prices{
+		"claude-opus-4":   {Input: 15},
+		"claude-opus-4-5": {Input: 5},
+	}
+
+	tests := []struct {
+		model string
+		want  float64
+		found bool
+	}{
+		{"claude-opus-4-1-20250805", 15, true},
+		{"claude-opus-4-5-20251101", 5, true},
+		{"claude-opus-4", 15, true},
+		{"gpt-5-codex", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := prices.Lookup(tt.model)
+		if ok != tt.found || got.Input != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v; want input %v, %v", tt.model, got, ok, tt.want, tt.found)
+		}
+	}
+}
+
+func TestPricesWith(t *testing.T) {
+	base := Prices{"gpt-5": {Input: 1.25}, "o3": {Input: 2}}
+	merged := base.With(Prices{"gpt-5": {Input: 1}, "my-model": {Input: 3}})
+
+	if merged["gpt-5"].Input != 1 || merged["o3"].Input != 2 || merged["my-model"].Input != 3 {
+		t.Errorf("With() = %v, want overrides applied on top of the base", merged)
+	}
+	if base["gpt-5"].Input != 1.25 {
+		t.Error("With() should not modify the receiver")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	prices := Prices{"claude-sonnet-4": {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75}}
+	records := []Record{
+		{Model: "claude-sonnet-4-5", Tokens: Tokens{Input: 1_000_000, Output: 100_000}},
+		{Model: "claude-sonnet-4-5", Tokens: Tokens{CacheRead: 1_000_000, CacheWrite: 100_000}},
+		{Model: "unknown-model", Tokens: Tokens{Input: 500}},
+		{Model: "<synthetic>"}, // No tokens, so it doesn't make the cost a lower bound
+	}
+
+	s := prices.Summarize(records)
+	want := Tokens{Input: 1_000_500, Output: 100_000, CacheRead: 1_000_000, CacheWrite: 100_000}
+	if s.Tokens != want {
+		t.Errorf("Tokens = %+v, want %+v", s.Tokens, want)
+	}
+	if math.Abs(s.Cost-(3+1.5+0.3+0.375)) > 1e-9 {
+		t.Errorf("Cost = %v, want 5.175", s.Cost)
+	}
+	if !s.Unpriced {
+		t.Error("Unpriced = false, want true for tokens from unknown-model")
+	}
+}
+
+func TestByDay(t *testing.T) {
+	day1 := time.Date(2026, 1, 7, 10, 0, 0, 0, time.Local)
+	day2 := day1.Add(24 * time.Hour)
+	records := []Record{
+		{Time: day2, Tokens: Tokens{Input: 1}},
+		{Time: day1, Tokens: Tokens{Input: 2}},
+		{Time: day2.Add(time.Hour), Tokens: Tokens{Input: 3}},
+	}
+
+	days, groups := ByDay(records)
+	if len(days) != 2 || days[0] != "2026-01-07" || days[1] != "2026-01-08" {
+		t.Fatalf("days = %v, want [2026-01-07 2026-01-08]", days)
+	}
+	if len(groups["2026-01-08"]) != 2 {
+		t.Errorf("2026-01-08 has %d records, want 2", len(groups["2026-01-08"]))
+	}
+}
+
+func TestFormatTokens(t *testing.T) {
+	tests := map[int64]string{0: "0", 950: "950", 12_345: "12.3k", 4_100_000: "4.1M"}
+	for n, want := range tests {
+		if got := FormatTokens(n); got != want {
+			t.Errorf("FormatTokens(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		s    Summary
+		want string
+	}{
+		{Summary{Cost: 1.236}, "$1.24"},
+		{Summary{Cost: 0.5, Unpriced: true}, "$0.50+"},
+		{Summary{Unpriced: true}, "?"},
+	}
+	for _, tt := range tests {
+		if got := FormatCost(tt.s); got != tt.want {
+			t.Errorf("FormatCost(%+v) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
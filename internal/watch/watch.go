@@ -0,0 +1,220 @@
+// Package watch reports changes in the directories june reads transcripts
+// from, using the OS's file notifications (inotify on Linux, kqueue on macOS)
+// instead of polling.
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// debounce is how long events are collected into one batch. Transcripts
+	// are written in bursts, so this keeps consumers from waking per write.
+	debounce = 50 * time.Millisecond
+	// rootRetryInterval is how often roots that don't exist yet are retried.
+	rootRetryInterval = 5 * time.Second
+)
+
+// Root is a directory to watch.
+type Root struct {
+	Path string
+	// Recursive also watches subdirectories, including ones created later.
+	Recursive bool
+	// Match, if set, limits the root to the direct subdirectories whose name
+	// it accepts. Changes to other entries of Path are ignored.
+	Match func(name string) bool
+}
+
+// Batch is a set of changes collected over a short window.
+type Batch struct {
+	Paths []string // Files and directories that were written, created, removed or renamed, sorted
+}
+
+// Watcher watches a set of roots and delivers changes in batches.
+type Watcher struct {
+	fs      *fsnotify.Watcher
+	roots   []Root
+	batches chan Batch
+
+	watched map[string]watchedDir // Only accessed by run, after New
+}
+
+// watchedDir is a directory being watched and where it sits under its root.
+type watchedDir struct {
+	root  *Root
+	depth int // 0 for the root itself
+}
+
+// New starts watching roots. Roots that don't exist yet are picked up once
+// they're created.
+func New(roots []Root) (*Watcher, error) {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		fs:      fs,
+		roots:   roots,
+		batches: make(chan Batch),
+		watched: make(map[string]watchedDir),
+	}
+	w.addMissingRoots()
+	go w.run()
+	return w, nil
+}
+
+// Batches returns the channel changes are delivered on. It is closed when the
+// watcher is closed.
+func (w *Watcher) Batches() <-chan Batch {
+	return w.batches
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+func (w *Watcher) run() {
+	defer close(w.batches)
+
+	retry := time.NewTicker(rootRetryInterval)
+	defer retry.Stop()
+
+	pending := make(map[string]bool)
+	var flush <-chan time.Time
+	var out chan Batch // Set once a batch is ready to be delivered
+
+	for {
+		var batch Batch
+		if out != nil {
+			batch = newBatch(pending)
+		}
+
+		select {
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if !w.relevant(ev.Name) || ev.Op == fsnotify.Chmod {
+				continue
+			}
+			if ev.Has(fsnotify.Create) {
+				w.addCreated(ev.Name)
+			}
+			if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+				w.forget(ev.Name)
+			}
+			pending[ev.Name] = true
+			if flush == nil && out == nil {
+				flush = time.After(debounce)
+			}
+		case _, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			// Usually a queue overflow; events were lost, so report the roots
+			for _, r := range w.roots {
+				pending[r.Path] = true
+			}
+			if flush == nil && out == nil {
+				flush = time.After(debounce)
+			}
+		case <-flush:
+			flush = nil
+			out = w.batches
+		case out <- batch:
+			out = nil
+			pending = make(map[string]bool)
+		case <-retry.C:
+			w.addMissingRoots()
+		}
+	}
+}
+
+func newBatch(paths map[string]bool) Batch {
+	b := Batch{Paths: make([]string, 0, len(paths))}
+	for p := range paths {
+		b.Paths = append(b.Paths, p)
+	}
+	sort.Strings(b.Paths)
+	return b
+}
+
+// relevant reports whether a change to path should be reported. Entries of a
+// root that its Match rejects are not.
+func (w *Watcher) relevant(path string) bool {
+	parent, ok := w.watched[filepath.Dir(path)]
+	if !ok {
+		return true
+	}
+	return parent.depth > 0 || parent.root.Match == nil || parent.root.Match(filepath.Base(path))
+}
+
+// addMissingRoots starts watching roots that aren't watched yet.
+func (w *Watcher) addMissingRoots() {
+	for i := range w.roots {
+		root := &w.roots[i]
+		if _, ok := w.watched[root.Path]; !ok {
+			w.addTree(root.Path, root, 0)
+		}
+	}
+}
+
+// addCreated watches a directory created inside a watched one, if its root
+// is recursive.
+func (w *Watcher) addCreated(path string) {
+	parent, ok := w.watched[filepath.Dir(path)]
+	if !ok || !parent.root.Recursive {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		w.addTree(path, parent.root, parent.depth+1)
+	}
+}
+
+// forget stops watching a removed or renamed directory and everything below
+// it, so a root that is recreated later gets watched again.
+func (w *Watcher) forget(path string) {
+	for dir := range w.watched {
+		if dir == path || strings.HasPrefix(dir, path+string(filepath.Separator)) {
+			_ = w.fs.Remove(dir)
+			delete(w.watched, dir)
+		}
+	}
+}
+
+// addTree watches dir and, for recursive roots, its subdirectories. Errors
+// are ignored: the directory may be gone already, or not exist yet.
+func (w *Watcher) addTree(dir string, root *Root, depth int) {
+	if _, ok := w.watched[dir]; ok {
+		return
+	}
+	if err := w.fs.Add(dir); err != nil {
+		return
+	}
+	w.watched[dir] = watchedDir{root: root, depth: depth}
+
+	if !root.Recursive {
+		return
+	}
+	// Subdirectories may have been created before the watch was added
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if depth == 0 && root.Match != nil && !root.Match(e.Name()) {
+			continue
+		}
+		w.addTree(filepath.Join(dir, e.Name()), root, depth+1)
+	}
+}
@@ -0,0 +1,131 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestWatcher(t *testing.T, roots ...Root) *Watcher {
+	t.Helper()
+	w, err := New(roots)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+// waitForPath waits until a batch containing path arrives.
+func waitForPath(t *testing.T, w *Watcher, path string) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case b := <-w.Batches():
+			if slices.Contains(b.Paths, path) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no change reported for %s", path)
+		}
+	}
+}
+
+// expectNoBatch fails if a batch arrives within a short window.
+func expectNoBatch(t *testing.T, w *Watcher) {
+	t.Helper()
+	select {
+	case b := <-w.Batches():
+		t.Fatalf("unexpected batch: %v", b.Paths)
+	case <-time.After(4 * debounce):
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_ReportsWrites(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "session.jsonl")
+	writeFile(t, file, "{}\n")
+
+	w := newTestWatcher(t, Root{Path: dir})
+
+	writeFile(t, file, "{}\n{}\n")
+	waitForPath(t, w, file)
+}
+
+func TestWatcher_WatchesNewSubdirectories(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWatcher(t, Root{Path: dir, Recursive: true})
+
+	// e.g. a Claude session's subagents dir, or Codex's YYYY/MM/DD
+	sub := filepath.Join(dir, "session", "subagents")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	waitForPath(t, w, filepath.Join(dir, "session"))
+
+	// Give the watcher a moment to add the nested directory
+	time.Sleep(2 * debounce)
+	file := filepath.Join(sub, "agent-1.jsonl")
+	writeFile(t, file, "{}\n")
+	waitForPath(t, w, file)
+}
+
+func TestWatcher_MatchFiltersRootEntries(t *testing.T) {
+	dir := t.TempDir()
+	related := filepath.Join(dir, "-code-june")
+	if err := os.Mkdir(related, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newTestWatcher(t, Root{
+		Path:      dir,
+		Recursive: true,
+		Match:     func(name string) bool { return strings.HasPrefix(name, "-code-june") },
+	})
+
+	if err := os.Mkdir(filepath.Join(dir, "-code-other"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectNoBatch(t, w)
+
+	file := filepath.Join(related, "agent-1.jsonl")
+	writeFile(t, file, "{}\n")
+	waitForPath(t, w, file)
+}
+
+func TestWatcher_MissingRootIsNotFatal(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWatcher(t, Root{Path: filepath.Join(dir, "missing")}, Root{Path: dir})
+
+	file := filepath.Join(dir, "june.db")
+	writeFile(t, file, "x")
+	waitForPath(t, w, file)
+}
+
+func TestWatcher_CloseClosesBatches(t *testing.T) {
+	w, err := New([]Root{{Path: t.TempDir()}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	w.Close()
+
+	select {
+	case _, ok := <-w.Batches():
+		if ok {
+			t.Error("expected Batches to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Batches not closed after Close")
+	}
+}